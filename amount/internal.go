@@ -0,0 +1,47 @@
+package amount
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/stellar/go/xdr"
+)
+
+var (
+	minInt64 = big.NewInt(math.MinInt64)
+	maxInt64 = big.NewInt(math.MaxInt64)
+)
+
+// divide returns n/d rounded using `r`.  `d` must not be zero.
+func divide(n, d *big.Int, r Rounding) (xdr.Int64, error) {
+	var q, m big.Int
+	q.QuoRem(n, d, &m)
+
+	if m.Sign() != 0 {
+		// the direction away from zero
+		away := int64(n.Sign() * d.Sign())
+
+		switch r {
+		case RoundUp:
+			q.Add(&q, big.NewInt(away))
+		case RoundHalfEven:
+			var twice big.Int
+			twice.Abs(&m)
+			twice.Lsh(&twice, 1)
+
+			var abs big.Int
+			abs.Abs(d)
+
+			cmp := twice.Cmp(&abs)
+			if cmp > 0 || (cmp == 0 && q.Bit(0) == 1) {
+				q.Add(&q, big.NewInt(away))
+			}
+		}
+	}
+
+	if q.Cmp(minInt64) < 0 || q.Cmp(maxInt64) > 0 {
+		return xdr.Int64(0), ErrOverflow
+	}
+
+	return xdr.Int64(q.Int64()), nil
+}
@@ -10,6 +10,7 @@
 package amount
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -21,6 +22,33 @@ import (
 // for fractional values, thus One is 10 million (10^7)
 const One = 10000000
 
+var (
+	// ErrOverflow is returned when the result of an operation does not fit
+	// within a 64-bit signed integer.
+	ErrOverflow = errors.New("amount overflow")
+
+	// ErrDivisionByZero is returned when dividing by zero.
+	ErrDivisionByZero = errors.New("division by zero")
+)
+
+// Rounding selects how the result of an operation that cannot be represented
+// exactly in stroops is rounded.
+type Rounding int
+
+const (
+	// RoundDown rounds towards zero, discarding any remainder.  This is how
+	// stellar-core rounds amounts received when crossing offers.
+	RoundDown Rounding = iota
+
+	// RoundUp rounds away from zero whenever there is a remainder.  This is
+	// how stellar-core rounds amounts that must be paid when crossing offers.
+	RoundUp
+
+	// RoundHalfEven rounds to the nearest value, and to the even value when
+	// the result is exactly halfway between two values.
+	RoundHalfEven
+)
+
 // MustParse is the panicking version of Parse
 func MustParse(v string) xdr.Int64 {
 	ret, err := Parse(v)
@@ -62,3 +90,64 @@ func String(v xdr.Int64) string {
 
 	return r.FloatString(7)
 }
+
+// ParseRounded parses `v` like Parse, but rounds any digits beyond the seventh
+// fractional digit using `r`.
+func ParseRounded(v string, r Rounding) (xdr.Int64, error) {
+	var f big.Rat
+
+	_, ok := f.SetString(v)
+	if !ok {
+		return xdr.Int64(0), fmt.Errorf("cannot parse amount: %s", v)
+	}
+
+	var n big.Int
+	n.Mul(f.Num(), big.NewInt(One))
+
+	return divide(&n, f.Denom(), r)
+}
+
+// Add returns the sum of `a` and `b`, or ErrOverflow.
+func Add(a, b xdr.Int64) (xdr.Int64, error) {
+	c := a + b
+	if (c > a) != (b > 0) {
+		return xdr.Int64(0), ErrOverflow
+	}
+	return c, nil
+}
+
+// Sub returns the difference of `a` and `b`, or ErrOverflow.
+func Sub(a, b xdr.Int64) (xdr.Int64, error) {
+	c := a - b
+	if (c < a) != (b > 0) {
+		return xdr.Int64(0), ErrOverflow
+	}
+	return c, nil
+}
+
+// Mul returns the product of the amounts `a` and `b`, rounded to stroops using
+// `r`.  For example, multiplying 2.5 (25000000) by 0.5 (5000000) returns 1.25
+// (12500000).
+func Mul(a, b xdr.Int64, r Rounding) (xdr.Int64, error) {
+	return MulDiv(int64(a), int64(b), One, r)
+}
+
+// Div returns the quotient of the amounts `a` and `b`, rounded to stroops using
+// `r`.
+func Div(a, b xdr.Int64, r Rounding) (xdr.Int64, error) {
+	return MulDiv(int64(a), One, int64(b), r)
+}
+
+// MulDiv returns a*b/c rounded using `r`.  The intermediate product is computed
+// without overflow, like stellar-core's bigDivide, so only a result that does
+// not fit within 64 bits returns ErrOverflow.
+func MulDiv(a, b, c int64, r Rounding) (xdr.Int64, error) {
+	if c == 0 {
+		return xdr.Int64(0), ErrDivisionByZero
+	}
+
+	var n big.Int
+	n.Mul(big.NewInt(a), big.NewInt(b))
+
+	return divide(&n, big.NewInt(c), r)
+}
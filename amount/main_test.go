@@ -1,6 +1,7 @@
 package amount_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stellar/go/amount"
@@ -39,3 +40,80 @@ func TestString(t *testing.T) {
 		}
 	}
 }
+
+func TestParseRounded(t *testing.T) {
+	cases := []struct {
+		S        string
+		Rounding amount.Rounding
+		I        xdr.Int64
+	}{
+		{"1.00000005", amount.RoundDown, 10000000},
+		{"1.00000005", amount.RoundUp, 10000001},
+		{"1.00000005", amount.RoundHalfEven, 10000000},
+		{"1.00000015", amount.RoundHalfEven, 10000002},
+		{"1.00000016", amount.RoundHalfEven, 10000002},
+		{"-1.00000005", amount.RoundDown, -10000000},
+		{"-1.00000005", amount.RoundUp, -10000001},
+		{"-1.00000015", amount.RoundHalfEven, -10000002},
+		{"922337203685.4775807", amount.RoundDown, 9223372036854775807},
+	}
+
+	for _, c := range cases {
+		o, err := amount.ParseRounded(c.S, c.Rounding)
+		if err != nil {
+			t.Errorf("Couldn't parse %s: %v+", c.S, err)
+			continue
+		}
+
+		if o != c.I {
+			t.Errorf("%s parsed to %d, not %d", c.S, o, c.I)
+		}
+	}
+
+	_, err := amount.ParseRounded("922337203685.4775808", amount.RoundDown)
+	if err != amount.ErrOverflow {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	const max = xdr.Int64(math.MaxInt64)
+	const min = xdr.Int64(math.MinInt64)
+
+	cases := []struct {
+		Name string
+		Fn   func() (xdr.Int64, error)
+		I    xdr.Int64
+		Err  error
+	}{
+		{"add", func() (xdr.Int64, error) { return amount.Add(1, 2) }, 3, nil},
+		{"add negative", func() (xdr.Int64, error) { return amount.Add(1, -2) }, -1, nil},
+		{"add overflow", func() (xdr.Int64, error) { return amount.Add(max, 1) }, 0, amount.ErrOverflow},
+		{"add underflow", func() (xdr.Int64, error) { return amount.Add(min, -1) }, 0, amount.ErrOverflow},
+		{"sub", func() (xdr.Int64, error) { return amount.Sub(1, 2) }, -1, nil},
+		{"sub overflow", func() (xdr.Int64, error) { return amount.Sub(max, -1) }, 0, amount.ErrOverflow},
+		{"sub underflow", func() (xdr.Int64, error) { return amount.Sub(min, 1) }, 0, amount.ErrOverflow},
+		{"mul", func() (xdr.Int64, error) { return amount.Mul(25000000, 5000000, amount.RoundDown) }, 12500000, nil},
+		{"mul down", func() (xdr.Int64, error) { return amount.Mul(1, 5000000, amount.RoundDown) }, 0, nil},
+		{"mul up", func() (xdr.Int64, error) { return amount.Mul(1, 5000000, amount.RoundUp) }, 1, nil},
+		{"mul half even", func() (xdr.Int64, error) { return amount.Mul(3, 5000000, amount.RoundHalfEven) }, 2, nil},
+		{"mul overflow", func() (xdr.Int64, error) { return amount.Mul(max, 2*amount.One, amount.RoundDown) }, 0, amount.ErrOverflow},
+		{"mul large", func() (xdr.Int64, error) { return amount.Mul(max, amount.One, amount.RoundDown) }, max, nil},
+		{"div", func() (xdr.Int64, error) { return amount.Div(10000000, 30000000, amount.RoundDown) }, 3333333, nil},
+		{"div up", func() (xdr.Int64, error) { return amount.Div(10000000, 30000000, amount.RoundUp) }, 3333334, nil},
+		{"div by zero", func() (xdr.Int64, error) { return amount.Div(1, 0, amount.RoundDown) }, 0, amount.ErrDivisionByZero},
+		{"muldiv", func() (xdr.Int64, error) { return amount.MulDiv(int64(max), 3, 4, amount.RoundDown) }, 6917529027641081855, nil},
+	}
+
+	for _, c := range cases {
+		o, err := c.Fn()
+		if err != c.Err {
+			t.Errorf("%s: expected error %v, got %v", c.Name, c.Err, err)
+			continue
+		}
+
+		if o != c.I {
+			t.Errorf("%s: got %d, not %d", c.Name, o, c.I)
+		}
+	}
+}
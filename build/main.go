@@ -10,9 +10,12 @@ package build
 
 import (
 	"math"
+	"time"
 
 	"github.com/stellar/go/amount"
+	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
 
@@ -20,6 +23,10 @@ const (
 	// MemoTextMaxLength represents the maximum number of bytes a valid memo of
 	// type "MEMO_TEXT" can be.
 	MemoTextMaxLength = 28
+
+	// HashXPreimageMaxLength represents the maximum number of bytes a hash(x)
+	// preimage can be and still be revealed in a transaction signature.
+	HashXPreimageMaxLength = 64
 )
 
 var (
@@ -41,6 +48,11 @@ var (
 	DefaultNetwork = Network{}
 )
 
+// ErrMultipleSigners is the error recorded by a SetOptionsBuilder when more
+// than one of its mutators updates a signer, as a set_options operation can
+// only update a single signer.
+var ErrMultipleSigners = errors.New("only one signer may be updated per set_options operation")
+
 // Amount is a mutator capable of setting the amount
 type Amount string
 
@@ -95,6 +107,17 @@ type Destination struct {
 	AddressOrSeed string
 }
 
+// Fee is a mutator that sets the total fee, in stroops, paid by a transaction.
+// Without it, the Defaults mutator charges the base fee for each operation.
+type Fee uint32
+
+// HashXSigner is a mutator capable of adding, updating and deleting a hash(x)
+// signer on an account.  The signer key is the SHA-256 hash of Preimage.
+type HashXSigner struct {
+	Preimage []byte
+	Weight   uint32
+}
+
 // InflationDest is a mutator capable of setting the inflation destination
 type InflationDest string
 
@@ -164,6 +187,15 @@ func PayWith(sendAsset Asset, maxAmount string) PayWithPath {
 	}
 }
 
+// PreAuthTxSigner is a mutator capable of adding, updating and deleting a
+// pre-authorized transaction signer on an account.  The signer key is the hash
+// of Tx, so Tx must be fully built (including its sequence number and network)
+// before this mutator is applied.
+type PreAuthTxSigner struct {
+	Tx     *TransactionBuilder
+	Weight uint32
+}
+
 // Price is a mutator that sets price on offer operations
 type Price string
 
@@ -191,6 +223,20 @@ type Sign struct {
 	Seed string
 }
 
+// SignWith is a mutator that contributes a signature of the provided envelope's
+// transaction using the configured keypair.  Unlike Sign, the keypair's seed
+// need not be held in process memory: any keypair.KP that can sign, such as
+// one backed by a remote signing service, may be used.
+type SignWith struct {
+	Signer keypair.KP
+}
+
+// SignHashX is a mutator that contributes a hash(x) signature to the provided
+// envelope by revealing the preimage of a hash(x) signer.
+type SignHashX struct {
+	Preimage []byte
+}
+
 // SetFlag is a mutator capable of setting account flags
 type SetFlag int32
 
@@ -217,6 +263,19 @@ type Thresholds struct {
 	High   *uint32
 }
 
+// Timebounds is a mutator that sets the window of time, in unix seconds,
+// during which a transaction is valid.  A MaxTime of zero means the
+// transaction has no upper bound.
+type Timebounds struct {
+	MinTime uint64
+	MaxTime uint64
+}
+
+// Timeout is a mutator that sets a transaction's max time to the provided
+// duration from the moment the mutator is applied.  Any min time already set
+// on the transaction is preserved.
+type Timeout time.Duration
+
 // Trustor is a mutator capable of setting the trustor on
 // allow_trust operation.
 type Trustor struct {
@@ -1,6 +1,8 @@
 package build
 
 import (
+	"github.com/stellar/go/hash"
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
@@ -31,6 +33,10 @@ func (b *SetOptionsBuilder) Mutate(muts ...interface{}) {
 		var err error
 		switch mut := m.(type) {
 		case SetOptionsMutator:
+			if isSignerMutator(mut) && b.SO.Signer != nil {
+				err = ErrMultipleSigners
+				break
+			}
 			err = mut.MutateSetOptions(&b.SO)
 		case OperationMutator:
 			err = mut.MutateOperation(&b.O)
@@ -114,6 +120,78 @@ func (m Signer) MutateTransaction(t *TransactionBuilder) error {
 	return mutateTransactionBuilder(t, m)
 }
 
+// AddPreAuthTxSigner creates PreAuthTxSigner mutator that adds the hash of the
+// provided transaction as a signer of the account
+func AddPreAuthTxSigner(tx *TransactionBuilder, weight uint32) PreAuthTxSigner {
+	return PreAuthTxSigner{tx, weight}
+}
+
+// RemovePreAuthTxSigner creates PreAuthTxSigner mutator that removes the hash
+// of the provided transaction from the account's signers
+func RemovePreAuthTxSigner(tx *TransactionBuilder) PreAuthTxSigner {
+	return PreAuthTxSigner{tx, 0}
+}
+
+// MutateSetOptions for PreAuthTxSigner sets the SetOptionsOp's signer field
+func (m PreAuthTxSigner) MutateSetOptions(o *xdr.SetOptionsOp) error {
+	if m.Tx == nil {
+		return errors.New("pre-authorized transaction is nil")
+	}
+
+	if m.Tx.Err != nil {
+		return errors.Wrap(m.Tx.Err, "pre-authorized transaction is invalid")
+	}
+
+	txHash, err := m.Tx.Hash()
+	if err != nil {
+		return errors.Wrap(err, "failed to hash pre-authorized transaction")
+	}
+
+	address, err := strkey.Encode(strkey.VersionByteHashTx, txHash[:])
+	if err != nil {
+		return errors.Wrap(err, "failed to encode pre-authorized transaction hash")
+	}
+
+	return Signer{address, m.Weight}.MutateSetOptions(o)
+}
+
+// MutateTransaction for PreAuthTxSigner allows creating an operation using a single mutator
+func (m PreAuthTxSigner) MutateTransaction(t *TransactionBuilder) error {
+	return mutateTransactionBuilder(t, m)
+}
+
+// AddHashXSigner creates HashXSigner mutator that adds the hash of the provided
+// preimage as a signer of the account
+func AddHashXSigner(preimage []byte, weight uint32) HashXSigner {
+	return HashXSigner{preimage, weight}
+}
+
+// RemoveHashXSigner creates HashXSigner mutator that removes the hash of the
+// provided preimage from the account's signers
+func RemoveHashXSigner(preimage []byte) HashXSigner {
+	return HashXSigner{preimage, 0}
+}
+
+// MutateSetOptions for HashXSigner sets the SetOptionsOp's signer field
+func (m HashXSigner) MutateSetOptions(o *xdr.SetOptionsOp) error {
+	if len(m.Preimage) > HashXPreimageMaxLength {
+		return errors.New("hash(x) preimage too long; over 64 bytes")
+	}
+
+	x := hash.Hash(m.Preimage)
+	address, err := strkey.Encode(strkey.VersionByteHashX, x[:])
+	if err != nil {
+		return errors.Wrap(err, "failed to encode hash(x)")
+	}
+
+	return Signer{address, m.Weight}.MutateSetOptions(o)
+}
+
+// MutateTransaction for HashXSigner allows creating an operation using a single mutator
+func (m HashXSigner) MutateTransaction(t *TransactionBuilder) error {
+	return mutateTransactionBuilder(t, m)
+}
+
 // SetThresholds creates Thresholds mutator
 func SetThresholds(low, medium, high uint32) Thresholds {
 	return Thresholds{
@@ -244,6 +322,14 @@ func isFlagValid(flag xdr.AccountFlags) bool {
 	return true
 }
 
+func isSignerMutator(m SetOptionsMutator) bool {
+	switch m.(type) {
+	case Signer, *Signer, PreAuthTxSigner, *PreAuthTxSigner, HashXSigner, *HashXSigner:
+		return true
+	}
+	return false
+}
+
 func mutateTransactionBuilder(t *TransactionBuilder, m SetOptionsMutator) error {
 	builder := SetOptions(m)
 	if builder.Err != nil {
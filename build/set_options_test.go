@@ -1,6 +1,7 @@
 package build
 
 import (
+	"crypto/sha256"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
@@ -186,6 +187,107 @@ var _ = Describe("SetOptionsBuilder Mutators", func() {
 		})
 	})
 
+	Describe("PreAuthTxSigner", func() {
+		var tx *TransactionBuilder
+
+		BeforeEach(func() {
+			tx = Transaction(
+				SourceAccount{address},
+				Sequence{2},
+				TestNetwork,
+				Inflation(),
+			)
+		})
+
+		Context("adding a signer", func() {
+			BeforeEach(func() { mut = AddPreAuthTxSigner(tx, 1) })
+
+			It("succeeds", func() {
+				Expect(subject.Err).NotTo(HaveOccurred())
+			})
+
+			It("sets the signer to the hash of the transaction", func() {
+				hash, err := tx.Hash()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(subject.SO.Signer.Key.Type).To(Equal(xdr.SignerKeyTypeSignerKeyTypeHashTx))
+				Expect(subject.SO.Signer.Key.MustHashTx()).To(Equal(xdr.Uint256(hash)))
+				Expect(subject.SO.Signer.Weight).To(Equal(xdr.Uint32(1)))
+			})
+		})
+
+		Context("removing a signer", func() {
+			BeforeEach(func() { mut = RemovePreAuthTxSigner(tx) })
+
+			It("sets the signer weight to zero", func() {
+				Expect(subject.Err).NotTo(HaveOccurred())
+				Expect(subject.SO.Signer.Key.Type).To(Equal(xdr.SignerKeyTypeSignerKeyTypeHashTx))
+				Expect(subject.SO.Signer.Weight).To(Equal(xdr.Uint32(0)))
+			})
+		})
+
+		Context("using a transaction without a network", func() {
+			BeforeEach(func() {
+				mut = AddPreAuthTxSigner(&TransactionBuilder{TX: &xdr.Transaction{}}, 1)
+			})
+
+			It("fails", func() { Expect(subject.Err).To(HaveOccurred()) })
+		})
+
+		Context("using a nil transaction", func() {
+			BeforeEach(func() { mut = AddPreAuthTxSigner(nil, 1) })
+
+			It("fails", func() { Expect(subject.Err).To(HaveOccurred()) })
+		})
+	})
+
+	Describe("HashXSigner", func() {
+		preimage := []byte("hello world")
+
+		Context("adding a signer", func() {
+			BeforeEach(func() { mut = AddHashXSigner(preimage, 2) })
+
+			It("succeeds", func() {
+				Expect(subject.Err).NotTo(HaveOccurred())
+			})
+
+			It("sets the signer to the hash of the preimage", func() {
+				Expect(subject.SO.Signer.Key.Type).To(Equal(xdr.SignerKeyTypeSignerKeyTypeHashX))
+				Expect(subject.SO.Signer.Key.MustHashX()).To(Equal(xdr.Uint256(sha256.Sum256(preimage))))
+				Expect(subject.SO.Signer.Weight).To(Equal(xdr.Uint32(2)))
+			})
+		})
+
+		Context("removing a signer", func() {
+			BeforeEach(func() { mut = RemoveHashXSigner(preimage) })
+
+			It("sets the signer weight to zero", func() {
+				Expect(subject.Err).NotTo(HaveOccurred())
+				Expect(subject.SO.Signer.Key.Type).To(Equal(xdr.SignerKeyTypeSignerKeyTypeHashX))
+				Expect(subject.SO.Signer.Weight).To(Equal(xdr.Uint32(0)))
+			})
+		})
+
+		Context("using a preimage longer than 64 bytes", func() {
+			BeforeEach(func() { mut = AddHashXSigner(make([]byte, 65), 1) })
+
+			It("fails", func() { Expect(subject.Err).To(HaveOccurred()) })
+		})
+
+		Context("after another signer was set", func() {
+			It("fails, keeping the first signer", func() {
+				b := SetOptions(AddSigner(address, 1), AddHashXSigner(preimage, 2))
+				Expect(b.Err).To(Equal(ErrMultipleSigners))
+				Expect(b.SO.Signer.Key.Type).To(Equal(xdr.SignerKeyTypeSignerKeyTypeEd25519))
+			})
+
+			It("fails for pointers to signer mutators too", func() {
+				signer := AddSigner(address, 2)
+				b := SetOptions(AddHashXSigner(preimage, 1), &signer)
+				Expect(b.Err).To(Equal(ErrMultipleSigners))
+			})
+		})
+	})
+
 	Describe("SourceAccount", func() {
 		Context("using a valid stellar address", func() {
 			BeforeEach(func() { mut = SourceAccount{address} })
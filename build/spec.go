@@ -0,0 +1,844 @@
+package build
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/price"
+	"github.com/stellar/go/support/config"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionSpec declaratively describes a transaction.  Specs are usually
+// loaded from a TOML or JSON document using ReadTransactionSpec,
+// TransactionSpecFromTOML or TransactionSpecFromJSON, and compiled into a
+// transaction using TransactionFromSpec.
+//
+// Exactly one of Sequence and AutoSequence must be set.  Network may be
+// "public", "test" or a network passphrase, and defaults to DefaultNetwork.
+// Fee defaults to the base fee for each operation.  Signers are the seeds that
+// should be used to sign the compiled transaction, and are not used by
+// TransactionFromSpec itself.
+type TransactionSpec struct {
+	Source       string          `json:"source" toml:"source" valid:"stellar_accountid"`
+	Sequence     uint64          `json:"sequence" toml:"sequence" valid:"optional"`
+	AutoSequence bool            `json:"autosequence" toml:"autosequence" valid:"optional"`
+	Network      string          `json:"network" toml:"network" valid:"optional"`
+	Fee          uint32          `json:"fee" toml:"fee" valid:"optional"`
+	Memo         *MemoSpec       `json:"memo" toml:"memo" valid:"-"`
+	Operations   []OperationSpec `json:"operations" toml:"operations" valid:"-"`
+	Signers      []string        `json:"signers" toml:"signers" valid:"-"`
+}
+
+// MemoSpec describes the memo of a transaction.  Type is one of "none",
+// "text", "id", "hash" or "return".  Hash and return memos take a hex encoded
+// value.
+type MemoSpec struct {
+	Type  string `json:"type" toml:"type" valid:"required"`
+	Value string `json:"value" toml:"value" valid:"optional"`
+}
+
+// OperationSpec describes a single operation of a transaction.  Exactly one of
+// the operation fields must be set.
+type OperationSpec struct {
+	Source             string                  `json:"source" toml:"source" valid:"optional"`
+	CreateAccount      *CreateAccountSpec      `json:"create_account" toml:"create_account" valid:"-"`
+	Payment            *PaymentSpec            `json:"payment" toml:"payment" valid:"-"`
+	PathPayment        *PathPaymentSpec        `json:"path_payment" toml:"path_payment" valid:"-"`
+	ManageOffer        *ManageOfferSpec        `json:"manage_offer" toml:"manage_offer" valid:"-"`
+	CreatePassiveOffer *CreatePassiveOfferSpec `json:"create_passive_offer" toml:"create_passive_offer" valid:"-"`
+	SetOptions         *SetOptionsSpec         `json:"set_options" toml:"set_options" valid:"-"`
+	ChangeTrust        *ChangeTrustSpec        `json:"change_trust" toml:"change_trust" valid:"-"`
+	AllowTrust         *AllowTrustSpec         `json:"allow_trust" toml:"allow_trust" valid:"-"`
+	AccountMerge       *AccountMergeSpec       `json:"account_merge" toml:"account_merge" valid:"-"`
+	Inflation          *InflationSpec          `json:"inflation" toml:"inflation" valid:"-"`
+	ManageData         *ManageDataSpec         `json:"manage_data" toml:"manage_data" valid:"-"`
+}
+
+// AssetSpec describes an asset.  Either Native must be set, or both Code and
+// Issuer.
+type AssetSpec struct {
+	Code   string `json:"code" toml:"code" valid:"optional"`
+	Issuer string `json:"issuer" toml:"issuer" valid:"optional"`
+	Native bool   `json:"native" toml:"native" valid:"optional"`
+}
+
+// CreateAccountSpec describes a create_account operation.
+type CreateAccountSpec struct {
+	Destination     string `json:"destination" toml:"destination" valid:"stellar_accountid"`
+	StartingBalance string `json:"starting_balance" toml:"starting_balance" valid:"required"`
+}
+
+// PaymentSpec describes a payment operation.
+type PaymentSpec struct {
+	Destination string    `json:"destination" toml:"destination" valid:"stellar_accountid"`
+	Asset       AssetSpec `json:"asset" toml:"asset" valid:"-"`
+	Amount      string    `json:"amount" toml:"amount" valid:"required"`
+}
+
+// PathPaymentSpec describes a path_payment operation.
+type PathPaymentSpec struct {
+	Destination string      `json:"destination" toml:"destination" valid:"stellar_accountid"`
+	SendAsset   AssetSpec   `json:"send_asset" toml:"send_asset" valid:"-"`
+	SendMax     string      `json:"send_max" toml:"send_max" valid:"required"`
+	DestAsset   AssetSpec   `json:"dest_asset" toml:"dest_asset" valid:"-"`
+	DestAmount  string      `json:"dest_amount" toml:"dest_amount" valid:"required"`
+	Path        []AssetSpec `json:"path" toml:"path" valid:"-"`
+}
+
+// ManageOfferSpec describes a manage_offer operation.  An OfferID of zero
+// creates a new offer.
+type ManageOfferSpec struct {
+	Selling AssetSpec `json:"selling" toml:"selling" valid:"-"`
+	Buying  AssetSpec `json:"buying" toml:"buying" valid:"-"`
+	Amount  string    `json:"amount" toml:"amount" valid:"required"`
+	Price   string    `json:"price" toml:"price" valid:"required"`
+	OfferID uint64    `json:"offer_id" toml:"offer_id" valid:"optional"`
+}
+
+// CreatePassiveOfferSpec describes a create_passive_offer operation.
+type CreatePassiveOfferSpec struct {
+	Selling AssetSpec `json:"selling" toml:"selling" valid:"-"`
+	Buying  AssetSpec `json:"buying" toml:"buying" valid:"-"`
+	Amount  string    `json:"amount" toml:"amount" valid:"required"`
+	Price   string    `json:"price" toml:"price" valid:"required"`
+}
+
+// SetOptionsSpec describes a set_options operation.  Flags are named
+// "auth_required", "auth_revocable" or "auth_immutable".
+type SetOptionsSpec struct {
+	InflationDest   string      `json:"inflation_dest" toml:"inflation_dest" valid:"optional"`
+	SetFlags        []string    `json:"set_flags" toml:"set_flags" valid:"-"`
+	ClearFlags      []string    `json:"clear_flags" toml:"clear_flags" valid:"-"`
+	MasterWeight    *uint32     `json:"master_weight" toml:"master_weight" valid:"-"`
+	LowThreshold    *uint32     `json:"low_threshold" toml:"low_threshold" valid:"-"`
+	MediumThreshold *uint32     `json:"medium_threshold" toml:"medium_threshold" valid:"-"`
+	HighThreshold   *uint32     `json:"high_threshold" toml:"high_threshold" valid:"-"`
+	HomeDomain      *string     `json:"home_domain" toml:"home_domain" valid:"-"`
+	Signer          *SignerSpec `json:"signer" toml:"signer" valid:"-"`
+}
+
+// SignerSpec describes a signer added, updated or (with a weight of zero)
+// removed by a set_options operation.  Address may be an account, pre-auth tx
+// or hash(x) strkey.
+type SignerSpec struct {
+	Address string `json:"address" toml:"address" valid:"required"`
+	Weight  uint32 `json:"weight" toml:"weight" valid:"optional"`
+}
+
+// ChangeTrustSpec describes a change_trust operation.  Limit defaults to the
+// maximum limit.
+type ChangeTrustSpec struct {
+	Asset AssetSpec `json:"asset" toml:"asset" valid:"-"`
+	Limit string    `json:"limit" toml:"limit" valid:"optional"`
+}
+
+// AllowTrustSpec describes an allow_trust operation.
+type AllowTrustSpec struct {
+	Trustor   string `json:"trustor" toml:"trustor" valid:"stellar_accountid"`
+	Code      string `json:"code" toml:"code" valid:"required"`
+	Authorize bool   `json:"authorize" toml:"authorize" valid:"optional"`
+}
+
+// AccountMergeSpec describes an account_merge operation.
+type AccountMergeSpec struct {
+	Destination string `json:"destination" toml:"destination" valid:"stellar_accountid"`
+}
+
+// InflationSpec describes an inflation operation.
+type InflationSpec struct{}
+
+// ManageDataSpec describes a manage_data operation.  A nil Value removes the
+// entry named Name.
+type ManageDataSpec struct {
+	Name  string  `json:"name" toml:"name" valid:"required"`
+	Value *string `json:"value" toml:"value" valid:"-"`
+}
+
+// ReadTransactionSpec loads the transaction spec stored at `path`.  Files with
+// a ".json" extension are decoded as JSON, all others as TOML.
+func ReadTransactionSpec(path string) (*TransactionSpec, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		return TransactionSpecFromJSON(bs)
+	}
+
+	return TransactionSpecFromTOML(string(bs))
+}
+
+// TransactionSpecFromTOML decodes and validates the transaction spec in the
+// provided TOML document.  Invalid fields are reported using a
+// *config.InvalidConfigError, keyed by the path of each field.
+func TransactionSpecFromTOML(content string) (*TransactionSpec, error) {
+	var spec TransactionSpec
+	err := config.Decode(content, &spec)
+
+	// config.Decode only validates the top level of the spec, so any validation
+	// failure is superseded by the one reported by spec.Validate below.
+	if _, ok := err.(*config.InvalidConfigError); err != nil && !ok {
+		return nil, err
+	}
+
+	err = spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
+
+// TransactionSpecFromJSON decodes and validates the transaction spec in the
+// provided JSON document.  Invalid fields are reported using a
+// *config.InvalidConfigError, keyed by the path of each field.
+func TransactionSpecFromJSON(content []byte) (*TransactionSpec, error) {
+	var raw interface{}
+	err := json.Unmarshal(content, &raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode json failed")
+	}
+
+	unknown := unknownJSONFields("", raw, reflect.TypeOf(TransactionSpec{}))
+	if len(unknown) > 0 {
+		return nil, errors.New("Unknown fields: " + fmt.Sprintf("%+v", unknown))
+	}
+
+	var spec TransactionSpec
+	err = json.Unmarshal(content, &spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode json failed")
+	}
+
+	err = spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
+
+// Validate checks every field of the spec, returning a
+// *config.InvalidConfigError keyed by the path of each invalid field.
+func (spec *TransactionSpec) Validate() error {
+	v := specValidator{fields: map[string]string{}}
+	v.validate("", spec)
+
+	switch {
+	case spec.Sequence == 0 && !spec.AutoSequence:
+		v.invalid("sequence", "either sequence or autosequence is required")
+	case spec.Sequence != 0 && spec.AutoSequence:
+		v.invalid("sequence", "sequence and autosequence cannot both be set")
+	}
+
+	if spec.Memo != nil {
+		v.validateMemo("memo", *spec.Memo)
+	}
+
+	if len(spec.Operations) == 0 {
+		v.invalid("operations", "at least one operation is required")
+	}
+
+	for i, op := range spec.Operations {
+		v.validateOperation(fmt.Sprintf("operations[%d]", i), op)
+	}
+
+	for i, seed := range spec.Signers {
+		v.validateSeed(fmt.Sprintf("signers[%d]", i), seed)
+	}
+
+	if len(v.fields) > 0 {
+		return &config.InvalidConfigError{InvalidFields: v.fields}
+	}
+
+	return nil
+}
+
+// Mutators compiles the spec into the mutators that build the transaction it
+// describes.  `seq` is used to load the transaction's sequence when the spec
+// sets AutoSequence, and may be nil otherwise.
+func (spec *TransactionSpec) Mutators(seq SequenceProvider) ([]TransactionMutator, error) {
+	err := spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	muts := []TransactionMutator{
+		SourceAccount{spec.Source},
+	}
+
+	switch spec.Network {
+	case "":
+		// leave the default network to the Defaults mutator
+	case "public":
+		muts = append(muts, PublicNetwork)
+	case "test":
+		muts = append(muts, TestNetwork)
+	default:
+		muts = append(muts, Network{spec.Network})
+	}
+
+	if spec.AutoSequence {
+		if seq == nil {
+			return nil, errors.New("autosequence requires a sequence provider")
+		}
+		muts = append(muts, AutoSequence{seq})
+	} else {
+		muts = append(muts, Sequence{spec.Sequence})
+	}
+
+	if spec.Memo != nil {
+		memo, err := spec.Memo.mutator()
+		if err != nil {
+			return nil, errors.Wrap(err, "memo")
+		}
+		if memo != nil {
+			muts = append(muts, memo)
+		}
+	}
+
+	for i, op := range spec.Operations {
+		mut, err := op.mutator()
+		if err != nil {
+			return nil, errors.Wrapf(err, "operations[%d]", i)
+		}
+		muts = append(muts, mut)
+	}
+
+	if spec.Fee != 0 {
+		muts = append(muts, Fee(spec.Fee))
+	}
+
+	return muts, nil
+}
+
+// TransactionFromSpec compiles the provided spec and builds the transaction it
+// describes.  Compilation failures are recorded in the returned builder's Err
+// field.  See TransactionSpec.Mutators for the meaning of `seq`.
+func TransactionFromSpec(spec *TransactionSpec, seq SequenceProvider) *TransactionBuilder {
+	muts, err := spec.Mutators(seq)
+	if err != nil {
+		return &TransactionBuilder{
+			TX:  &xdr.Transaction{},
+			Err: err,
+		}
+	}
+
+	return Transaction(muts...)
+}
+
+func (m MemoSpec) mutator() (TransactionMutator, error) {
+	switch m.Type {
+	case "none":
+		return nil, nil
+	case "text":
+		return MemoText{m.Value}, nil
+	case "id":
+		id, err := strconv.ParseUint(m.Value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return MemoID{id}, nil
+	case "hash", "return":
+		hash, err := parseHash(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		if m.Type == "hash" {
+			return MemoHash{hash}, nil
+		}
+		return MemoReturn{hash}, nil
+	default:
+		return nil, errors.New("unknown memo type")
+	}
+}
+
+func (op OperationSpec) mutator() (TransactionMutator, error) {
+	var muts []interface{}
+	if op.Source != "" {
+		muts = append(muts, SourceAccount{op.Source})
+	}
+
+	switch {
+	case op.CreateAccount != nil:
+		s := op.CreateAccount
+		muts = append(muts,
+			Destination{s.Destination},
+			NativeAmount{s.StartingBalance},
+		)
+		return CreateAccount(muts...), nil
+	case op.Payment != nil:
+		s := op.Payment
+		muts = append(muts,
+			Destination{s.Destination},
+			s.Asset.amount(s.Amount),
+		)
+		return Payment(muts...), nil
+	case op.PathPayment != nil:
+		s := op.PathPayment
+		pay := PayWith(s.SendAsset.asset(), s.SendMax)
+		for _, asset := range s.Path {
+			pay = pay.Through(asset.asset())
+		}
+		muts = append(muts,
+			Destination{s.Destination},
+			s.DestAsset.amount(s.DestAmount),
+			pay,
+		)
+		return Payment(muts...), nil
+	case op.ManageOffer != nil:
+		s := op.ManageOffer
+		rate := Rate{
+			Selling: s.Selling.asset(),
+			Buying:  s.Buying.asset(),
+			Price:   Price(s.Price),
+		}
+		muts = append(muts, rate, Amount(s.Amount))
+		if s.OfferID != 0 {
+			muts = append(muts, OfferID(s.OfferID))
+		}
+		return ManageOffer(false, muts...), nil
+	case op.CreatePassiveOffer != nil:
+		s := op.CreatePassiveOffer
+		rate := Rate{
+			Selling: s.Selling.asset(),
+			Buying:  s.Buying.asset(),
+			Price:   Price(s.Price),
+		}
+		muts = append(muts, rate, Amount(s.Amount))
+		return ManageOffer(true, muts...), nil
+	case op.SetOptions != nil:
+		s := op.SetOptions
+		if s.InflationDest != "" {
+			muts = append(muts, InflationDest(s.InflationDest))
+		}
+		for _, name := range s.SetFlags {
+			muts = append(muts, SetFlag(accountFlags[name]))
+		}
+		for _, name := range s.ClearFlags {
+			muts = append(muts, ClearFlag(accountFlags[name]))
+		}
+		if s.MasterWeight != nil {
+			muts = append(muts, MasterWeight(*s.MasterWeight))
+		}
+		if s.LowThreshold != nil || s.MediumThreshold != nil || s.HighThreshold != nil {
+			muts = append(muts, Thresholds{
+				Low:    s.LowThreshold,
+				Medium: s.MediumThreshold,
+				High:   s.HighThreshold,
+			})
+		}
+		if s.HomeDomain != nil {
+			muts = append(muts, HomeDomain(*s.HomeDomain))
+		}
+		if s.Signer != nil {
+			muts = append(muts, Signer{s.Signer.Address, s.Signer.Weight})
+		}
+		return SetOptions(muts...), nil
+	case op.ChangeTrust != nil:
+		s := op.ChangeTrust
+		limit := MaxLimit
+		if s.Limit != "" {
+			limit = Limit(s.Limit)
+		}
+		muts = append(muts, s.Asset.asset(), limit)
+		return ChangeTrust(muts...), nil
+	case op.AllowTrust != nil:
+		s := op.AllowTrust
+		muts = append(muts,
+			Trustor{s.Trustor},
+			AllowTrustAsset{s.Code},
+			Authorize{s.Authorize},
+		)
+		return AllowTrust(muts...), nil
+	case op.AccountMerge != nil:
+		muts = append(muts, Destination{op.AccountMerge.Destination})
+		return AccountMerge(muts...), nil
+	case op.Inflation != nil:
+		return Inflation(muts...), nil
+	case op.ManageData != nil:
+		s := op.ManageData
+		if s.Value == nil {
+			return ClearData(s.Name, muts...), nil
+		}
+		return SetData(s.Name, []byte(*s.Value), muts...), nil
+	default:
+		return nil, errors.New("no operation set")
+	}
+}
+
+func (a AssetSpec) asset() Asset {
+	if a.Native {
+		return NativeAsset()
+	}
+	return CreditAsset(a.Code, a.Issuer)
+}
+
+// amount returns the payment mutator that sends `value` of the asset.
+func (a AssetSpec) amount(value string) PaymentMutator {
+	if a.Native {
+		return NativeAmount{value}
+	}
+	return CreditAmount{a.Code, a.Issuer, value}
+}
+
+var accountFlags = map[string]xdr.AccountFlags{
+	"auth_required":  xdr.AccountFlagsAuthRequiredFlag,
+	"auth_revocable": xdr.AccountFlagsAuthRevocableFlag,
+	"auth_immutable": xdr.AccountFlagsAuthImmutableFlag,
+}
+
+// specValidator accumulates the invalid fields of a spec, keyed by path.
+type specValidator struct {
+	fields map[string]string
+}
+
+func (v *specValidator) invalid(path, msg string) {
+	if _, ok := v.fields[path]; !ok {
+		v.fields[path] = msg
+	}
+}
+
+// validate runs the struct tag validations for `dest`, recording failures
+// under `prefix`.
+func (v *specValidator) validate(prefix string, dest interface{}) {
+	err := config.Validate(dest)
+	if err == nil {
+		return
+	}
+
+	verr, ok := err.(*config.InvalidConfigError)
+	if !ok {
+		v.invalid(prefix, err.Error())
+		return
+	}
+
+	t := reflect.TypeOf(dest)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for field, msg := range verr.InvalidFields {
+		v.invalid(joinSpecPath(prefix, specFieldName(t, field)), msg)
+	}
+}
+
+func (v *specValidator) validateAccountID(path, address string) {
+	v.validateValue(path, &struct {
+		Value string `valid:"stellar_accountid"`
+	}{address})
+}
+
+func (v *specValidator) validateSeed(path, seed string) {
+	v.validateValue(path, &struct {
+		Value string `valid:"stellar_seed"`
+	}{seed})
+}
+
+// validateValue runs the struct tag validations for `dest`, a struct wrapping
+// a single value, recording any failure under `path`.
+func (v *specValidator) validateValue(path string, dest interface{}) {
+	err := config.Validate(dest)
+	if err == nil {
+		return
+	}
+
+	verr, ok := err.(*config.InvalidConfigError)
+	if !ok {
+		v.invalid(path, err.Error())
+		return
+	}
+
+	for _, msg := range verr.InvalidFields {
+		v.invalid(path, msg)
+	}
+}
+
+func (v *specValidator) validateAmount(path, value string) {
+	if value == "" {
+		return
+	}
+
+	_, err := amount.Parse(value)
+	if err != nil {
+		v.invalid(path, "invalid amount")
+	}
+}
+
+func (v *specValidator) validatePrice(path, value string) {
+	if value == "" {
+		return
+	}
+
+	_, err := price.Parse(value)
+	if err != nil {
+		v.invalid(path, "invalid price")
+	}
+}
+
+func (v *specValidator) validateAsset(path string, a AssetSpec) {
+	if a.Native {
+		if a.Code != "" || a.Issuer != "" {
+			v.invalid(path, "native assets have no code or issuer")
+		}
+		return
+	}
+
+	if len(a.Code) < 1 || len(a.Code) > 12 {
+		v.invalid(joinSpecPath(path, "code"), "asset code must be 1 to 12 characters")
+	}
+
+	v.validateAccountID(joinSpecPath(path, "issuer"), a.Issuer)
+}
+
+func (v *specValidator) validateMemo(path string, m MemoSpec) {
+	v.validate(path, &m)
+
+	switch m.Type {
+	case "none":
+		if m.Value != "" {
+			v.invalid(joinSpecPath(path, "value"), "none memos have no value")
+		}
+	case "text":
+		if len(m.Value) > MemoTextMaxLength {
+			v.invalid(joinSpecPath(path, "value"), "text memos must be 28 bytes or less")
+		}
+	case "id":
+		_, err := strconv.ParseUint(m.Value, 10, 64)
+		if err != nil {
+			v.invalid(joinSpecPath(path, "value"), "id memos must be an unsigned 64-bit integer")
+		}
+	case "hash", "return":
+		_, err := parseHash(m.Value)
+		if err != nil {
+			v.invalid(joinSpecPath(path, "value"), "hash memos must be 32 hex encoded bytes")
+		}
+	case "":
+		// reported by the struct validation
+	default:
+		v.invalid(joinSpecPath(path, "type"), "unknown memo type")
+	}
+}
+
+func (v *specValidator) validateOperation(path string, op OperationSpec) {
+	if op.Source != "" {
+		v.validateAccountID(joinSpecPath(path, "source"), op.Source)
+	}
+
+	set := 0
+	body := func(name string, present bool) string {
+		if present {
+			set++
+		}
+		return joinSpecPath(path, name)
+	}
+
+	if p := body("create_account", op.CreateAccount != nil); op.CreateAccount != nil {
+		v.validate(p, op.CreateAccount)
+		v.validateAmount(joinSpecPath(p, "starting_balance"), op.CreateAccount.StartingBalance)
+	}
+
+	if p := body("payment", op.Payment != nil); op.Payment != nil {
+		v.validate(p, op.Payment)
+		v.validateAsset(joinSpecPath(p, "asset"), op.Payment.Asset)
+		v.validateAmount(joinSpecPath(p, "amount"), op.Payment.Amount)
+	}
+
+	if p := body("path_payment", op.PathPayment != nil); op.PathPayment != nil {
+		s := op.PathPayment
+		v.validate(p, s)
+		v.validateAsset(joinSpecPath(p, "send_asset"), s.SendAsset)
+		v.validateAmount(joinSpecPath(p, "send_max"), s.SendMax)
+		v.validateAsset(joinSpecPath(p, "dest_asset"), s.DestAsset)
+		v.validateAmount(joinSpecPath(p, "dest_amount"), s.DestAmount)
+		for i, asset := range s.Path {
+			v.validateAsset(fmt.Sprintf("%s[%d]", joinSpecPath(p, "path"), i), asset)
+		}
+	}
+
+	if p := body("manage_offer", op.ManageOffer != nil); op.ManageOffer != nil {
+		s := op.ManageOffer
+		v.validate(p, s)
+		v.validateAsset(joinSpecPath(p, "selling"), s.Selling)
+		v.validateAsset(joinSpecPath(p, "buying"), s.Buying)
+		v.validateAmount(joinSpecPath(p, "amount"), s.Amount)
+		v.validatePrice(joinSpecPath(p, "price"), s.Price)
+	}
+
+	if p := body("create_passive_offer", op.CreatePassiveOffer != nil); op.CreatePassiveOffer != nil {
+		s := op.CreatePassiveOffer
+		v.validate(p, s)
+		v.validateAsset(joinSpecPath(p, "selling"), s.Selling)
+		v.validateAsset(joinSpecPath(p, "buying"), s.Buying)
+		v.validateAmount(joinSpecPath(p, "amount"), s.Amount)
+		v.validatePrice(joinSpecPath(p, "price"), s.Price)
+	}
+
+	if p := body("set_options", op.SetOptions != nil); op.SetOptions != nil {
+		s := op.SetOptions
+		v.validate(p, s)
+		if s.InflationDest != "" {
+			v.validateAccountID(joinSpecPath(p, "inflation_dest"), s.InflationDest)
+		}
+		for i, name := range s.SetFlags {
+			if _, ok := accountFlags[name]; !ok {
+				v.invalid(fmt.Sprintf("%s[%d]", joinSpecPath(p, "set_flags"), i), "unknown flag")
+			}
+		}
+		for i, name := range s.ClearFlags {
+			if _, ok := accountFlags[name]; !ok {
+				v.invalid(fmt.Sprintf("%s[%d]", joinSpecPath(p, "clear_flags"), i), "unknown flag")
+			}
+		}
+		if s.Signer != nil {
+			sp := joinSpecPath(p, "signer")
+			v.validate(sp, s.Signer)
+
+			var key xdr.SignerKey
+			if s.Signer.Address != "" && key.SetAddress(s.Signer.Address) != nil {
+				v.invalid(joinSpecPath(sp, "address"), "invalid signer key")
+			}
+		}
+	}
+
+	if p := body("change_trust", op.ChangeTrust != nil); op.ChangeTrust != nil {
+		s := op.ChangeTrust
+		v.validate(p, s)
+		if s.Asset.Native {
+			v.invalid(joinSpecPath(p, "asset"), "cannot trust the native asset")
+		} else {
+			v.validateAsset(joinSpecPath(p, "asset"), s.Asset)
+		}
+		v.validateAmount(joinSpecPath(p, "limit"), s.Limit)
+	}
+
+	if p := body("allow_trust", op.AllowTrust != nil); op.AllowTrust != nil {
+		v.validate(p, op.AllowTrust)
+		if len(op.AllowTrust.Code) > 12 {
+			v.invalid(joinSpecPath(p, "code"), "asset code must be 1 to 12 characters")
+		}
+	}
+
+	if p := body("account_merge", op.AccountMerge != nil); op.AccountMerge != nil {
+		v.validate(p, op.AccountMerge)
+	}
+
+	body("inflation", op.Inflation != nil)
+
+	if p := body("manage_data", op.ManageData != nil); op.ManageData != nil {
+		v.validate(p, op.ManageData)
+		if len(op.ManageData.Name) > 64 {
+			v.invalid(joinSpecPath(p, "name"), "name must be 64 bytes or less")
+		}
+		if op.ManageData.Value != nil && len(*op.ManageData.Value) > 64 {
+			v.invalid(joinSpecPath(p, "value"), "value must be 64 bytes or less")
+		}
+	}
+
+	switch {
+	case set == 0:
+		v.invalid(path, "no operation set")
+	case set > 1:
+		v.invalid(path, "only one operation may be set")
+	}
+}
+
+// specFieldName returns the spec name (its json tag) of the field of `t`
+// identified by `name`, which may be either the go or spec name of the field.
+func specFieldName(t reflect.Type, name string) string {
+	if t.Kind() != reflect.Struct {
+		return name
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Name == name || tag == name {
+			if tag == "" {
+				return f.Name
+			}
+			return tag
+		}
+	}
+
+	return name
+}
+
+func joinSpecPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// unknownJSONFields returns the path of every object key within the decoded
+// json value `raw` that has no corresponding field in `t`.
+func unknownJSONFields(prefix string, raw interface{}, t reflect.Type) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var unknown []string
+	switch raw := raw.(type) {
+	case map[string]interface{}:
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+
+		for key, value := range raw {
+			f, ok := jsonField(t, key)
+			if !ok {
+				unknown = append(unknown, joinSpecPath(prefix, key))
+				continue
+			}
+			unknown = append(unknown, unknownJSONFields(joinSpecPath(prefix, key), value, f.Type)...)
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice {
+			return nil
+		}
+
+		for i, value := range raw {
+			path := fmt.Sprintf("%s[%d]", prefix, i)
+			unknown = append(unknown, unknownJSONFields(path, value, t.Elem())...)
+		}
+	}
+
+	return unknown
+}
+
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if strings.Split(f.Tag.Get("json"), ",")[0] == key {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func parseHash(value string) (xdr.Hash, error) {
+	var hash xdr.Hash
+
+	raw, err := hex.DecodeString(value)
+	if err != nil {
+		return hash, err
+	}
+
+	if len(raw) != len(hash) {
+		return hash, errors.New("hash must be 32 bytes")
+	}
+
+	copy(hash[:], raw)
+	return hash, nil
+}
@@ -0,0 +1,176 @@
+package build
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stellar/go/support/config"
+	"github.com/stellar/go/xdr"
+)
+
+var _ = Describe("TransactionSpec", func() {
+	var (
+		source = "GAXEMCEXBERNSRXOEKD4JAIKVECIXQCENHEBRVSPX2TTYZPMNEDSQCNQ"
+		other  = "GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA"
+	)
+
+	invalidFields := func(err error) map[string]string {
+		Expect(err).To(BeAssignableToTypeOf(&config.InvalidConfigError{}))
+		return err.(*config.InvalidConfigError).InvalidFields
+	}
+
+	Describe("TransactionSpecFromTOML", func() {
+		It("compiles a treasury payment", func() {
+			spec, err := TransactionSpecFromTOML(`
+source = "` + source + `"
+sequence = 5
+network = "test"
+fee = 300
+
+[memo]
+type = "text"
+value = "payroll"
+
+[[operations]]
+[operations.payment]
+destination = "` + other + `"
+amount = "20"
+asset = { code = "USD", issuer = "` + source + `" }
+
+[[operations]]
+source = "` + other + `"
+[operations.change_trust]
+asset = { code = "EUR", issuer = "` + source + `" }
+
+[[operations]]
+[operations.inflation]
+`)
+			Expect(err).NotTo(HaveOccurred())
+
+			subject := TransactionFromSpec(spec, nil)
+			Expect(subject.Err).NotTo(HaveOccurred())
+
+			expected := Transaction(
+				SourceAccount{source},
+				TestNetwork,
+				Sequence{5},
+				MemoText{"payroll"},
+				Payment(
+					Destination{other},
+					CreditAmount{"USD", source, "20"},
+				),
+				ChangeTrust(
+					SourceAccount{other},
+					CreditAsset("EUR", source),
+					MaxLimit,
+				),
+				Inflation(),
+				Fee(300),
+			)
+			Expect(expected.Err).NotTo(HaveOccurred())
+
+			Expect(subject.NetworkPassphrase).To(Equal(expected.NetworkPassphrase))
+			Expect(*subject.TX).To(Equal(*expected.TX))
+		})
+
+		It("rejects unknown fields", func() {
+			_, err := TransactionSpecFromTOML(`
+source = "` + source + `"
+sequence = 5
+colour = "blue"
+`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("colour"))
+		})
+
+		It("reports invalid fields by path", func() {
+			_, err := TransactionSpecFromTOML(`
+source = "foo"
+
+[[operations]]
+[operations.payment]
+destination = "` + other + `"
+amount = "lots"
+asset = { native = true }
+
+[[operations]]
+source = "bar"
+[operations.create_account]
+starting_balance = "10"
+`)
+			fields := invalidFields(err)
+			Expect(fields).To(HaveKey("source"))
+			Expect(fields).To(HaveKey("sequence"))
+			Expect(fields).To(HaveKey("operations[0].payment.amount"))
+			Expect(fields).To(HaveKey("operations[1].source"))
+			Expect(fields).To(HaveKey("operations[1].create_account.destination"))
+			Expect(fields).NotTo(HaveKey("operations[0].payment.destination"))
+		})
+	})
+
+	Describe("TransactionSpecFromJSON", func() {
+		It("compiles an autosequenced trustline setup", func() {
+			spec, err := TransactionSpecFromJSON([]byte(`{
+				"source": "` + source + `",
+				"autosequence": true,
+				"signers": ["SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"],
+				"operations": [
+					{"allow_trust": {"trustor": "` + other + `", "code": "USD", "authorize": true}},
+					{"set_options": {"set_flags": ["auth_required"], "home_domain": "example.com"}}
+				]
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			seq := &MockSequenceProvider{
+				Data: map[string]xdr.SequenceNumber{source: 2},
+			}
+
+			subject := TransactionFromSpec(spec, seq)
+			Expect(subject.Err).NotTo(HaveOccurred())
+			Expect(subject.TX.SeqNum).To(BeEquivalentTo(3))
+			Expect(subject.TX.Fee).To(BeEquivalentTo(200))
+			Expect(subject.TX.Operations).To(HaveLen(2))
+			Expect(subject.TX.Operations[0].Body.Type).To(Equal(xdr.OperationTypeAllowTrust))
+			Expect(subject.TX.Operations[1].Body.MustSetOptionsOp().HomeDomain).ToNot(BeNil())
+		})
+
+		It("rejects unknown nested fields", func() {
+			_, err := TransactionSpecFromJSON([]byte(`{
+				"source": "` + source + `",
+				"sequence": 1,
+				"operations": [{"inflation": {}, "paymnet": {}}]
+			}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("operations[0].paymnet"))
+		})
+
+		It("validates memos, signers and operation counts", func() {
+			_, err := TransactionSpecFromJSON([]byte(`{
+				"source": "` + source + `",
+				"sequence": 1,
+				"autosequence": true,
+				"memo": {"type": "hash", "value": "abcd"},
+				"signers": ["` + other + `"],
+				"operations": [{}, {"inflation": {}, "account_merge": {"destination": "` + other + `"}}]
+			}`))
+			fields := invalidFields(err)
+			Expect(fields).To(HaveKey("sequence"))
+			Expect(fields).To(HaveKey("memo.value"))
+			Expect(fields).To(HaveKey("signers[0]"))
+			Expect(fields).To(HaveKey("operations[0]"))
+			Expect(fields).To(HaveKey("operations[1]"))
+		})
+	})
+
+	Describe("Mutators", func() {
+		It("requires a sequence provider for autosequence", func() {
+			spec := &TransactionSpec{
+				Source:       source,
+				AutoSequence: true,
+				Operations:   []OperationSpec{{Inflation: &InflationSpec{}}},
+			}
+
+			subject := TransactionFromSpec(spec, nil)
+			Expect(subject.Err).To(HaveOccurred())
+		})
+	})
+})
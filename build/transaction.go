@@ -2,7 +2,9 @@ package build
 
 import (
 	"encoding/hex"
+	"time"
 
+	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
 	"github.com/stellar/go/xdr"
 	"github.com/stellar/go/support/errors"
@@ -17,6 +19,33 @@ func Transaction(muts ...TransactionMutator) (result *TransactionBuilder) {
 	return
 }
 
+// TransactionFromXDR creates a new TransactionBuilder whose transaction is a
+// copy of the provided xdr transaction, and then applies the provided mutators
+// to it.  Use this to continue building a transaction that was previously
+// encoded.
+func TransactionFromXDR(tx xdr.Transaction, muts ...TransactionMutator) (result *TransactionBuilder) {
+	result = &TransactionBuilder{TX: copyTransaction(tx)}
+	result.Mutate(muts...)
+	result.Mutate(Defaults{})
+	return
+}
+
+// TransactionFromBase64 decodes the provided base64-encoded xdr transaction and
+// then behaves like TransactionFromXDR.  A decoding failure is recorded in the
+// returned builder's Err field.
+func TransactionFromBase64(txB64 string, muts ...TransactionMutator) *TransactionBuilder {
+	var tx xdr.Transaction
+	err := xdr.SafeUnmarshalBase64(txB64, &tx)
+	if err != nil {
+		return &TransactionBuilder{
+			TX:  &xdr.Transaction{},
+			Err: errors.Wrap(err, "decode transaction failed"),
+		}
+	}
+
+	return TransactionFromXDR(tx, muts...)
+}
+
 // TransactionMutator is a interface that wraps the
 // MutateTransaction operation.  types may implement this interface to
 // specify how they modify an xdr.Transaction object
@@ -29,14 +58,21 @@ type TransactionBuilder struct {
 	TX                *xdr.Transaction
 	NetworkPassphrase string
 	Err               error
+
+	// explicitFee is set once the fee is set using the Fee mutator
+	explicitFee bool
 }
 
-// Mutate applies the provided TransactionMutators to this builder's transaction
+// Mutate applies the provided TransactionMutators to this builder's
+// transaction.  Unless set using the Fee mutator, a fee already set on the
+// transaction is raised to the base fee of its operations when operations are
+// added, such that transactions reconstructed from xdr remain valid.
 func (b *TransactionBuilder) Mutate(muts ...TransactionMutator) {
 	if b.TX == nil {
 		b.TX = &xdr.Transaction{}
 	}
 
+	ops := len(b.TX.Operations)
 	for _, m := range muts {
 		err := m.MutateTransaction(b)
 		if err != nil {
@@ -44,6 +80,13 @@ func (b *TransactionBuilder) Mutate(muts ...TransactionMutator) {
 			return
 		}
 	}
+
+	if b.explicitFee || b.TX.Fee == 0 || len(b.TX.Operations) <= ops {
+		return
+	}
+	if fee := xdr.Uint32(100 * len(b.TX.Operations)); b.TX.Fee < fee {
+		b.TX.Fee = fee
+	}
 }
 
 // Hash returns the hash of this builder's transaction.
@@ -74,6 +117,32 @@ func (b *TransactionBuilder) Sign(signers ...string) (result TransactionEnvelope
 	return
 }
 
+// SignWith returns an new TransactionEnvelopeBuilder using this builder's
+// transaction as the basis and with signatures of that transaction from the
+// provided keypairs.  Local seeds may be mixed with other signers by parsing
+// them with keypair.Parse.
+func (b *TransactionBuilder) SignWith(signers ...keypair.KP) (result TransactionEnvelopeBuilder) {
+	result.Mutate(b)
+
+	for _, s := range signers {
+		result.Mutate(SignWith{s})
+	}
+
+	return
+}
+
+// copyTransaction returns a copy of tx that shares no slices with it, such that
+// mutating the copy leaves the original untouched.
+func copyTransaction(tx xdr.Transaction) *xdr.Transaction {
+	if tx.TimeBounds != nil {
+		tb := *tx.TimeBounds
+		tx.TimeBounds = &tb
+	}
+
+	tx.Operations = append([]xdr.Operation(nil), tx.Operations...)
+	return &tx
+}
+
 // ------------------------------------------------------------
 //
 //   Mutator implementations
@@ -162,6 +231,13 @@ func (m Defaults) MutateTransaction(o *TransactionBuilder) error {
 	return nil
 }
 
+// MutateTransaction for Fee sets the fee on the transaction.
+func (m Fee) MutateTransaction(o *TransactionBuilder) error {
+	o.TX.Fee = xdr.Uint32(m)
+	o.explicitFee = true
+	return nil
+}
+
 // MutateTransaction for InflationBuilder causes the underylying
 // InflationOp to be added to the operation list for the provided
 // transaction
@@ -284,3 +360,32 @@ func (m Sequence) MutateTransaction(o *TransactionBuilder) error {
 func (m SourceAccount) MutateTransaction(o *TransactionBuilder) error {
 	return setAccountId(m.AddressOrSeed, &o.TX.SourceAccount)
 }
+
+// MutateTransaction for Timebounds sets the TimeBounds on the transaction.
+func (m Timebounds) MutateTransaction(o *TransactionBuilder) error {
+	if m.MaxTime != 0 && m.MinTime >= m.MaxTime {
+		return errors.New("invalid timebounds: min time must be before max time")
+	}
+
+	o.TX.TimeBounds = &xdr.TimeBounds{
+		MinTime: xdr.Uint64(m.MinTime),
+		MaxTime: xdr.Uint64(m.MaxTime),
+	}
+	return nil
+}
+
+// MutateTransaction for Timeout sets the max time of the transaction's
+// TimeBounds to the current time plus the timeout.
+func (m Timeout) MutateTransaction(o *TransactionBuilder) error {
+	if m <= 0 {
+		return errors.New("invalid timeout: must be positive")
+	}
+
+	var min uint64
+	if o.TX.TimeBounds != nil {
+		min = uint64(o.TX.TimeBounds.MinTime)
+	}
+
+	max := time.Now().Add(time.Duration(m)).Unix()
+	return Timebounds{MinTime: min, MaxTime: uint64(max)}.MutateTransaction(o)
+}
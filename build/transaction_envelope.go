@@ -5,11 +5,53 @@ import (
 	"encoding/base64"
 	"fmt"
 
+	"github.com/stellar/go/hash"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
 
+// TransactionEnvelopeFromXDR creates a new TransactionEnvelopeBuilder whose
+// envelope is a copy of the provided xdr envelope, including its transaction
+// and any signatures already present, and then applies the provided mutators to
+// it.  Signatures added by the mutators, such as Sign, are made for `network`.
+// Changing the transaction afterwards, for example by adding operations using
+// MutateTX, drops the signatures already present as they are no longer valid.
+func TransactionEnvelopeFromXDR(
+	txe xdr.TransactionEnvelope,
+	network Network,
+	muts ...TransactionEnvelopeMutator,
+) (result *TransactionEnvelopeBuilder) {
+	txe.Tx = *copyTransaction(txe.Tx)
+	txe.Signatures = append([]xdr.DecoratedSignature(nil), txe.Signatures...)
+
+	result = &TransactionEnvelopeBuilder{E: &txe}
+	result.Init()
+	result.MutateTX(network)
+	result.Mutate(muts...)
+	return
+}
+
+// TransactionEnvelopeFromBase64 decodes the provided base64-encoded xdr
+// envelope and then behaves like TransactionEnvelopeFromXDR.  A decoding
+// failure is recorded in the returned builder's Err field.
+func TransactionEnvelopeFromBase64(
+	txeB64 string,
+	network Network,
+	muts ...TransactionEnvelopeMutator,
+) *TransactionEnvelopeBuilder {
+	var txe xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(txeB64, &txe)
+	if err != nil {
+		result := &TransactionEnvelopeBuilder{}
+		result.Init()
+		result.Err = errors.Wrap(err, "decode envelope failed")
+		return result
+	}
+
+	return TransactionEnvelopeFromXDR(txe, network, muts...)
+}
+
 // TransactionEnvelopeMutator is a interface that wraps the
 // MutateTransactionEnvelope operation.  types may implement this interface to
 // specify how they modify an xdr.TransactionEnvelope object
@@ -50,7 +92,8 @@ func (b *TransactionEnvelopeBuilder) Mutate(muts ...TransactionEnvelopeMutator)
 }
 
 // MutateTX runs Mutate on the underlying transaction using the provided
-// mutators.
+// mutators.  The signatures of the envelope are dropped when the transaction
+// changes, as they are no longer valid.
 func (b *TransactionEnvelopeBuilder) MutateTX(muts ...TransactionMutator) {
 	b.Init()
 
@@ -58,8 +101,28 @@ func (b *TransactionEnvelopeBuilder) MutateTX(muts ...TransactionMutator) {
 		return
 	}
 
+	var before string
+	if len(b.E.Signatures) > 0 {
+		before, b.Err = xdr.MarshalBase64(b.E.Tx)
+		if b.Err != nil {
+			return
+		}
+	}
+
 	b.child.Mutate(muts...)
 	b.Err = b.child.Err
+	if b.Err != nil || before == "" {
+		return
+	}
+
+	after, err := xdr.MarshalBase64(b.E.Tx)
+	if err != nil {
+		b.Err = err
+		return
+	}
+	if after != before {
+		b.E.Signatures = nil
+	}
 }
 
 // Bytes encodes the builder's underlying envelope to XDR
@@ -96,17 +159,26 @@ func (b *TransactionEnvelopeBuilder) Base64() (string, error) {
 
 // MutateTransactionEnvelope adds a signature to the provided envelope
 func (m Sign) MutateTransactionEnvelope(txe *TransactionEnvelopeBuilder) error {
-	hash, err := txe.child.Hash()
+	kp, err := keypair.Parse(m.Seed)
 	if err != nil {
-		return errors.Wrap(err, "hash tx failed")
+		return errors.Wrap(err, "parse failed")
 	}
 
-	kp, err := keypair.Parse(m.Seed)
+	return SignWith{kp}.MutateTransactionEnvelope(txe)
+}
+
+// MutateTransactionEnvelope adds a signature to the provided envelope
+func (m SignWith) MutateTransactionEnvelope(txe *TransactionEnvelopeBuilder) error {
+	if m.Signer == nil {
+		return errors.New("signer is nil")
+	}
+
+	hash, err := txe.child.Hash()
 	if err != nil {
-		return errors.Wrap(err, "parse failed")
+		return errors.Wrap(err, "hash tx failed")
 	}
 
-	sig, err := kp.SignDecorated(hash[:])
+	sig, err := m.Signer.SignDecorated(hash[:])
 	if err != nil {
 		return errors.Wrap(err, "sign tx failed")
 	}
@@ -115,6 +187,25 @@ func (m Sign) MutateTransactionEnvelope(txe *TransactionEnvelopeBuilder) error {
 	return nil
 }
 
+// MutateTransactionEnvelope adds a hash(x) signature to the provided envelope
+func (m SignHashX) MutateTransactionEnvelope(txe *TransactionEnvelopeBuilder) error {
+	if len(m.Preimage) > HashXPreimageMaxLength {
+		return errors.New("hash(x) preimage too long; over 64 bytes")
+	}
+
+	// the hint of a hash(x) signature is the last four bytes of the signer key,
+	// i.e. of the hash of the preimage.
+	x := hash.Hash(m.Preimage)
+	var hint xdr.SignatureHint
+	copy(hint[:], x[28:])
+
+	txe.E.Signatures = append(txe.E.Signatures, xdr.DecoratedSignature{
+		Hint:      hint,
+		Signature: xdr.Signature(m.Preimage),
+	})
+	return nil
+}
+
 // MutateTransactionEnvelope for TransactionBuilder causes the underylying
 // transaction to be set as the provided envelope's Tx field
 func (m *TransactionBuilder) MutateTransactionEnvelope(txe *TransactionEnvelopeBuilder) error {
@@ -1,9 +1,13 @@
 package build
 
 import (
+	"crypto/sha256"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
 )
 
 var _ = Describe("TransactionEnvelope Mutators:", func() {
@@ -56,4 +60,220 @@ var _ = Describe("TransactionEnvelope Mutators:", func() {
 		})
 	})
 
+	Describe("SignWith", func() {
+		Context("with a keypair able to sign", func() {
+			BeforeEach(func() {
+				subject.MutateTX(SourceAccount{"SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"}, TestNetwork)
+				mut = SignWith{keypair.MustParse("SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H")}
+			})
+
+			It("succeeds", func() { Expect(subject.Err).NotTo(HaveOccurred()) })
+			It("adds the same signature as Sign", func() {
+				expected := TransactionEnvelopeBuilder{}
+				expected.MutateTX(SourceAccount{"SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"}, TestNetwork)
+				expected.Mutate(Sign{"SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"})
+				Expect(subject.E.Signatures).To(Equal(expected.E.Signatures))
+			})
+		})
+
+		Context("with a keypair unable to sign", func() {
+			BeforeEach(func() {
+				subject.MutateTX(TestNetwork)
+				mut = SignWith{keypair.MustParse("GAXEMCEXBERNSRXOEKD4JAIKVECIXQCENHEBRVSPX2TTYZPMNEDSQCNQ")}
+			})
+
+			It("fails", func() { Expect(subject.Err).To(HaveOccurred()) })
+		})
+
+		Context("with no keypair", func() {
+			BeforeEach(func() { mut = SignWith{} })
+
+			It("fails", func() { Expect(subject.Err).To(HaveOccurred()) })
+		})
+	})
+
+	Describe("SignHashX", func() {
+		preimage := []byte("hello world")
+
+		Context("with a valid preimage", func() {
+			BeforeEach(func() { mut = SignHashX{preimage} })
+
+			It("succeeds", func() { Expect(subject.Err).NotTo(HaveOccurred()) })
+			It("adds the preimage as a signature", func() {
+				x := sha256.Sum256(preimage)
+				Expect(subject.E.Signatures).To(HaveLen(1))
+				Expect(subject.E.Signatures[0].Signature).To(Equal(xdr.Signature(preimage)))
+				Expect(subject.E.Signatures[0].Hint[:]).To(Equal(x[28:]))
+			})
+		})
+
+		Context("with a preimage longer than 64 bytes", func() {
+			BeforeEach(func() { mut = SignHashX{make([]byte, 65)} })
+
+			It("fails", func() { Expect(subject.Err).To(HaveOccurred()) })
+		})
+	})
+
+	Describe("Base64", func() {
+		var (
+			seed   = "SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"
+			result string
+			err    error
+		)
+
+		JustBeforeEach(func() { result, err = subject.Base64() })
+
+		Context("with timebounds set", func() {
+			BeforeEach(func() {
+				mut = Transaction(
+					SourceAccount{seed},
+					Sequence{1},
+					TestNetwork,
+					Timebounds{MinTime: 1500000000, MaxTime: 1500000300},
+					Inflation(),
+				)
+			})
+
+			It("round-trips the timebounds", func() {
+				Expect(err).NotTo(HaveOccurred())
+
+				var txe xdr.TransactionEnvelope
+				Expect(xdr.SafeUnmarshalBase64(result, &txe)).To(Succeed())
+				Expect(txe.Tx.TimeBounds).ToNot(BeNil())
+				Expect(txe.Tx.TimeBounds.MinTime).To(BeEquivalentTo(1500000000))
+				Expect(txe.Tx.TimeBounds.MaxTime).To(BeEquivalentTo(1500000300))
+			})
+		})
+
+		Context("with no timebounds set", func() {
+			BeforeEach(func() {
+				mut = Transaction(SourceAccount{seed}, Sequence{1}, TestNetwork, Inflation())
+			})
+
+			It("omits the timebounds", func() {
+				Expect(err).NotTo(HaveOccurred())
+
+				var txe xdr.TransactionEnvelope
+				Expect(xdr.SafeUnmarshalBase64(result, &txe)).To(Succeed())
+				Expect(txe.Tx.TimeBounds).To(BeNil())
+			})
+		})
+
+		Context("with invalid timebounds", func() {
+			BeforeEach(func() {
+				mut = Transaction(
+					SourceAccount{seed},
+					Sequence{1},
+					Timebounds{MinTime: 1500000300, MaxTime: 1500000000},
+				)
+			})
+
+			It("fails", func() { Expect(err).To(HaveOccurred()) })
+		})
+	})
+
+	Describe("TransactionEnvelopeFromBase64", func() {
+		var (
+			seed    = "SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"
+			other   = "SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY"
+			dest    = "GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA"
+			txeB64  string
+			builder *TransactionEnvelopeBuilder
+		)
+
+		BeforeEach(func() {
+			tx := Transaction(
+				SourceAccount{seed},
+				Sequence{1},
+				TestNetwork,
+				MemoText{"hello"},
+				Payment(Destination{dest}, NativeAmount{"50"}),
+			)
+			txe := tx.Sign(seed)
+
+			var err error
+			txeB64, err = txe.Base64()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		JustBeforeEach(func() { builder = TransactionEnvelopeFromBase64(txeB64, TestNetwork) })
+
+		It("succeeds", func() { Expect(builder.Err).NotTo(HaveOccurred()) })
+
+		It("preserves the original transaction and signatures", func() {
+			Expect(builder.E.Tx.SeqNum).To(BeEquivalentTo(1))
+			Expect(builder.E.Tx.Memo.MustText()).To(Equal("hello"))
+			Expect(builder.E.Tx.Operations).To(HaveLen(1))
+			payment := builder.E.Tx.Operations[0].Body.MustPaymentOp()
+			Expect(payment.Destination.Address()).To(Equal(dest))
+			Expect(builder.E.Signatures).To(HaveLen(1))
+		})
+
+		It("re-encodes to the same envelope", func() {
+			result, err := builder.Base64()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(txeB64))
+		})
+
+		It("accepts further signatures", func() {
+			builder.Mutate(Sign{other})
+			Expect(builder.Err).NotTo(HaveOccurred())
+			Expect(builder.E.Signatures).To(HaveLen(2))
+
+			// the new signature must be made for the provided network
+			expected := Transaction(
+				SourceAccount{seed},
+				Sequence{1},
+				TestNetwork,
+				MemoText{"hello"},
+				Payment(Destination{dest}, NativeAmount{"50"}),
+			).Sign(seed, other)
+			Expect(builder.E.Signatures).To(Equal(expected.E.Signatures))
+		})
+
+		It("signs for the provided network", func() {
+			signed := TransactionEnvelopeFromBase64(txeB64, TestNetwork, Sign{other})
+			Expect(signed.Err).NotTo(HaveOccurred())
+			Expect(signed.E.Signatures).To(HaveLen(2))
+
+			public := TransactionEnvelopeFromBase64(txeB64, PublicNetwork, Sign{other})
+			Expect(public.Err).NotTo(HaveOccurred())
+			Expect(public.E.Signatures).To(HaveLen(2))
+			Expect(public.E.Signatures[1]).NotTo(Equal(signed.E.Signatures[1]))
+		})
+
+		It("accepts further transaction mutators, dropping stale signatures", func() {
+			builder.MutateTX(Payment(Destination{dest}, NativeAmount{"10"}))
+			Expect(builder.Err).NotTo(HaveOccurred())
+			Expect(builder.E.Tx.Operations).To(HaveLen(2))
+			Expect(builder.E.Tx.Fee).To(BeEquivalentTo(200))
+			Expect(builder.E.Signatures).To(BeEmpty())
+		})
+
+		It("keeps signatures when the transaction does not change", func() {
+			builder.MutateTX(TestNetwork)
+			Expect(builder.Err).NotTo(HaveOccurred())
+			Expect(builder.E.Signatures).To(HaveLen(1))
+		})
+
+		Context("with invalid base64", func() {
+			BeforeEach(func() { txeB64 = "not an envelope" })
+
+			It("fails", func() { Expect(builder.Err).To(HaveOccurred()) })
+		})
+	})
+
+	Describe("TransactionEnvelopeFromXDR", func() {
+		It("does not share state with the provided envelope", func() {
+			var txe xdr.TransactionEnvelope
+			txe.Tx.Operations = make([]xdr.Operation, 1, 10)
+
+			builder := TransactionEnvelopeFromXDR(txe, TestNetwork)
+			builder.MutateTX(Inflation())
+			Expect(builder.Err).NotTo(HaveOccurred())
+			Expect(builder.E.Tx.Operations).To(HaveLen(2))
+			Expect(txe.Tx.Operations[:2][1].Body.Type).To(Equal(xdr.OperationType(0)))
+		})
+	})
+
 })
@@ -1,6 +1,8 @@
 package build
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/stellar/go/xdr"
@@ -132,4 +134,142 @@ var _ = Describe("Transaction Mutators:", func() {
 			It("sets the sequence", func() { Expect(subject.TX.SeqNum).To(BeEquivalentTo(3)) })
 		})
 	})
+
+	Describe("Timebounds", func() {
+		BeforeEach(func() { mut = Timebounds{MinTime: 1000, MaxTime: 2000} })
+		It("succeeds", func() { Expect(subject.Err).NotTo(HaveOccurred()) })
+		It("sets the timebounds", func() {
+			Expect(subject.TX.TimeBounds).ToNot(BeNil())
+			Expect(subject.TX.TimeBounds.MinTime).To(BeEquivalentTo(1000))
+			Expect(subject.TX.TimeBounds.MaxTime).To(BeEquivalentTo(2000))
+		})
+
+		Context("with no max time", func() {
+			BeforeEach(func() { mut = Timebounds{MinTime: 1000} })
+			It("succeeds", func() { Expect(subject.Err).NotTo(HaveOccurred()) })
+			It("leaves the max time unbounded", func() {
+				Expect(subject.TX.TimeBounds.MaxTime).To(BeEquivalentTo(0))
+			})
+		})
+
+		Context("with a min time after the max time", func() {
+			BeforeEach(func() { mut = Timebounds{MinTime: 2000, MaxTime: 1000} })
+			It("fails", func() { Expect(subject.Err).To(HaveOccurred()) })
+			It("does not set the timebounds", func() { Expect(subject.TX.TimeBounds).To(BeNil()) })
+		})
+
+		Context("with a min time equal to the max time", func() {
+			BeforeEach(func() { mut = Timebounds{MinTime: 1000, MaxTime: 1000} })
+			It("fails", func() { Expect(subject.Err).To(HaveOccurred()) })
+		})
+	})
+
+	Describe("Timeout", func() {
+		var before int64
+
+		BeforeEach(func() {
+			before = time.Now().Unix()
+			mut = Timeout(5 * time.Minute)
+		})
+
+		It("succeeds", func() { Expect(subject.Err).NotTo(HaveOccurred()) })
+		It("sets the max time relative to now", func() {
+			Expect(subject.TX.TimeBounds).ToNot(BeNil())
+			Expect(subject.TX.TimeBounds.MinTime).To(BeEquivalentTo(0))
+			Expect(int64(subject.TX.TimeBounds.MaxTime)).To(BeNumerically(">=", before+300))
+			Expect(int64(subject.TX.TimeBounds.MaxTime)).To(BeNumerically("<=", time.Now().Unix()+300))
+		})
+
+		Context("with a min time already set", func() {
+			BeforeEach(func() { subject.Mutate(Timebounds{MinTime: 1000}) })
+			It("preserves the min time", func() {
+				Expect(subject.TX.TimeBounds.MinTime).To(BeEquivalentTo(1000))
+			})
+		})
+
+		Context("with a non-positive duration", func() {
+			BeforeEach(func() { mut = Timeout(0) })
+			It("fails", func() { Expect(subject.Err).To(HaveOccurred()) })
+		})
+	})
+})
+
+var _ = Describe("TransactionFromXDR", func() {
+	var (
+		source  = "GAXEMCEXBERNSRXOEKD4JAIKVECIXQCENHEBRVSPX2TTYZPMNEDSQCNQ"
+		tx      xdr.Transaction
+		subject *TransactionBuilder
+	)
+
+	BeforeEach(func() {
+		original := Transaction(
+			SourceAccount{source},
+			Sequence{5},
+			TestNetwork,
+			MemoID{42},
+			Inflation(),
+		)
+		Expect(original.Err).NotTo(HaveOccurred())
+		tx = *original.TX
+	})
+
+	JustBeforeEach(func() { subject = TransactionFromXDR(tx, TestNetwork) })
+
+	It("preserves the original transaction", func() {
+		Expect(subject.Err).NotTo(HaveOccurred())
+		Expect(*subject.TX).To(Equal(tx))
+	})
+
+	It("hashes the same as the original", func() {
+		expected, err := Transaction(
+			SourceAccount{source},
+			Sequence{5},
+			TestNetwork,
+			MemoID{42},
+			Inflation(),
+		).Hash()
+		Expect(err).NotTo(HaveOccurred())
+
+		actual, err := subject.Hash()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual).To(Equal(expected))
+	})
+
+	It("accepts further mutators without changing the original", func() {
+		subject.Mutate(Inflation())
+		Expect(subject.TX.Operations).To(HaveLen(2))
+		Expect(tx.Operations).To(HaveLen(1))
+	})
+
+	It("raises the fee when operations are added", func() {
+		subject := TransactionFromXDR(tx, TestNetwork, Inflation(), Inflation())
+		Expect(subject.Err).NotTo(HaveOccurred())
+		Expect(subject.TX.Operations).To(HaveLen(3))
+		Expect(subject.TX.Fee).To(BeEquivalentTo(300))
+
+		subject.Mutate(Inflation())
+		Expect(subject.TX.Fee).To(BeEquivalentTo(400))
+	})
+
+	It("keeps a fee set explicitly when operations are added", func() {
+		subject := TransactionFromXDR(tx, TestNetwork, Fee(150), Inflation(), Inflation())
+		Expect(subject.Err).NotTo(HaveOccurred())
+		Expect(subject.TX.Fee).To(BeEquivalentTo(150))
+	})
+
+	Describe("TransactionFromBase64", func() {
+		It("decodes the transaction", func() {
+			b64, err := xdr.MarshalBase64(tx)
+			Expect(err).NotTo(HaveOccurred())
+
+			subject := TransactionFromBase64(b64)
+			Expect(subject.Err).NotTo(HaveOccurred())
+			Expect(*subject.TX).To(Equal(tx))
+		})
+
+		It("fails with invalid input", func() {
+			subject := TransactionFromBase64("foo")
+			Expect(subject.Err).To(HaveOccurred())
+		})
+	})
 })
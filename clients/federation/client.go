@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/stellar/go/address"
 	proto "github.com/stellar/go/protocols/federation"
@@ -28,19 +29,61 @@ func (c *Client) LookupByAddress(addy string) (*proto.NameResponse, error) {
 		return nil, errors.Wrap(err, "lookup federation server failed")
 	}
 
-	url := c.url(fserv, "name", addy)
+	return c.lookupName(fserv, addy)
+}
 
-	var resp proto.NameResponse
-	err = c.getJSON(url, &resp)
-	if err != nil {
-		return nil, errors.Wrap(err, "get federation failed")
+// LookupByAddresses performs the federated lookup of every address of
+// `addresses`, like LookupByAddress, returning a result for each of them in
+// the same order.  Addresses are grouped by domain, so that the stellar.toml
+// file of each domain is only loaded once, and the addresses of at most
+// MaxConcurrentDomains domains are looked up concurrently, sending at most
+// DomainConcurrency requests at a time to each federation server.  An address
+// present more than once is only looked up once.
+func (c *Client) LookupByAddresses(addresses []string) []AddressResult {
+	var (
+		unique  = map[string]*AddressResult{}
+		domains = map[string][]*AddressResult{}
+	)
+
+	for _, addy := range addresses {
+		if _, ok := unique[addy]; ok {
+			continue
+		}
+
+		result := &AddressResult{Address: addy}
+		unique[addy] = result
+
+		_, domain, err := address.Split(addy)
+		if err != nil {
+			result.Err = errors.Wrap(err, "parse address failed")
+			continue
+		}
+		domains[domain] = append(domains[domain], result)
 	}
 
-	if resp.MemoType != "" && resp.Memo.String() == "" {
-		return nil, errors.New("Invalid federation response (memo)")
+	concurrency := c.MaxConcurrentDomains
+	if concurrency <= 0 {
+		concurrency = DefaultMaxConcurrentDomains
 	}
 
-	return &resp, nil
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for domain, results := range domains {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(domain string, results []*AddressResult) {
+			defer wg.Done()
+			c.lookupDomain(domain, results)
+			<-sem
+		}(domain, results)
+	}
+	wg.Wait()
+
+	results := make([]AddressResult, len(addresses))
+	for i, addy := range addresses {
+		results[i] = *unique[addy]
+	}
+	return results
 }
 
 // LookupByAccountID performs a federated lookup following to the stellar
@@ -73,6 +116,55 @@ func (c *Client) LookupByAccountID(aid string) (*proto.IDResponse, error) {
 	return &resp, nil
 }
 
+// lookupDomain looks up the addresses of `results`, which all belong to
+// `domain`, recording the response or error of each of them.
+func (c *Client) lookupDomain(domain string, results []*AddressResult) {
+	fserv, err := c.getFederationServer(domain)
+	if err != nil {
+		err = errors.Wrap(err, "lookup federation server failed")
+		for _, result := range results {
+			result.Err = err
+		}
+		return
+	}
+
+	concurrency := c.DomainConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultDomainConcurrency
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, result := range results {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(result *AddressResult) {
+			defer wg.Done()
+			result.Response, result.Err = c.lookupName(fserv, result.Address)
+			<-sem
+		}(result)
+	}
+	wg.Wait()
+}
+
+// lookupName performs a "name" type request of `addy` against the federation
+// server `fserv`.
+func (c *Client) lookupName(fserv, addy string) (*proto.NameResponse, error) {
+	url := c.url(fserv, "name", addy)
+
+	var resp proto.NameResponse
+	err := c.getJSON(url, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "get federation failed")
+	}
+
+	if resp.MemoType != "" && resp.Memo.String() == "" {
+		return nil, errors.New("Invalid federation response (memo)")
+	}
+
+	return &resp, nil
+}
+
 func (c *Client) getFederationServer(domain string) (string, error) {
 	stoml, err := c.StellarTOML.GetStellarToml(domain)
 	if err != nil {
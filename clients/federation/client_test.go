@@ -2,13 +2,19 @@ package federation
 
 import (
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stellar/go/clients/stellartoml"
 	"github.com/stellar/go/support/http/httptest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLookupByAddress(t *testing.T) {
@@ -120,6 +126,158 @@ func TestLookupByAddress(t *testing.T) {
 	}
 }
 
+// concurrentHTTP responds to federation requests using the queried address as
+// memo, recording the largest number of concurrent requests to each host, and
+// the largest number of hosts requested concurrently.
+type concurrentHTTP struct {
+	lock     sync.Mutex
+	active   map[string]int
+	maxSeen  map[string]int
+	hosts    int
+	maxHosts int
+}
+
+func (h *concurrentHTTP) Get(rawurl string) (*http.Response, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	h.lock.Lock()
+	h.active[u.Host]++
+	if h.active[u.Host] > h.maxSeen[u.Host] {
+		h.maxSeen[u.Host] = h.active[u.Host]
+	}
+	if h.active[u.Host] == 1 {
+		h.hosts++
+	}
+	if h.hosts > h.maxHosts {
+		h.maxHosts = h.hosts
+	}
+	h.lock.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	h.lock.Lock()
+	h.active[u.Host]--
+	if h.active[u.Host] == 0 {
+		h.hosts--
+	}
+	h.lock.Unlock()
+
+	q := u.Query().Get("q")
+	if strings.HasPrefix(q, "missing*") {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"detail": "not found"}`)),
+		}, nil
+	}
+
+	body := fmt.Sprintf(`{"stellar_address": %q, "account_id": "GASTNVNLHVR3NFO3QACMHCJT3JUSIV4NBXDHDO4VTPDTNN65W3B2766C", "memo_type": "text", "memo": %q}`, q, q)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+func TestLookupByAddresses(t *testing.T) {
+	hmock := &concurrentHTTP{active: map[string]int{}, maxSeen: map[string]int{}}
+	tomlmock := &stellartoml.MockClient{}
+	c := &Client{StellarTOML: tomlmock, HTTP: hmock, DomainConcurrency: 2}
+
+	tomlmock.On("GetStellarToml", "stellar.org").Return(&stellartoml.Response{
+		FederationServer: "https://stellar.org/federation",
+	}, nil)
+	tomlmock.On("GetStellarToml", "example.com").Return(&stellartoml.Response{
+		FederationServer: "https://fed.example.com/federation",
+	}, nil)
+	tomlmock.On("GetStellarToml", "broken.org").Return(
+		(*stellartoml.Response)(nil),
+		errors.New("toml failed"),
+	)
+
+	var addresses []string
+	for i := 0; i < 10; i++ {
+		addresses = append(addresses,
+			fmt.Sprintf("user%d*stellar.org", i),
+			fmt.Sprintf("user%d*example.com", i),
+		)
+	}
+	addresses = append(addresses,
+		"user0*stellar.org",
+		"missing*stellar.org",
+		"user0*broken.org",
+		"user1*broken.org",
+		"not an address",
+	)
+
+	results := c.LookupByAddresses(addresses)
+	require.Len(t, results, len(addresses))
+
+	for i, result := range results[:20] {
+		assert.Equal(t, addresses[i], result.Address)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "GASTNVNLHVR3NFO3QACMHCJT3JUSIV4NBXDHDO4VTPDTNN65W3B2766C", result.Response.AccountID)
+			assert.Equal(t, "text", result.Response.MemoType)
+			assert.Equal(t, addresses[i], result.Response.Memo.String())
+		}
+	}
+
+	// duplicates share the result of the first lookup
+	assert.Equal(t, results[0], results[20])
+
+	if assert.Error(t, results[21].Err) {
+		assert.Contains(t, results[21].Err.Error(), "failed with (404)")
+	}
+	for _, result := range results[22:24] {
+		if assert.Error(t, result.Err) {
+			assert.Contains(t, result.Err.Error(), "toml failed")
+		}
+	}
+	if assert.Error(t, results[24].Err) {
+		assert.Contains(t, results[24].Err.Error(), "parse address failed")
+	}
+
+	// stellar.toml files are loaded once per domain
+	tomlmock.AssertNumberOfCalls(t, "GetStellarToml", 3)
+	assert.Equal(t, 2, hmock.maxSeen["stellar.org"])
+	assert.Equal(t, 2, hmock.maxSeen["fed.example.com"])
+}
+
+func TestLookupByAddresses_MaxConcurrentDomains(t *testing.T) {
+	hmock := &concurrentHTTP{active: map[string]int{}, maxSeen: map[string]int{}}
+	tomlmock := &stellartoml.MockClient{}
+	c := &Client{
+		StellarTOML:          tomlmock,
+		HTTP:                 hmock,
+		DomainConcurrency:    2,
+		MaxConcurrentDomains: 3,
+	}
+
+	var addresses []string
+	for i := 0; i < 10; i++ {
+		domain := fmt.Sprintf("domain%d.com", i)
+		tomlmock.On("GetStellarToml", domain).Return(&stellartoml.Response{
+			FederationServer: "https://" + domain + "/federation",
+		}, nil)
+
+		for j := 0; j < 4; j++ {
+			addresses = append(addresses, fmt.Sprintf("user%d*%s", j, domain))
+		}
+	}
+
+	results := c.LookupByAddresses(addresses)
+	require.Len(t, results, len(addresses))
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+	}
+
+	assert.True(t, hmock.maxHosts <= 3, "%d domains looked up concurrently", hmock.maxHosts)
+	for host, n := range hmock.maxSeen {
+		assert.True(t, n <= 2, "%d concurrent requests to %s", n, host)
+	}
+}
+
 func TestLookupByID(t *testing.T) {
 	// HACK: until we improve our mocking scenario, this is just a smoke test.
 	// When/if it breaks, please write this test correctly.  That, or curse
@@ -5,11 +5,21 @@ import (
 
 	"github.com/stellar/go/clients/horizon"
 	"github.com/stellar/go/clients/stellartoml"
+	proto "github.com/stellar/go/protocols/federation"
 )
 
 // FederationResponseMaxSize is the maximum size of response from a federation server
 const FederationResponseMaxSize = 100 * 1024
 
+// DefaultDomainConcurrency is the number of requests LookupByAddresses sends
+// concurrently to the federation server of each domain, when the
+// DomainConcurrency of the client is zero.
+const DefaultDomainConcurrency = 4
+
+// DefaultMaxConcurrentDomains is the number of domains LookupByAddresses
+// looks up concurrently, when the MaxConcurrentDomains of the client is zero.
+const DefaultMaxConcurrentDomains = 16
+
 // DefaultTestNetClient is a default federation client for testnet
 var DefaultTestNetClient = &Client{
 	HTTP:        http.DefaultClient,
@@ -25,12 +35,32 @@ var DefaultPublicNetClient = &Client{
 }
 
 // Client represents a client that is capable of resolving a Stellar.toml file
-// using the internet.
+// using the internet.  To avoid loading the same stellar.toml files and
+// federation records repeatedly, use an `httpcache.Client` as its HTTP client
+// and as the HTTP client of its StellarTOML client.
 type Client struct {
 	StellarTOML StellarTOML
 	HTTP        HTTP
 	Horizon     Horizon
 	AllowHTTP   bool
+
+	// DomainConcurrency is the maximum number of requests LookupByAddresses
+	// sends concurrently to the federation server of a domain.
+	DomainConcurrency int
+
+	// MaxConcurrentDomains is the maximum number of domains LookupByAddresses
+	// looks up concurrently, bounding the total number of requests in flight
+	// to MaxConcurrentDomains * DomainConcurrency.
+	MaxConcurrentDomains int
+}
+
+// AddressResult is the result of the lookup of a single address by
+// LookupByAddresses: either the response of the federation server, or the
+// error that occurred.
+type AddressResult struct {
+	Address  string
+	Response *proto.NameResponse
+	Err      error
 }
 
 // Horizon represents a horizon client that can be consulted for data when
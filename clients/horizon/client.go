@@ -4,10 +4,10 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
@@ -144,96 +144,313 @@ func (c *Client) LoadOrderBook(selling Asset, buying Asset, params ...interface{
 	return
 }
 
-func (c *Client) stream(ctx context.Context, baseURL string, cursor *Cursor, handler func(data []byte) error) error {
+// LoadRoot loads the root resource of horizon, which describes the horizon
+// server and the network it is connected to.
+func (c *Client) LoadRoot() (root Root, err error) {
+	err = c.load(c.URL+"/", &root)
+	return
+}
+
+// LoadMetrics loads a snapshot of the metrics collected by horizon.
+func (c *Client) LoadMetrics() (metrics Metrics, err error) {
+	err = c.load(c.URL+"/metrics", &metrics)
+	return
+}
+
+// LoadAccountData loads the value of the data entry named `key` of an account.
+func (c *Client) LoadAccountData(accountID string, key string) (data AccountData, err error) {
+	err = c.load(c.URL+"/accounts/"+accountID+"/data/"+pathSegment(key), &data)
+	return
+}
+
+// LoadLedger loads the ledger with the provided sequence.
+func (c *Client) LoadLedger(sequence int32) (ledger Ledger, err error) {
+	err = c.load(fmt.Sprintf("%s/ledgers/%d", c.URL, sequence), &ledger)
+	return
+}
+
+// LoadLedgers loads a page of ledgers.  See the package documentation for the
+// supported parameters.
+func (c *Client) LoadLedgers(params ...interface{}) (page LedgersPage, err error) {
+	endpoint, err := c.collectionURL("ledgers", 0, params)
+	if err != nil {
+		return
+	}
+
+	err = c.load(endpoint, &page)
+	return
+}
+
+// LoadTransaction loads the transaction with the provided hash.
+func (c *Client) LoadTransaction(hash string) (transaction Transaction, err error) {
+	err = c.load(c.URL+"/transactions/"+hash, &transaction)
+	return
+}
+
+// LoadTransactions loads a page of transactions, which may be filtered using
+// ForAccount or ForLedger.
+func (c *Client) LoadTransactions(params ...interface{}) (page TransactionsPage, err error) {
+	endpoint, err := c.collectionURL("transactions", scopeAccount|scopeLedger, params)
+	if err != nil {
+		return
+	}
+
+	err = c.load(endpoint, &page)
+	return
+}
+
+// LoadOperation loads the operation with the provided id.
+func (c *Client) LoadOperation(id string) (operation Operation, err error) {
+	err = c.load(c.URL+"/operations/"+id, &operation)
+	return
+}
+
+// LoadOperations loads a page of operations, which may be filtered using
+// ForAccount, ForLedger or ForTransaction.
+func (c *Client) LoadOperations(params ...interface{}) (page OperationsPage, err error) {
+	endpoint, err := c.collectionURL("operations", scopeAccount|scopeLedger|scopeTransaction, params)
+	if err != nil {
+		return
+	}
+
+	err = c.load(endpoint, &page)
+	return
+}
+
+// LoadPayment loads the payment operation with the provided id.
+func (c *Client) LoadPayment(id string) (payment Payment, err error) {
+	err = c.load(c.URL+"/operations/"+id, &payment)
+	return
+}
+
+// LoadPayments loads a page of payments, which may be filtered using
+// ForAccount, ForLedger or ForTransaction.
+func (c *Client) LoadPayments(params ...interface{}) (page PaymentsPage, err error) {
+	endpoint, err := c.collectionURL("payments", scopeAccount|scopeLedger|scopeTransaction, params)
+	if err != nil {
+		return
+	}
+
+	err = c.load(endpoint, &page)
+	return
+}
+
+// LoadEffects loads a page of effects, which may be filtered using ForAccount,
+// ForLedger, ForTransaction or ForOperation.
+func (c *Client) LoadEffects(params ...interface{}) (page EffectsPage, err error) {
+	endpoint, err := c.collectionURL("effects", scopeAccount|scopeLedger|scopeTransaction|scopeOperation, params)
+	if err != nil {
+		return
+	}
+
+	err = c.load(endpoint, &page)
+	return
+}
+
+// LoadTrades loads a page of trades, which may be filtered using AssetPair.
+func (c *Client) LoadTrades(params ...interface{}) (page TradesPage, err error) {
+	endpoint, err := c.collectionURL("trades", scopeAssetPair, params)
+	if err != nil {
+		return
+	}
+
+	err = c.load(endpoint, &page)
+	return
+}
+
+// LoadPaths finds the payment paths described by `request`.
+func (c *Client) LoadPaths(request PathRequest) (paths PathsPage, err error) {
+	query := url.Values{}
+	query.Add("source_account", request.SourceAccount)
+	query.Add("destination_account", request.DestinationAccount)
+	query.Add("destination_amount", request.DestinationAmount)
+	addAssetQuery(query, "destination_", request.DestinationAsset)
+
+	err = c.load(c.URL+"/paths?"+query.Encode(), &paths)
+	return
+}
+
+// FundAccount asks the friendbot of the horizon server to create and fund an
+// account.  Friendbot is only available on test networks.
+func (c *Client) FundAccount(accountID string) (response TransactionSuccess, err error) {
 	query := url.Values{}
+	query.Add("addr", accountID)
+
+	err = c.load(c.URL+"/friendbot?"+query.Encode(), &response)
+	return
+}
+
+// stream streams the events of `endpoint`, calling `handler` with the data of
+// each message received.  When the connection fails or is closed by horizon,
+// stream reconnects and resumes after the last event received, using the
+// `Last-Event-ID` header and `cursor` parameter.  Reconnection waits for the
+// delay requested by horizon using the `retry` field, backing off
+// exponentially (with jitter) while connecting fails.  stream only returns
+// when `ctx` is done, when `handler` fails or when horizon rejects the request
+// with a client error, such as an invalid cursor.
+func (c *Client) stream(ctx context.Context, endpoint string, cursor *Cursor, handler func(data []byte) error) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse endpoint")
+	}
+
+	query := u.Query()
 	if cursor != nil {
 		query.Set("cursor", string(*cursor))
 	}
 
+	state := streamState{retry: defaultStreamRetry}
+	failures := 0
+
 	for {
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s?%s", baseURL, query.Encode()), nil)
-		if err != nil {
-			return err
+		u.RawQuery = query.Encode()
+		connected, err := c.readStream(ctx, u.String(), &state, handler)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		if perr, ok := err.(permanentError); ok {
+			return perr.err
 		}
-		req.Header.Set("Accept", "text/event-stream")
 
-		resp, err := c.HTTP.Do(req)
+		if connected {
+			failures = 0
+		}
 		if err != nil {
-			return err
+			failures++
 		}
-		defer resp.Body.Close()
-		scanner := bufio.NewScanner(resp.Body)
-		scanner.Split(splitSSE)
-
-		var objectBytes []byte
-
-		for scanner.Scan() {
-			// Check if ctx is not cancelled
-			select {
-			case <-ctx.Done():
-				return nil
-			default:
-				// Continue streaming
-			}
 
-			if len(scanner.Bytes()) == 0 {
-				continue
-			}
+		if state.lastID != "" {
+			query.Set("cursor", state.lastID)
+		}
 
-			ev, err := parseEvent(scanner.Bytes())
-			if err != nil {
-				return err
-			}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(streamBackoff(state.retry, failures)):
+		}
+	}
+}
 
-			if ev.Event != "message" {
-				continue
-			}
+// readStream connects to `endpoint` and reads its events until the connection
+// is closed, updating `state` along the way.  `connected` reports whether
+// horizon accepted the request.  Errors that should not be retried are
+// returned as a permanentError.
+func (c *Client) readStream(
+	ctx context.Context,
+	endpoint string,
+	state *streamState,
+	handler func(data []byte) error,
+) (connected bool, err error) {
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return false, permanentError{err}
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if state.lastID != "" {
+		req.Header.Set("Last-Event-ID", state.lastID)
+	}
+	req.Cancel = ctx.Done()
 
-			switch data := ev.Data.(type) {
-			case string:
-				err = handler([]byte(data))
-				objectBytes = []byte(data)
-			case []byte:
-				err = handler(data)
-				objectBytes = data
-			default:
-				err = errors.New("Invalid ev.Data type")
-			}
-			if err != nil {
-				return err
-			}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		err = decodeResponse(resp, nil)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return false, permanentError{err}
 		}
+		return false, err
+	}
 
-		err = scanner.Err()
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Split(splitSSE)
 
-		// Start streaming from the next object:
-		// - if there was no error OR
-		// - if connection was lost
-		if err == nil || err == io.ErrUnexpectedEOF {
-			object := struct {
-				PT string `json:"paging_token"`
-			}{}
+	for scanner.Scan() {
+		// Check if ctx is not cancelled
+		select {
+		case <-ctx.Done():
+			return true, nil
+		default:
+			// Continue streaming
+		}
 
-			err := json.Unmarshal(objectBytes, &object)
-			if err != nil {
-				return errors.Wrap(err, "Error unmarshaling objectBytes")
-			}
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
 
-			if object.PT != "" {
-				query.Set("cursor", object.PT)
-			} else {
-				return errors.New("no paging_token in object: cannot continue")
-			}
+		id, retry, hasRetry := parseEventFields(scanner.Bytes())
+		if hasRetry {
+			state.retry = retry
+		}
+
+		ev, err := parseEvent(scanner.Bytes())
+		if err != nil {
+			return true, permanentError{err}
+		}
 
+		if ev.Event != "message" {
 			continue
 		}
 
+		var data []byte
+		switch d := ev.Data.(type) {
+		case string:
+			data = []byte(d)
+		case []byte:
+			data = d
+		default:
+			return true, permanentError{errors.New("Invalid ev.Data type")}
+		}
+
+		err = handler(data)
 		if err != nil {
-			return err
+			return true, permanentError{err}
 		}
+
+		// Resume after this event when reconnecting.  Events are identified by
+		// their paging token, which is also found in the data of events
+		// streamed by older horizon servers that do not send an id.
+		if id == "" {
+			object := struct {
+				PT string `json:"paging_token"`
+			}{}
+			if json.Unmarshal(data, &object) == nil {
+				id = object.PT
+			}
+		}
+		if id != "" {
+			state.lastID = id
+		}
+	}
+
+	return true, scanner.Err()
+}
+
+// StreamEffects streams incoming effects, which may be filtered using
+// ForAccount, ForLedger, ForTransaction or ForOperation. Use context.WithCancel
+// to stop streaming or context.Background() if you want to stream indefinitely.
+func (c *Client) StreamEffects(ctx context.Context, cursor *Cursor, handler EffectHandler, params ...interface{}) error {
+	endpoint, err := c.collectionURL("effects", scopeAccount|scopeLedger|scopeTransaction|scopeOperation, params)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return c.stream(ctx, endpoint, cursor, func(data []byte) error {
+		var effect Effect
+		err := json.Unmarshal(data, &effect)
+		if err != nil {
+			return errors.Wrap(err, "Error unmarshaling data")
+		}
+		handler(effect)
+		return nil
+	})
 }
 
 // StreamLedgers streams incoming ledgers. Use context.WithCancel to stop streaming or
@@ -251,6 +468,60 @@ func (c *Client) StreamLedgers(ctx context.Context, cursor *Cursor, handler Ledg
 	})
 }
 
+// StreamOffers streams the offers of an account. Use context.WithCancel to stop
+// streaming or context.Background() if you want to stream indefinitely.
+func (c *Client) StreamOffers(ctx context.Context, accountID string, cursor *Cursor, handler OfferHandler) error {
+	url := fmt.Sprintf("%s/accounts/%s/offers", c.URL, accountID)
+	return c.stream(ctx, url, cursor, func(data []byte) error {
+		var offer Offer
+		err := json.Unmarshal(data, &offer)
+		if err != nil {
+			return errors.Wrap(err, "Error unmarshaling data")
+		}
+		handler(offer)
+		return nil
+	})
+}
+
+// StreamOperations streams incoming operations, which may be filtered using
+// ForAccount, ForLedger or ForTransaction. Use context.WithCancel to stop
+// streaming or context.Background() if you want to stream indefinitely.
+func (c *Client) StreamOperations(ctx context.Context, cursor *Cursor, handler OperationHandler, params ...interface{}) error {
+	endpoint, err := c.collectionURL("operations", scopeAccount|scopeLedger|scopeTransaction, params)
+	if err != nil {
+		return err
+	}
+
+	return c.stream(ctx, endpoint, cursor, func(data []byte) error {
+		var operation Operation
+		err := json.Unmarshal(data, &operation)
+		if err != nil {
+			return errors.Wrap(err, "Error unmarshaling data")
+		}
+		handler(operation)
+		return nil
+	})
+}
+
+// StreamOrderBook streams snapshots of the order book of the selling and
+// buying assets, sent by horizon whenever it changes. Use context.WithCancel to
+// stop streaming or context.Background() if you want to stream indefinitely.
+func (c *Client) StreamOrderBook(ctx context.Context, selling Asset, buying Asset, handler OrderBookHandler) error {
+	query := url.Values{}
+	addAssetQuery(query, "selling_", selling)
+	addAssetQuery(query, "buying_", buying)
+
+	return c.stream(ctx, c.URL+"/order_book?"+query.Encode(), nil, func(data []byte) error {
+		var orderBook OrderBookSummary
+		err := json.Unmarshal(data, &orderBook)
+		if err != nil {
+			return errors.Wrap(err, "Error unmarshaling data")
+		}
+		handler(orderBook)
+		return nil
+	})
+}
+
 // StreamPayments streams incoming payments. Use context.WithCancel to stop streaming or
 // context.Background() if you want to stream indefinitely.
 func (c *Client) StreamPayments(ctx context.Context, accountID string, cursor *Cursor, handler PaymentHandler) (err error) {
@@ -266,6 +537,26 @@ func (c *Client) StreamPayments(ctx context.Context, accountID string, cursor *C
 	})
 }
 
+// StreamTrades streams incoming trades, which may be filtered using AssetPair.
+// Use context.WithCancel to stop streaming or context.Background() if you want
+// to stream indefinitely.
+func (c *Client) StreamTrades(ctx context.Context, cursor *Cursor, handler TradeHandler, params ...interface{}) error {
+	endpoint, err := c.collectionURL("trades", scopeAssetPair, params)
+	if err != nil {
+		return err
+	}
+
+	return c.stream(ctx, endpoint, cursor, func(data []byte) error {
+		var trade Trade
+		err := json.Unmarshal(data, &trade)
+		if err != nil {
+			return errors.Wrap(err, "Error unmarshaling data")
+		}
+		handler(trade)
+		return nil
+	})
+}
+
 // StreamTransactions streams incoming transactions. Use context.WithCancel to stop streaming or
 // context.Background() if you want to stream indefinitely.
 func (c *Client) StreamTransactions(ctx context.Context, accountID string, cursor *Cursor, handler TransactionHandler) (err error) {
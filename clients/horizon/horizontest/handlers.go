@@ -0,0 +1,409 @@
+package horizontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 200
+)
+
+// collection selects the records of one of the collections of the ledger.
+type collection func(l *ledger) []record
+
+func ledgers(l *ledger) []record      { return l.ledgers }
+func transactions(l *ledger) []record { return l.transactions }
+func payments(l *ledger) []record     { return l.payments }
+
+// pageQuery holds the paging parameters of a request for a collection.
+type pageQuery struct {
+	cursor int64
+	now    bool
+	limit  int
+	desc   bool
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.URL.Path == "/":
+		s.serveRoot(w, r)
+	case r.URL.Path == "/friendbot":
+		s.serveFriendbot(w, r)
+	case r.URL.Path == "/transactions" && r.Method == "POST":
+		s.serveSubmit(w, r)
+	case r.Method != "GET":
+		writeProblem(w, notImplementedProblem)
+	case len(segments) == 1:
+		switch segments[0] {
+		case "ledgers":
+			s.serveCollection(w, r, ledgers, "")
+		case "transactions":
+			s.serveCollection(w, r, transactions, "")
+		case "payments":
+			s.serveCollection(w, r, payments, "")
+		default:
+			writeProblem(w, notFoundProblem)
+		}
+	case len(segments) == 2 && segments[0] == "accounts":
+		s.serveAccount(w, segments[1])
+	case len(segments) == 2 && segments[0] == "transactions":
+		s.serveRecord(w, transactions, func(resource interface{}) bool {
+			return resource.(transactionResource).Hash == segments[1]
+		})
+	case len(segments) == 2 && segments[0] == "ledgers":
+		sequence, err := strconv.ParseInt(segments[1], 10, 32)
+		if err != nil {
+			writeProblem(w, notFoundProblem)
+			return
+		}
+		s.serveRecord(w, ledgers, func(resource interface{}) bool {
+			return resource.(horizon.Ledger).Sequence == int32(sequence)
+		})
+	case len(segments) == 3 && segments[0] == "accounts" && segments[2] == "transactions":
+		s.serveCollection(w, r, transactions, segments[1])
+	case len(segments) == 3 && segments[0] == "accounts" && segments[2] == "payments":
+		s.serveCollection(w, r, payments, segments[1])
+	default:
+		writeProblem(w, notImplementedProblem)
+	}
+}
+
+func (s *Server) serveRoot(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	latest := s.ledger.sequence
+	s.lock.Unlock()
+
+	var root horizon.Root
+	root.HorizonVersion = "horizontest"
+	root.StellarCoreVersion = "horizontest"
+	root.HorizonSequence = latest
+	root.HistoryElderSequence = 1
+	root.CoreSequence = latest
+	root.CoreElderSequence = 1
+	root.NetworkPassphrase = s.Passphrase
+	root.ProtocolVersion = protocolVersion
+
+	root.Links.Account.Href = s.URL + "/accounts/{account_id}"
+	root.Links.Account.Templated = true
+	root.Links.AccountTransactions.Href = s.URL + "/accounts/{account_id}/transactions{?cursor,limit,order}"
+	root.Links.AccountTransactions.Templated = true
+	root.Links.Friendbot.Href = s.URL + "/friendbot{?addr}"
+	root.Links.Friendbot.Templated = true
+	root.Links.Self.Href = s.URL + "/"
+	root.Links.Transaction.Href = s.URL + "/transactions/{hash}"
+	root.Links.Transaction.Templated = true
+	root.Links.Transactions.Href = s.URL + "/transactions{?cursor,limit,order}"
+	root.Links.Transactions.Templated = true
+
+	writeJSON(w, http.StatusOK, root)
+}
+
+func (s *Server) serveFriendbot(w http.ResponseWriter, r *http.Request) {
+	address := r.URL.Query().Get("addr")
+	if address == "" {
+		writeProblem(w, badRequestProblem)
+		return
+	}
+
+	result, err := s.Fund(address, FriendbotAmount)
+	if herr, ok := err.(*horizon.Error); ok {
+		writeProblem(w, herr.Problem)
+		return
+	}
+	if err != nil {
+		writeProblem(w, badRequestProblem)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) serveSubmit(w http.ResponseWriter, r *http.Request) {
+	result, err := s.Submit(r.PostFormValue("tx"))
+	if herr, ok := err.(*horizon.Error); ok {
+		writeProblem(w, herr.Problem)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) serveAccount(w http.ResponseWriter, address string) {
+	s.lock.Lock()
+	resource, ok := s.ledger.accountResource(address)
+	s.lock.Unlock()
+
+	if !ok {
+		writeProblem(w, notFoundProblem)
+		return
+	}
+	writeJSON(w, http.StatusOK, resource)
+}
+
+// serveRecord responds with the first record of `c` matching `match`.
+func (s *Server) serveRecord(w http.ResponseWriter, c collection, match func(interface{}) bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, rec := range c(s.ledger) {
+		if match(rec.resource) {
+			writeJSON(w, http.StatusOK, rec.resource)
+			return
+		}
+	}
+	writeProblem(w, notFoundProblem)
+}
+
+// serveCollection responds with a page of the records of `c` concerning
+// `account`, or with every record when `account` is empty.  Requests
+// accepting server sent events are streamed instead.
+func (s *Server) serveCollection(w http.ResponseWriter, r *http.Request, c collection, account string) {
+	q, ok := parsePageQuery(r.URL.Query())
+	if !ok {
+		writeProblem(w, badRequestProblem)
+		return
+	}
+
+	if account != "" {
+		s.lock.Lock()
+		exists := s.ledger.accounts[account] != nil
+		s.lock.Unlock()
+		if !exists {
+			writeProblem(w, notFoundProblem)
+			return
+		}
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		s.stream(w, r, c, account, q)
+		return
+	}
+
+	s.lock.Lock()
+	records := page(c(s.ledger), account, q)
+	s.lock.Unlock()
+
+	var body struct {
+		Links    horizon.PageLinks `json:"_links"`
+		Embedded struct {
+			Records []interface{} `json:"records"`
+		} `json:"_embedded"`
+	}
+
+	body.Embedded.Records = []interface{}{}
+	for _, rec := range records {
+		body.Embedded.Records = append(body.Embedded.Records, rec.resource)
+	}
+
+	// like horizon, the next and prev links of an empty page keep the cursor
+	next, prev := q.cursor, q.cursor
+	if len(records) > 0 {
+		prev = records[0].token
+		next = records[len(records)-1].token
+	}
+
+	base := s.URL + r.URL.Path
+	body.Links.Self.Href = base + "?" + r.URL.RawQuery
+	body.Links.Next.Href = pageURL(base, next, q.limit, q.desc)
+	body.Links.Prev.Href = pageURL(base, prev, q.limit, !q.desc)
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+// stream sends the records of `c` concerning `account` after the cursor of
+// `q` as server sent events, followed by every record added until the client
+// disconnects or the server is closed.
+func (s *Server) stream(w http.ResponseWriter, r *http.Request, c collection, account string, q pageQuery) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeProblem(w, serverErrorProblem)
+		return
+	}
+
+	var closed <-chan bool
+	if notifier, ok := w.(http.CloseNotifier); ok {
+		closed = notifier.CloseNotify()
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 1000\nevent: open\ndata: \"hello\"\n\n")
+	flusher.Flush()
+
+	cursor := q.cursor
+	s.lock.Lock()
+	if q.now {
+		cursor = latestToken(c(s.ledger))
+	}
+	s.lock.Unlock()
+
+	for {
+		s.lock.Lock()
+		records := page(c(s.ledger), account, pageQuery{cursor: cursor, limit: maxLimit})
+		changed := s.changed
+		s.lock.Unlock()
+
+		for _, rec := range records {
+			data, err := json.Marshal(rec.resource)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "id: %d\ndata: %s\n\n", rec.token, data)
+			cursor = rec.token
+		}
+		flusher.Flush()
+
+		if len(records) == maxLimit {
+			continue
+		}
+
+		select {
+		case <-changed:
+		case <-closed:
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// apply applies the transaction of `txe`, notifying every stream when a
+// ledger was closed.  The caller must hold the lock of the server.
+func (s *Server) apply(txe xdr.TransactionEnvelope) (horizon.TransactionSuccess, *horizon.Problem) {
+	before := s.ledger.sequence
+	result, problem := s.ledger.apply(txe, time.Now())
+
+	if s.ledger.sequence != before {
+		close(s.changed)
+		s.changed = make(chan struct{})
+	}
+	return result, problem
+}
+
+// page returns the records of `records`, which are ordered by paging token,
+// concerning `account` that follow the cursor of `q` in its order.
+func page(records []record, account string, q pageQuery) []record {
+	var result []record
+
+	if !q.desc {
+		for _, rec := range records {
+			if len(result) == q.limit {
+				break
+			}
+			if rec.token > q.cursor && concerns(rec, account) {
+				result = append(result, rec)
+			}
+		}
+		return result
+	}
+
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+		if len(result) == q.limit {
+			break
+		}
+		if (q.cursor == 0 || rec.token < q.cursor) && concerns(rec, account) {
+			result = append(result, rec)
+		}
+	}
+	return result
+}
+
+func concerns(rec record, account string) bool {
+	if account == "" {
+		return true
+	}
+
+	for _, participant := range rec.participants {
+		if participant == account {
+			return true
+		}
+	}
+	return false
+}
+
+func latestToken(records []record) int64 {
+	if len(records) == 0 {
+		return 0
+	}
+	return records[len(records)-1].token
+}
+
+func parsePageQuery(query url.Values) (pageQuery, bool) {
+	q := pageQuery{limit: defaultLimit}
+
+	switch cursor := query.Get("cursor"); cursor {
+	case "":
+	case "now":
+		q.now = true
+	default:
+		var err error
+		q.cursor, err = strconv.ParseInt(cursor, 10, 64)
+		if err != nil || q.cursor < 0 {
+			return q, false
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		q.limit, err = strconv.Atoi(limit)
+		if err != nil || q.limit < 1 || q.limit > maxLimit {
+			return q, false
+		}
+	}
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		q.desc = true
+	default:
+		return q, false
+	}
+
+	// when paging, "now" is after every record
+	if q.now && !q.desc {
+		q.cursor = 1<<63 - 1
+	}
+
+	return q, true
+}
+
+func pageURL(base string, cursor int64, limit int, desc bool) string {
+	order := "asc"
+	if desc {
+		order = "desc"
+	}
+
+	query := url.Values{}
+	query.Set("order", order)
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != 0 {
+		query.Set("cursor", strconv.FormatInt(cursor, 10))
+	}
+	return base + "?" + query.Encode()
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/hal+json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeProblem(w http.ResponseWriter, p horizon.Problem) {
+	w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
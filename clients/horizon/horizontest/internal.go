@@ -0,0 +1,334 @@
+package horizontest
+
+import (
+	stdbase64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txauth"
+	"github.com/stellar/go/xdr"
+)
+
+// protocolVersion is the protocol version reported by the server.
+const protocolVersion = 8
+
+// result codes, as reported by horizon
+const (
+	opSuccess            = "op_success"
+	opMalformed          = "op_malformed"
+	opUnderfunded        = "op_underfunded"
+	opLowReserve         = "op_low_reserve"
+	opAlreadyExists      = "op_already_exists"
+	opSrcNoTrust         = "op_src_no_trust"
+	opNoDestination      = "op_no_destination"
+	opNoTrust            = "op_no_trust"
+	opLineFull           = "op_line_full"
+	opNoIssuer           = "op_no_issuer"
+	opInvalidLimit       = "op_invalid_limit"
+	opBadAuth            = "op_bad_auth"
+	opNoSourceAccount    = "op_no_source_account"
+	transactionMalformed = "transaction_malformed"
+	transactionFailed    = "transaction_failed"
+)
+
+// problemTypePrefix is the prefix horizon adds to the type of every problem.
+const problemTypePrefix = "https://stellar.org/horizon-errors/"
+
+var transactionCodes = map[xdr.TransactionResultCode]string{
+	xdr.TransactionResultCodeTxSuccess:             "tx_success",
+	xdr.TransactionResultCodeTxFailed:              "tx_failed",
+	xdr.TransactionResultCodeTxTooEarly:            "tx_too_early",
+	xdr.TransactionResultCodeTxTooLate:             "tx_too_late",
+	xdr.TransactionResultCodeTxMissingOperation:    "tx_missing_operation",
+	xdr.TransactionResultCodeTxBadSeq:              "tx_bad_seq",
+	xdr.TransactionResultCodeTxBadAuth:             "tx_bad_auth",
+	xdr.TransactionResultCodeTxInsufficientBalance: "tx_insufficient_balance",
+	xdr.TransactionResultCodeTxNoAccount:           "tx_no_source_account",
+	xdr.TransactionResultCodeTxInsufficientFee:     "tx_insufficient_fee",
+	xdr.TransactionResultCodeTxBadAuthExtra:        "tx_bad_auth_extra",
+}
+
+var (
+	notFoundProblem = horizon.Problem{
+		Type:   problemTypePrefix + "not_found",
+		Title:  "Resource Missing",
+		Status: http.StatusNotFound,
+		Detail: "The resource at the url requested was not found.",
+	}
+
+	notImplementedProblem = horizon.Problem{
+		Type:   problemTypePrefix + "not_implemented",
+		Title:  "Resource Not Yet Implemented",
+		Status: http.StatusNotFound,
+		Detail: "The fake horizon server does not support this request.",
+	}
+
+	badRequestProblem = horizon.Problem{
+		Type:   problemTypePrefix + "bad_request",
+		Title:  "Bad Request",
+		Status: http.StatusBadRequest,
+		Detail: "The request you sent was invalid in some way.",
+	}
+
+	serverErrorProblem = horizon.Problem{
+		Type:   problemTypePrefix + "server_error",
+		Title:  "Internal Server Error",
+		Status: http.StatusInternalServerError,
+	}
+)
+
+// transactionResource is the resource of a transaction, which adds links to
+// the client's representation.
+type transactionResource struct {
+	Links struct {
+		Self       horizon.Link `json:"self"`
+		Account    horizon.Link `json:"account"`
+		Ledger     horizon.Link `json:"ledger"`
+		Operations horizon.Link `json:"operations"`
+		Payments   horizon.Link `json:"payments"`
+	} `json:"_links"`
+
+	horizon.Transaction
+}
+
+// paymentResource is the resource of an operation listed as a payment.
+type paymentResource struct {
+	Links struct {
+		Self        horizon.Link `json:"self"`
+		Transaction horizon.Link `json:"transaction"`
+	} `json:"_links"`
+
+	ID              string `json:"id"`
+	PagingToken     string `json:"paging_token"`
+	SourceAccount   string `json:"source_account"`
+	Type            string `json:"type"`
+	TypeI           int32  `json:"type_i"`
+	TransactionHash string `json:"transaction_hash"`
+
+	// create_account fields
+	Account         string `json:"account,omitempty"`
+	Funder          string `json:"funder,omitempty"`
+	StartingBalance string `json:"starting_balance,omitempty"`
+
+	// payment fields
+	From        string `json:"from,omitempty"`
+	To          string `json:"to,omitempty"`
+	AssetType   string `json:"asset_type,omitempty"`
+	AssetCode   string `json:"asset_code,omitempty"`
+	AssetIssuer string `json:"asset_issuer,omitempty"`
+	Amount      string `json:"amount,omitempty"`
+}
+
+// failedProblem returns the problem horizon responds with when the
+// transaction of `envelope` fails with `result`.
+func failedProblem(envelope string, result xdr.TransactionResult) *horizon.Problem {
+	codes := horizon.TransactionResultCodes{
+		TransactionCode: transactionCodes[result.Result.Code],
+	}
+	if result.Result.Results != nil {
+		for _, op := range *result.Result.Results {
+			codes.OperationCodes = append(codes.OperationCodes, operationCode(op))
+		}
+	}
+
+	resultXDR, _ := xdr.MarshalBase64(result)
+	return &horizon.Problem{
+		Type:   problemTypePrefix + transactionFailed,
+		Title:  "Transaction Failed",
+		Status: http.StatusBadRequest,
+		Detail: "The transaction failed when submitted to the stellar network. " +
+			"The `extras.result_codes` field on this response contains further " +
+			"details.",
+		Extras: extras(map[string]interface{}{
+			"envelope_xdr": envelope,
+			"result_xdr":   resultXDR,
+			"result_codes": codes,
+		}),
+	}
+}
+
+// malformedProblem returns the problem horizon responds with when the
+// envelope `envelope` cannot be decoded.
+func malformedProblem(envelope string) horizon.Problem {
+	return horizon.Problem{
+		Type:   problemTypePrefix + transactionMalformed,
+		Title:  "Transaction Malformed",
+		Status: http.StatusBadRequest,
+		Detail: "Horizon could not decode the transaction envelope in this " +
+			"request.",
+		Extras: extras(map[string]interface{}{
+			"envelope_xdr": envelope,
+		}),
+	}
+}
+
+func extras(values map[string]interface{}) map[string]json.RawMessage {
+	result := make(map[string]json.RawMessage, len(values))
+	for key, value := range values {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			panic(err)
+		}
+		result[key] = raw
+	}
+	return result
+}
+
+func problemPtr(p horizon.Problem) *horizon.Problem {
+	return &p
+}
+
+// operationCode returns the code horizon reports for the result `r`.
+func operationCode(r xdr.OperationResult) string {
+	switch r.Code {
+	case xdr.OperationResultCodeOpBadAuth:
+		return opBadAuth
+	case xdr.OperationResultCodeOpNoAccount:
+		return opNoSourceAccount
+	}
+
+	switch r.Tr.Type {
+	case xdr.OperationTypeCreateAccount:
+		switch r.Tr.CreateAccountResult.Code {
+		case xdr.CreateAccountResultCodeCreateAccountSuccess:
+			return opSuccess
+		case xdr.CreateAccountResultCodeCreateAccountUnderfunded:
+			return opUnderfunded
+		case xdr.CreateAccountResultCodeCreateAccountLowReserve:
+			return opLowReserve
+		case xdr.CreateAccountResultCodeCreateAccountAlreadyExist:
+			return opAlreadyExists
+		}
+	case xdr.OperationTypePayment:
+		switch r.Tr.PaymentResult.Code {
+		case xdr.PaymentResultCodePaymentSuccess:
+			return opSuccess
+		case xdr.PaymentResultCodePaymentUnderfunded:
+			return opUnderfunded
+		case xdr.PaymentResultCodePaymentSrcNoTrust:
+			return opSrcNoTrust
+		case xdr.PaymentResultCodePaymentNoDestination:
+			return opNoDestination
+		case xdr.PaymentResultCodePaymentNoTrust:
+			return opNoTrust
+		case xdr.PaymentResultCodePaymentLineFull:
+			return opLineFull
+		case xdr.PaymentResultCodePaymentNoIssuer:
+			return opNoIssuer
+		}
+	case xdr.OperationTypeChangeTrust:
+		switch r.Tr.ChangeTrustResult.Code {
+		case xdr.ChangeTrustResultCodeChangeTrustSuccess:
+			return opSuccess
+		case xdr.ChangeTrustResultCodeChangeTrustNoIssuer:
+			return opNoIssuer
+		case xdr.ChangeTrustResultCodeChangeTrustInvalidLimit:
+			return opInvalidLimit
+		case xdr.ChangeTrustResultCodeChangeTrustLowReserve:
+			return opLowReserve
+		}
+	}
+
+	return opMalformed
+}
+
+// successfulResults returns a successful result for each of `ops`.
+func successfulResults(ops []xdr.Operation) []xdr.OperationResult {
+	results := make([]xdr.OperationResult, len(ops))
+	for i, op := range ops {
+		tr := &xdr.OperationResultTr{Type: op.Body.Type}
+		switch op.Body.Type {
+		case xdr.OperationTypeCreateAccount:
+			tr.CreateAccountResult = &xdr.CreateAccountResult{}
+		case xdr.OperationTypePayment:
+			tr.PaymentResult = &xdr.PaymentResult{}
+		case xdr.OperationTypeChangeTrust:
+			tr.ChangeTrustResult = &xdr.ChangeTrustResult{}
+		}
+		results[i] = xdr.OperationResult{Code: xdr.OperationResultCodeOpInner, Tr: tr}
+	}
+	return results
+}
+
+// supported returns true if operations of type `typ` can be applied.
+func supported(typ xdr.OperationType) bool {
+	switch typ {
+	case xdr.OperationTypeCreateAccount, xdr.OperationTypePayment, xdr.OperationTypeChangeTrust:
+		return true
+	default:
+		return false
+	}
+}
+
+// signerAccount returns the signing configuration of the account `address`,
+// whose master key is its only signer and whose thresholds are all zero.
+func signerAccount(address string) txauth.Account {
+	return txauth.NewAccount(address, xdr.Thresholds{1, 0, 0, 0})
+}
+
+// validAsset returns true if `asset` is native or a credit asset with a valid
+// issuer.
+func validAsset(asset xdr.Asset) bool {
+	if asset.Type == xdr.AssetTypeAssetTypeNative {
+		return true
+	}
+
+	_, err := strkey.Decode(strkey.VersionByteAccountID, issuerOf(asset))
+	return err == nil
+}
+
+// issuerOf returns the address of the issuer of the credit asset `asset`.
+func issuerOf(asset xdr.Asset) string {
+	var typ, code, issuer string
+	if err := asset.Extract(&typ, &code, &issuer); err != nil {
+		return ""
+	}
+	return issuer
+}
+
+// memo returns the type and value of `m` as reported by horizon.
+func memo(m xdr.Memo) (string, string) {
+	switch m.Type {
+	case xdr.MemoTypeMemoText:
+		return "text", m.MustText()
+	case xdr.MemoTypeMemoId:
+		return "id", fmt.Sprint(uint64(m.MustId()))
+	case xdr.MemoTypeMemoHash:
+		hash := m.MustHash()
+		return "hash", base64(hash[:])
+	case xdr.MemoTypeMemoReturn:
+		hash := m.MustRetHash()
+		return "return", base64(hash[:])
+	default:
+		return "none", ""
+	}
+}
+
+func base64(b []byte) string {
+	return stdbase64.StdEncoding.EncodeToString(b)
+}
+
+// appendAddress appends `address` to `addresses` unless it is already present.
+func appendAddress(addresses []string, address string) []string {
+	for _, a := range addresses {
+		if a == address {
+			return addresses
+		}
+	}
+	return append(addresses, address)
+}
+
+// ledgerToken returns the paging token of the ledger `sequence`, using the
+// same layout as horizon's total order ids.
+func ledgerToken(sequence int32) int64 {
+	return int64(sequence) << 32
+}
+
+// transactionToken returns the paging token of the only transaction of the
+// ledger `sequence`.  The paging token of its operations follow it.
+func transactionToken(sequence int32) int64 {
+	return ledgerToken(sequence) | 1<<12
+}
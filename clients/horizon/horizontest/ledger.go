@@ -0,0 +1,597 @@
+package horizontest
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txauth"
+	"github.com/stellar/go/xdr"
+)
+
+// ledger is the in-memory state of the fake network: its accounts and the
+// history of the ledgers, transactions and payments applied so far.
+type ledger struct {
+	url        string
+	passphrase string
+	sequence   int32
+	closedAt   time.Time
+
+	accounts     map[string]*account
+	ledgers      []record
+	transactions []record
+	payments     []record
+}
+
+// account is an account of the fake network.  Trustlines are kept in the
+// order they were created.
+type account struct {
+	address    string
+	sequence   xdr.SequenceNumber
+	balance    xdr.Int64
+	trustlines []*trustline
+}
+
+type trustline struct {
+	asset   xdr.Asset
+	balance xdr.Int64
+	limit   xdr.Int64
+}
+
+// record is a single record of a collection, along with the accounts it
+// concerns, which are used to filter the collections of an account.
+type record struct {
+	token        int64
+	participants []string
+	resource     interface{}
+}
+
+// newLedger returns the genesis ledger of a network whose root account,
+// identified by `root`, holds every lumen.
+func newLedger(url, passphrase, root string, now time.Time) *ledger {
+	l := &ledger{
+		url:        url,
+		passphrase: passphrase,
+		accounts: map[string]*account{
+			root: {address: root, balance: TotalCoins},
+		},
+	}
+
+	l.close(now, 0, 0)
+	return l
+}
+
+// fundingEnvelope returns a transaction envelope, signed by root, creating the
+// account `address` with a starting balance of `amount`.
+func (l *ledger) fundingEnvelope(root *keypair.Full, address, amount string) (xdr.TransactionEnvelope, error) {
+	source := l.accounts[root.Address()]
+
+	tx := build.Transaction(
+		build.SourceAccount{AddressOrSeed: root.Address()},
+		build.Sequence{Sequence: uint64(source.sequence) + 1},
+		build.Network{Passphrase: l.passphrase},
+		build.CreateAccount(
+			build.Destination{AddressOrSeed: address},
+			build.NativeAmount{Amount: amount},
+		),
+	)
+
+	txe := tx.SignWith(root)
+	if txe.Err != nil {
+		return xdr.TransactionEnvelope{}, txe.Err
+	}
+	return *txe.E, nil
+}
+
+// apply checks and applies the transaction of `txe`.  When the transaction is
+// invalid, the ledger is left untouched.  When one of its operations fails,
+// the fee is charged and the sequence number consumed, but the effects of its
+// operations are discarded.  Either way, the problem horizon would respond
+// with is returned.
+func (l *ledger) apply(txe xdr.TransactionEnvelope, now time.Time) (horizon.TransactionSuccess, *horizon.Problem) {
+	tx := txe.Tx
+	result := xdr.TransactionResult{FeeCharged: xdr.Int64(tx.Fee)}
+
+	envelope, err := xdr.MarshalBase64(txe)
+	if err != nil {
+		return horizon.TransactionSuccess{}, problemPtr(malformedProblem(""))
+	}
+
+	fail := func(code xdr.TransactionResultCode) (horizon.TransactionSuccess, *horizon.Problem) {
+		result.Result = xdr.TransactionResultResult{Code: code}
+		return horizon.TransactionSuccess{}, failedProblem(envelope, result)
+	}
+
+	for _, op := range tx.Operations {
+		if !supported(op.Body.Type) {
+			return horizon.TransactionSuccess{}, problemPtr(notImplementedProblem)
+		}
+	}
+
+	source := l.accounts[tx.SourceAccount.Address()]
+	fee := xdr.Int64(tx.Fee)
+
+	switch {
+	case len(tx.Operations) == 0:
+		return fail(xdr.TransactionResultCodeTxMissingOperation)
+	case tx.TimeBounds != nil && uint64(now.Unix()) < uint64(tx.TimeBounds.MinTime):
+		return fail(xdr.TransactionResultCodeTxTooEarly)
+	case tx.TimeBounds != nil && tx.TimeBounds.MaxTime != 0 &&
+		uint64(now.Unix()) > uint64(tx.TimeBounds.MaxTime):
+		return fail(xdr.TransactionResultCodeTxTooLate)
+	case fee < BaseFee*xdr.Int64(len(tx.Operations)):
+		return fail(xdr.TransactionResultCodeTxInsufficientFee)
+	case source == nil:
+		return fail(xdr.TransactionResultCodeTxNoAccount)
+	case tx.SeqNum != source.sequence+1:
+		return fail(xdr.TransactionResultCodeTxBadSeq)
+	}
+
+	// every operation source must exist before signatures can be checked
+	signers := []txauth.Account{signerAccount(source.address)}
+	for i, op := range tx.Operations {
+		if op.SourceAccount == nil {
+			continue
+		}
+
+		address := op.SourceAccount.Address()
+		if l.accounts[address] == nil {
+			results := successfulResults(tx.Operations)
+			results[i] = xdr.OperationResult{Code: xdr.OperationResultCodeOpNoAccount}
+			result.Result = xdr.TransactionResultResult{
+				Code:    xdr.TransactionResultCodeTxFailed,
+				Results: &results,
+			}
+			return horizon.TransactionSuccess{}, failedProblem(envelope, result)
+		}
+		signers = append(signers, signerAccount(address))
+	}
+
+	auth, err := txauth.Check(txe, l.passphrase, signers...)
+	if err != nil || !auth.Authorized() {
+		return fail(xdr.TransactionResultCodeTxBadAuth)
+	}
+	if !auth.Sufficient() {
+		return fail(xdr.TransactionResultCodeTxBadAuthExtra)
+	}
+
+	if source.balance-fee < source.minBalance() {
+		return fail(xdr.TransactionResultCodeTxInsufficientBalance)
+	}
+
+	// the transaction is valid: from here on its fee is charged and its
+	// sequence number consumed, even if an operation fails
+	source.balance -= fee
+	source.sequence = tx.SeqNum
+
+	snapshot := l.snapshot()
+	results := make([]xdr.OperationResult, len(tx.Operations))
+	failed := false
+	for i, op := range tx.Operations {
+		opSource := source.address
+		if op.SourceAccount != nil {
+			opSource = op.SourceAccount.Address()
+		}
+
+		results[i] = l.applyOperation(opSource, op)
+		if operationCode(results[i]) != opSuccess {
+			failed = true
+		}
+	}
+
+	if failed {
+		l.accounts = snapshot
+		result.Result = xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxFailed,
+			Results: &results,
+		}
+		l.close(now, 0, 0)
+		return horizon.TransactionSuccess{}, failedProblem(envelope, result)
+	}
+
+	result.Result = xdr.TransactionResultResult{
+		Code:    xdr.TransactionResultCodeTxSuccess,
+		Results: &results,
+	}
+	resultXDR, err := xdr.MarshalBase64(result)
+	if err != nil {
+		return horizon.TransactionSuccess{}, problemPtr(serverErrorProblem)
+	}
+
+	hash, err := network.HashTransaction(&tx, l.passphrase)
+	if err != nil {
+		return horizon.TransactionSuccess{}, problemPtr(serverErrorProblem)
+	}
+
+	l.close(now, 1, int32(len(tx.Operations)))
+	l.record(txe, hex.EncodeToString(hash[:]), envelope, resultXDR)
+
+	success := horizon.TransactionSuccess{
+		Hash:   hex.EncodeToString(hash[:]),
+		Ledger: l.sequence,
+		Env:    envelope,
+		Result: resultXDR,
+	}
+	success.Links.Transaction.Href = l.url + "/transactions/" + success.Hash
+	return success, nil
+}
+
+// applyOperation applies `op`, whose source account is `source`, returning
+// its result.
+func (l *ledger) applyOperation(source string, op xdr.Operation) xdr.OperationResult {
+	tr := &xdr.OperationResultTr{Type: op.Body.Type}
+	result := xdr.OperationResult{Code: xdr.OperationResultCodeOpInner, Tr: tr}
+
+	switch op.Body.Type {
+	case xdr.OperationTypeCreateAccount:
+		code := l.createAccount(source, op.Body.MustCreateAccountOp())
+		tr.CreateAccountResult = &xdr.CreateAccountResult{Code: code}
+	case xdr.OperationTypePayment:
+		code := l.payment(source, op.Body.MustPaymentOp())
+		tr.PaymentResult = &xdr.PaymentResult{Code: code}
+	case xdr.OperationTypeChangeTrust:
+		code := l.changeTrust(source, op.Body.MustChangeTrustOp())
+		tr.ChangeTrustResult = &xdr.ChangeTrustResult{Code: code}
+	default:
+		// the operation is rejected before the transaction is applied
+		panic(fmt.Sprintf("unsupported operation: %s", op.Body.Type))
+	}
+
+	return result
+}
+
+func (l *ledger) createAccount(source string, op xdr.CreateAccountOp) xdr.CreateAccountResultCode {
+	from := l.accounts[source]
+	address := op.Destination.Address()
+
+	switch {
+	case op.StartingBalance <= 0 || address == source:
+		return xdr.CreateAccountResultCodeCreateAccountMalformed
+	case l.accounts[address] != nil:
+		return xdr.CreateAccountResultCodeCreateAccountAlreadyExist
+	case op.StartingBalance < 2*BaseReserve:
+		return xdr.CreateAccountResultCodeCreateAccountLowReserve
+	case from.available() < op.StartingBalance:
+		return xdr.CreateAccountResultCodeCreateAccountUnderfunded
+	}
+
+	from.balance -= op.StartingBalance
+	l.accounts[address] = &account{
+		address:  address,
+		sequence: xdr.SequenceNumber(int64(l.sequence+1) << 32),
+		balance:  op.StartingBalance,
+	}
+	return xdr.CreateAccountResultCodeCreateAccountSuccess
+}
+
+func (l *ledger) payment(source string, op xdr.PaymentOp) xdr.PaymentResultCode {
+	from := l.accounts[source]
+	to := l.accounts[op.Destination.Address()]
+
+	if op.Amount <= 0 || !validAsset(op.Asset) {
+		return xdr.PaymentResultCodePaymentMalformed
+	}
+	if to == nil {
+		return xdr.PaymentResultCodePaymentNoDestination
+	}
+
+	if op.Asset.Type == xdr.AssetTypeAssetTypeNative {
+		if from.available() < op.Amount {
+			return xdr.PaymentResultCodePaymentUnderfunded
+		}
+		from.balance -= op.Amount
+		to.balance += op.Amount
+		return xdr.PaymentResultCodePaymentSuccess
+	}
+
+	issuer := issuerOf(op.Asset)
+	if l.accounts[issuer] == nil {
+		return xdr.PaymentResultCodePaymentNoIssuer
+	}
+
+	// issuers create and destroy their own assets, without trustlines
+	var fromLine, toLine *trustline
+	if from.address != issuer {
+		fromLine = from.trustline(op.Asset)
+		if fromLine == nil {
+			return xdr.PaymentResultCodePaymentSrcNoTrust
+		}
+		if fromLine.balance < op.Amount {
+			return xdr.PaymentResultCodePaymentUnderfunded
+		}
+	}
+	if to.address != issuer {
+		toLine = to.trustline(op.Asset)
+		if toLine == nil {
+			return xdr.PaymentResultCodePaymentNoTrust
+		}
+		if toLine.limit-toLine.balance < op.Amount {
+			return xdr.PaymentResultCodePaymentLineFull
+		}
+	}
+
+	if fromLine != nil {
+		fromLine.balance -= op.Amount
+	}
+	if toLine != nil {
+		toLine.balance += op.Amount
+	}
+	return xdr.PaymentResultCodePaymentSuccess
+}
+
+func (l *ledger) changeTrust(source string, op xdr.ChangeTrustOp) xdr.ChangeTrustResultCode {
+	from := l.accounts[source]
+
+	if op.Line.Type == xdr.AssetTypeAssetTypeNative || !validAsset(op.Line) ||
+		op.Limit < 0 || issuerOf(op.Line) == source {
+		return xdr.ChangeTrustResultCodeChangeTrustMalformed
+	}
+	if l.accounts[issuerOf(op.Line)] == nil {
+		return xdr.ChangeTrustResultCodeChangeTrustNoIssuer
+	}
+
+	line := from.trustline(op.Line)
+	switch {
+	case line == nil && op.Limit == 0:
+		return xdr.ChangeTrustResultCodeChangeTrustInvalidLimit
+	case line == nil:
+		if from.balance < from.minBalance()+BaseReserve {
+			return xdr.ChangeTrustResultCodeChangeTrustLowReserve
+		}
+		from.trustlines = append(from.trustlines, &trustline{
+			asset: op.Line,
+			limit: op.Limit,
+		})
+	case op.Limit < line.balance:
+		return xdr.ChangeTrustResultCodeChangeTrustInvalidLimit
+	case op.Limit == 0:
+		from.removeTrustline(op.Line)
+	default:
+		line.limit = op.Limit
+	}
+
+	return xdr.ChangeTrustResultCodeChangeTrustSuccess
+}
+
+// snapshot returns a copy of the accounts of the ledger, used to discard the
+// effects of the operations of a failed transaction.
+func (l *ledger) snapshot() map[string]*account {
+	accounts := make(map[string]*account, len(l.accounts))
+	for address, a := range l.accounts {
+		accounts[address] = a.copy()
+	}
+	return accounts
+}
+
+// close closes a new ledger containing `transactions` transactions with a
+// total of `operations` operations.
+func (l *ledger) close(now time.Time, transactions, operations int32) {
+	l.sequence++
+	l.closedAt = now
+
+	ledger := horizon.Ledger{
+		ID:               fmt.Sprintf("%064x", l.sequence),
+		PT:               fmt.Sprint(ledgerToken(l.sequence)),
+		Hash:             fmt.Sprintf("%064x", l.sequence),
+		Sequence:         l.sequence,
+		TransactionCount: transactions,
+		OperationCount:   operations,
+		ClosedAt:         now.UTC(),
+		TotalCoins:       amount.String(TotalCoins),
+		FeePool:          "0.0000000",
+		BaseFee:          BaseFee,
+		BaseReserve:      amount.String(BaseReserve),
+		MaxTxSetSize:     50,
+		ProtocolVersion:  protocolVersion,
+	}
+	if l.sequence > 1 {
+		ledger.PrevHash = fmt.Sprintf("%064x", l.sequence-1)
+	}
+
+	self := fmt.Sprintf("%s/ledgers/%d", l.url, l.sequence)
+	ledger.Links.Self.Href = self
+	ledger.Links.Transactions.Href = self + "/transactions"
+	ledger.Links.Operations.Href = self + "/operations"
+	ledger.Links.Payments.Href = self + "/payments"
+	ledger.Links.Effects.Href = self + "/effects"
+
+	l.ledgers = append(l.ledgers, record{
+		token:    ledgerToken(l.sequence),
+		resource: ledger,
+	})
+}
+
+// record adds the transaction of `txe`, applied in the last ledger closed, and
+// its payments to the history.
+func (l *ledger) record(txe xdr.TransactionEnvelope, hash, envelope, result string) {
+	tx := txe.Tx
+	token := transactionToken(l.sequence)
+
+	var resource transactionResource
+	resource.ID = hash
+	resource.PagingToken = fmt.Sprint(token)
+	resource.Hash = hash
+	resource.Ledger = l.sequence
+	resource.LedgerCloseTime = l.closedAt.UTC()
+	resource.Account = tx.SourceAccount.Address()
+	resource.AccountSequence = fmt.Sprint(int64(tx.SeqNum))
+	resource.FeePaid = int32(tx.Fee)
+	resource.OperationCount = int32(len(tx.Operations))
+	resource.EnvelopeXdr = envelope
+	resource.ResultXdr = result
+	resource.MemoType, resource.Memo = memo(tx.Memo)
+	for _, sig := range txe.Signatures {
+		resource.Signatures = append(resource.Signatures, base64(sig.Signature))
+	}
+	if tx.TimeBounds != nil {
+		resource.ValidAfter = time.Unix(int64(tx.TimeBounds.MinTime), 0).UTC().Format(time.RFC3339)
+		if tx.TimeBounds.MaxTime != 0 {
+			resource.ValidBefore = time.Unix(int64(tx.TimeBounds.MaxTime), 0).UTC().Format(time.RFC3339)
+		}
+	}
+
+	self := l.url + "/transactions/" + hash
+	resource.Links.Self.Href = self
+	resource.Links.Account.Href = l.url + "/accounts/" + resource.Account
+	resource.Links.Ledger.Href = fmt.Sprintf("%s/ledgers/%d", l.url, l.sequence)
+	resource.Links.Operations.Href = self + "/operations"
+	resource.Links.Payments.Href = self + "/payments"
+
+	participants := []string{resource.Account}
+	for i, op := range tx.Operations {
+		source := resource.Account
+		if op.SourceAccount != nil {
+			source = op.SourceAccount.Address()
+		}
+		participants = appendAddress(participants, source)
+
+		payment, ok := l.paymentResource(source, op, token+int64(i)+1, self)
+		if !ok {
+			continue
+		}
+		payment.TransactionHash = hash
+
+		paymentParticipants := []string{source}
+		switch op.Body.Type {
+		case xdr.OperationTypeCreateAccount:
+			paymentParticipants = appendAddress(paymentParticipants, payment.Account)
+		case xdr.OperationTypePayment:
+			paymentParticipants = appendAddress(paymentParticipants, payment.To)
+		}
+		for _, address := range paymentParticipants {
+			participants = appendAddress(participants, address)
+		}
+
+		l.payments = append(l.payments, record{
+			token:        token + int64(i) + 1,
+			participants: paymentParticipants,
+			resource:     payment,
+		})
+	}
+
+	l.transactions = append(l.transactions, record{
+		token:        token,
+		participants: participants,
+		resource:     resource,
+	})
+}
+
+// paymentResource returns the resource of `op` if it is listed as a payment.
+func (l *ledger) paymentResource(source string, op xdr.Operation, token int64, tx string) (paymentResource, bool) {
+	var resource paymentResource
+	resource.ID = fmt.Sprint(token)
+	resource.PagingToken = fmt.Sprint(token)
+	resource.SourceAccount = source
+	resource.TypeI = int32(op.Body.Type)
+	resource.Links.Self.Href = fmt.Sprintf("%s/operations/%d", l.url, token)
+	resource.Links.Transaction.Href = tx
+
+	switch op.Body.Type {
+	case xdr.OperationTypeCreateAccount:
+		body := op.Body.MustCreateAccountOp()
+		resource.Type = "create_account"
+		resource.Account = body.Destination.Address()
+		resource.Funder = source
+		resource.StartingBalance = amount.String(body.StartingBalance)
+	case xdr.OperationTypePayment:
+		body := op.Body.MustPaymentOp()
+		resource.Type = "payment"
+		resource.From = source
+		resource.To = body.Destination.Address()
+		resource.Amount = amount.String(body.Amount)
+		if err := body.Asset.Extract(&resource.AssetType, &resource.AssetCode, &resource.AssetIssuer); err != nil {
+			return resource, false
+		}
+	default:
+		return resource, false
+	}
+
+	return resource, true
+}
+
+// accountResource returns the resource describing the account `address`.
+func (l *ledger) accountResource(address string) (horizon.Account, bool) {
+	a := l.accounts[address]
+	if a == nil {
+		return horizon.Account{}, false
+	}
+
+	var resource horizon.Account
+	resource.ID = a.address
+	resource.AccountID = a.address
+	resource.Sequence = fmt.Sprint(int64(a.sequence))
+	resource.SubentryCount = int32(len(a.trustlines))
+	resource.Data = map[string]string{}
+	resource.Signers = []horizon.Signer{{
+		PublicKey: a.address,
+		Weight:    1,
+		Key:       a.address,
+		Type:      "ed25519_public_key",
+	}}
+
+	// horizon lists the native balance last
+	for _, line := range a.trustlines {
+		balance := horizon.Balance{
+			Balance: amount.String(line.balance),
+			Limit:   amount.String(line.limit),
+		}
+		line.asset.Extract(&balance.Type, &balance.Code, &balance.Issuer)
+		resource.Balances = append(resource.Balances, balance)
+	}
+	resource.Balances = append(resource.Balances, horizon.Balance{
+		Balance: amount.String(a.balance),
+		Asset:   horizon.Asset{Type: "native"},
+	})
+
+	self := l.url + "/accounts/" + a.address
+	resource.Links.Self.Href = self
+	resource.Links.Transactions.Href = self + "/transactions"
+	resource.Links.Operations.Href = self + "/operations"
+	resource.Links.Payments.Href = self + "/payments"
+	resource.Links.Effects.Href = self + "/effects"
+	resource.Links.Offers.Href = self + "/offers"
+	return resource, true
+}
+
+// minBalance returns the smallest balance the account may hold.
+func (a *account) minBalance() xdr.Int64 {
+	return xdr.Int64(2+len(a.trustlines)) * BaseReserve
+}
+
+// available returns the amount of lumens the account may spend.
+func (a *account) available() xdr.Int64 {
+	return a.balance - a.minBalance()
+}
+
+func (a *account) trustline(asset xdr.Asset) *trustline {
+	for _, line := range a.trustlines {
+		if line.asset.Equals(asset) {
+			return line
+		}
+	}
+	return nil
+}
+
+func (a *account) removeTrustline(asset xdr.Asset) {
+	for i, line := range a.trustlines {
+		if line.asset.Equals(asset) {
+			a.trustlines = append(a.trustlines[:i], a.trustlines[i+1:]...)
+			return
+		}
+	}
+}
+
+func (a *account) copy() *account {
+	result := *a
+	result.trustlines = make([]*trustline, len(a.trustlines))
+	for i, line := range a.trustlines {
+		copied := *line
+		result.trustlines[i] = &copied
+	}
+	return &result
+}
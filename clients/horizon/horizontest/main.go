@@ -0,0 +1,154 @@
+// Package horizontest provides an in-process fake horizon server, allowing the
+// code that uses a horizon client to be tested using real HTTP requests
+// without running stellar-core and horizon.
+//
+// The server keeps a small in-memory ledger: submitted transactions are
+// checked (sequence numbers, signatures, fees and balances) and applied the
+// way stellar-core would, closing a new ledger for each transaction.  Only the
+// create account, payment and change trust operations are supported; other
+// operations are rejected using the `not_implemented` problem type.
+//
+// The following endpoints are served, using the same HAL resources as
+// horizon:
+//
+//	/
+//	/accounts/{id}
+//	/accounts/{id}/payments and /payments
+//	/accounts/{id}/transactions and /transactions
+//	/transactions/{hash}
+//	/ledgers and /ledgers/{sequence}
+//	/friendbot?addr={id}
+//
+// The collections support the `cursor`, `limit` and `order` parameters, and
+// may be streamed using server sent events.
+//
+//	server := horizontest.NewServer(build.TestNetwork.Passphrase)
+//	defer server.Close()
+//	client := server.Client()
+//	_, err := client.FundAccount(address)
+package horizontest
+
+import (
+	"net/http"
+	stdtest "net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	// BaseFee is the fee, in stroops, charged for each operation of a
+	// transaction.
+	BaseFee = 100
+
+	// BaseReserve is the amount, in stroops, every account must hold for
+	// itself and each of its trustlines.
+	BaseReserve = 5000000
+
+	// FriendbotAmount is the starting balance of the accounts created by
+	// the friendbot endpoint and Fund.
+	FriendbotAmount = "10000"
+
+	// TotalCoins is the amount of lumens, in stroops, held by the root
+	// account of a new server.
+	TotalCoins = 1000000000000000000
+)
+
+// Server is a fake horizon server.  Create one using NewServer.
+type Server struct {
+	*stdtest.Server
+
+	// Passphrase is the passphrase of the network whose transactions are
+	// accepted.
+	Passphrase string
+
+	// Root is the keypair of the root account of the network, which holds
+	// every lumen when the server starts.
+	Root *keypair.Full
+
+	lock    sync.Mutex
+	ledger  *ledger
+	changed chan struct{}
+	done    chan struct{}
+	closing sync.Once
+}
+
+// NewServer starts a fake horizon server for the network identified by
+// `passphrase`.  Its ledger only contains the root account of the network.
+// Stop it using Close.
+func NewServer(passphrase string) *Server {
+	root := keypair.Master(passphrase).(*keypair.Full)
+	s := &Server{
+		Passphrase: passphrase,
+		Root:       root,
+		changed:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	s.Server = stdtest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.ledger = newLedger(s.URL, passphrase, root.Address(), time.Now())
+	return s
+}
+
+// Client returns a horizon client connected to the server.
+func (s *Server) Client() *horizon.Client {
+	return &horizon.Client{
+		URL:  s.URL,
+		HTTP: http.DefaultClient,
+	}
+}
+
+// Close ends every stream and shuts the server down.
+func (s *Server) Close() {
+	s.closing.Do(func() {
+		close(s.done)
+	})
+	s.Server.Close()
+}
+
+// Fund creates the account `address` with a starting balance of `amount`
+// lumens, submitting a transaction signed by Root.  A transaction that fails
+// is reported using a *horizon.Error, as it would be by horizon.
+func (s *Server) Fund(address string, amount string) (horizon.TransactionSuccess, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	txe, err := s.ledger.fundingEnvelope(s.Root, address, amount)
+	if err != nil {
+		return horizon.TransactionSuccess{}, err
+	}
+
+	result, problem := s.apply(txe)
+	if problem != nil {
+		return result, &horizon.Error{Problem: *problem}
+	}
+	return result, nil
+}
+
+// Submit applies the transaction envelope `txe` without going through HTTP,
+// behaving like a call to the SubmitTransaction method of a client.
+func (s *Server) Submit(txe string) (horizon.TransactionSuccess, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(txe, &envelope); err != nil {
+		return horizon.TransactionSuccess{}, &horizon.Error{Problem: malformedProblem(txe)}
+	}
+
+	result, problem := s.apply(envelope)
+	if problem != nil {
+		return result, &horizon.Error{Problem: *problem}
+	}
+	return result, nil
+}
+
+// LatestLedger returns the sequence number of the last ledger closed.
+func (s *Server) LatestLedger() int32 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.ledger.sequence
+}
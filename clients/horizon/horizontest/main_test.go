@@ -0,0 +1,264 @@
+package horizontest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func envelope(t *testing.T, client *horizon.Client, signer *keypair.Full, muts ...build.TransactionMutator) string {
+	muts = append([]build.TransactionMutator{
+		build.SourceAccount{AddressOrSeed: signer.Address()},
+		build.AutoSequence{SequenceProvider: client},
+		build.TestNetwork,
+	}, muts...)
+
+	txe := build.Transaction(muts...).SignWith(signer)
+	result, err := txe.Base64()
+	require.NoError(t, err)
+	return result
+}
+
+func random(t *testing.T) *keypair.Full {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+	return kp
+}
+
+func resultCodes(t *testing.T, err error) *horizon.TransactionResultCodes {
+	herr, ok := err.(*horizon.Error)
+	require.True(t, ok, "expected a horizon error, got %v", err)
+	codes, err := herr.ResultCodes()
+	require.NoError(t, err)
+	return codes
+}
+
+func TestServer_Accounts(t *testing.T) {
+	server := NewServer(build.TestNetwork.Passphrase)
+	defer server.Close()
+	client := server.Client()
+
+	root, err := client.LoadRoot()
+	require.NoError(t, err)
+	assert.Equal(t, build.TestNetwork.Passphrase, root.NetworkPassphrase)
+	assert.Equal(t, int32(1), root.HorizonSequence)
+
+	kp := random(t)
+	_, err = client.LoadAccount(kp.Address())
+	herr, ok := err.(*horizon.Error)
+	require.True(t, ok)
+	assert.Equal(t, 404, herr.Problem.Status)
+
+	result, err := client.FundAccount(kp.Address())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), result.Ledger)
+
+	account, err := client.LoadAccount(kp.Address())
+	require.NoError(t, err)
+	assert.Equal(t, "10000.0000000", account.GetNativeBalance())
+	assert.Equal(t, "8589934592", account.Sequence)
+
+	_, err = client.FundAccount(kp.Address())
+	assert.Equal(t, []string{"op_already_exists"}, resultCodes(t, err).OperationCodes)
+	assert.Equal(t, int32(3), server.LatestLedger())
+}
+
+func TestServer_Payments(t *testing.T) {
+	server := NewServer(build.TestNetwork.Passphrase)
+	defer server.Close()
+	client := server.Client()
+
+	issuer, holder := random(t), random(t)
+	for _, kp := range []*keypair.Full{issuer, holder} {
+		_, err := server.Fund(kp.Address(), "100")
+		require.NoError(t, err)
+	}
+
+	usd := build.CreditAsset("USD", issuer.Address())
+	pay := build.Payment(
+		build.Destination{AddressOrSeed: holder.Address()},
+		build.CreditAmount{Code: "USD", Issuer: issuer.Address(), Amount: "50"},
+	)
+
+	_, err := client.SubmitTransaction(envelope(t, client, issuer, pay))
+	codes := resultCodes(t, err)
+	assert.Equal(t, "tx_failed", codes.TransactionCode)
+	assert.Equal(t, []string{"op_no_trust"}, codes.OperationCodes)
+
+	_, err = client.SubmitTransaction(envelope(t, client, holder, build.Trust(usd.Code, usd.Issuer, build.Limit("40"))))
+	require.NoError(t, err)
+
+	_, err = client.SubmitTransaction(envelope(t, client, issuer, pay))
+	assert.Equal(t, []string{"op_line_full"}, resultCodes(t, err).OperationCodes)
+
+	_, err = client.SubmitTransaction(envelope(t, client, holder, build.Trust(usd.Code, usd.Issuer)))
+	require.NoError(t, err)
+	result, err := client.SubmitTransaction(envelope(t, client, issuer, pay, build.MemoText{Value: "hello"}))
+	require.NoError(t, err)
+
+	account, err := client.LoadAccount(holder.Address())
+	require.NoError(t, err)
+	require.Len(t, account.Balances, 2)
+	assert.Equal(t, "50.0000000", account.Balances[0].Balance)
+	assert.Equal(t, "USD", account.Balances[0].Code)
+	assert.Equal(t, int32(1), account.SubentryCount)
+
+	// the fees of the failed payments were charged too
+	account, err = client.LoadAccount(issuer.Address())
+	require.NoError(t, err)
+	assert.Equal(t, "99.9999700", account.GetNativeBalance())
+
+	tx, err := client.LoadTransaction(result.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, "text", tx.MemoType)
+	assert.Equal(t, "hello", tx.Memo)
+
+	payments, err := client.LoadPayments(horizon.ForAccount(holder.Address()))
+	require.NoError(t, err)
+	require.Len(t, payments.Embedded.Records, 2)
+	assert.Equal(t, "create_account", payments.Embedded.Records[0].Type)
+	assert.Equal(t, "payment", payments.Embedded.Records[1].Type)
+	assert.Equal(t, "50.0000000", payments.Embedded.Records[1].Amount)
+	assert.Equal(t, issuer.Address(), payments.Embedded.Records[1].From)
+
+	payment := payments.Embedded.Records[1]
+	require.NoError(t, client.LoadMemo(&payment))
+	assert.Equal(t, "hello", payment.Memo.Value)
+
+	_, err = client.SubmitTransaction(envelope(t, client, holder, build.Trust(usd.Code, usd.Issuer, build.Limit("0"))))
+	assert.Equal(t, []string{"op_invalid_limit"}, resultCodes(t, err).OperationCodes)
+}
+
+func TestServer_Submit(t *testing.T) {
+	server := NewServer(build.TestNetwork.Passphrase)
+	defer server.Close()
+	client := server.Client()
+
+	kp, other := random(t), random(t)
+	_, err := server.Fund(kp.Address(), "100")
+	require.NoError(t, err)
+
+	pay := build.Payment(
+		build.Destination{AddressOrSeed: server.Root.Address()},
+		build.NativeAmount{Amount: "1"},
+	)
+
+	_, err = client.SubmitTransaction(envelope(t, client, kp, pay, build.Sequence{Sequence: 1}))
+	assert.Equal(t, "tx_bad_seq", resultCodes(t, err).TransactionCode)
+
+	txe := build.Transaction(
+		build.SourceAccount{AddressOrSeed: kp.Address()},
+		build.AutoSequence{SequenceProvider: client},
+		build.TestNetwork,
+		pay,
+	).SignWith(other)
+	blob, err := txe.Base64()
+	require.NoError(t, err)
+	_, err = client.SubmitTransaction(blob)
+	assert.Equal(t, "tx_bad_auth", resultCodes(t, err).TransactionCode)
+
+	_, err = client.SubmitTransaction(envelope(t, client, kp, build.Payment(
+		build.Destination{AddressOrSeed: server.Root.Address()},
+		build.NativeAmount{Amount: "99"},
+	)))
+	assert.Equal(t, []string{"op_underfunded"}, resultCodes(t, err).OperationCodes)
+
+	_, err = client.SubmitTransaction("AAAA")
+	herr, ok := err.(*horizon.Error)
+	require.True(t, ok)
+	assert.Equal(t, problemTypePrefix+transactionMalformed, herr.Problem.Type)
+
+	_, err = client.SubmitTransaction(envelope(t, client, kp, build.SetOptions(build.HomeDomain("example.com"))))
+	herr, ok = err.(*horizon.Error)
+	require.True(t, ok)
+	assert.Equal(t, problemTypePrefix+"not_implemented", herr.Problem.Type)
+}
+
+func TestServer_Pages(t *testing.T) {
+	server := NewServer(build.TestNetwork.Passphrase)
+	defer server.Close()
+	client := server.Client()
+
+	var addresses []string
+	for i := 0; i < 5; i++ {
+		kp := random(t)
+		_, err := server.Fund(kp.Address(), "100")
+		require.NoError(t, err)
+		addresses = append(addresses, kp.Address())
+	}
+
+	it := horizon.NewTransactionIterator(context.Background(), client, horizon.Limit(2))
+	var ledgers []int32
+	for it.Next() {
+		ledgers = append(ledgers, it.Transaction().Ledger)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []int32{2, 3, 4, 5, 6}, ledgers)
+
+	page, err := client.LoadTransactions(horizon.OrderDesc, horizon.Limit(2))
+	require.NoError(t, err)
+	require.Len(t, page.Embedded.Records, 2)
+	assert.Equal(t, int32(6), page.Embedded.Records[0].Ledger)
+
+	page, err = client.LoadTransactions(horizon.ForAccount(addresses[2]))
+	require.NoError(t, err)
+	require.Len(t, page.Embedded.Records, 1)
+	assert.Equal(t, int32(4), page.Embedded.Records[0].Ledger)
+
+	ledger, err := client.LoadLedger(4)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), ledger.TransactionCount)
+}
+
+func TestServer_Stream(t *testing.T) {
+	server := NewServer(build.TestNetwork.Passphrase)
+	defer server.Close()
+	client := server.Client()
+
+	kp := random(t)
+	_, err := server.Fund(kp.Address(), "100")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	payments := make(chan horizon.Payment)
+	go func() {
+		client.StreamPayments(ctx, kp.Address(), nil, func(p horizon.Payment) {
+			select {
+			case payments <- p:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	next := func() horizon.Payment {
+		select {
+		case p := <-payments:
+			return p
+		case <-ctx.Done():
+			t.Fatal("no payment streamed")
+			return horizon.Payment{}
+		}
+	}
+
+	// the payments made before the stream started are sent first
+	assert.Equal(t, "create_account", next().Type)
+
+	_, err = client.SubmitTransaction(envelope(t, client, kp, build.Payment(
+		build.Destination{AddressOrSeed: server.Root.Address()},
+		build.NativeAmount{Amount: "1"},
+	)))
+	require.NoError(t, err)
+
+	p := next()
+	assert.Equal(t, "payment", p.Type)
+	assert.Equal(t, kp.Address(), p.From)
+	assert.Equal(t, "1.0000000", p.Amount)
+}
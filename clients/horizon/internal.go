@@ -4,11 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/manucorporat/sse"
 	"github.com/stellar/go/support/errors"
+	"golang.org/x/net/context"
 )
 
 var endEvent = regexp.MustCompile("(\r\n|\r|\n){2}")
@@ -60,14 +66,237 @@ func parseEvent(data []byte) (result sse.Event, err error) {
 	return
 }
 
+// splitSSE splits a stream into complete events.  An incomplete event at the
+// end of the stream, e.g. when the connection was lost, is discarded.
 func splitSSE(data []byte, atEOF bool) (advance int, token []byte, err error) {
-	if atEOF {
-		return 0, nil, nil
-	}
-
 	if loc := endEvent.FindIndex(data); loc != nil {
 		return loc[1], data[0:loc[1]], nil
 	}
 
 	return 0, nil, nil
 }
+
+// the filters supported by a collection, used by collectionURL
+const (
+	scopeAccount = 1 << iota
+	scopeLedger
+	scopeTransaction
+	scopeOperation
+	scopeAssetPair
+)
+
+// collectionURL returns the url of `collection`, built from the paging
+// parameters and filters found in `params`.  `scopes` is the set of filters
+// the collection supports.
+func (c *Client) collectionURL(collection string, scopes int, params []interface{}) (endpoint string, err error) {
+	var (
+		at     string
+		parent string
+		query  = url.Values{}
+	)
+
+	setParent := func(scope int, name string, path string) error {
+		if scopes&scope == 0 {
+			return fmt.Errorf("%s cannot be filtered using %s", collection, name)
+		}
+		if parent != "" {
+			return fmt.Errorf("%s can only be filtered by one of ForAccount, ForLedger, ForTransaction or ForOperation", collection)
+		}
+		parent = path
+		return nil
+	}
+
+	for _, param := range params {
+		switch param := param.(type) {
+		case At:
+			at = string(param)
+		case Limit:
+			query.Add("limit", strconv.Itoa(int(param)))
+		case Order:
+			query.Add("order", string(param))
+		case Cursor:
+			query.Add("cursor", string(param))
+		case ForAccount:
+			err = setParent(scopeAccount, "ForAccount", "/accounts/"+string(param))
+		case ForLedger:
+			err = setParent(scopeLedger, "ForLedger", fmt.Sprintf("/ledgers/%d", param))
+		case ForTransaction:
+			err = setParent(scopeTransaction, "ForTransaction", "/transactions/"+string(param))
+		case ForOperation:
+			err = setParent(scopeOperation, "ForOperation", "/operations/"+string(param))
+		case AssetPair:
+			if scopes&scopeAssetPair == 0 {
+				err = fmt.Errorf("%s cannot be filtered using AssetPair", collection)
+				break
+			}
+			addAssetQuery(query, "base_", param.Base)
+			addAssetQuery(query, "counter_", param.Counter)
+		default:
+			err = fmt.Errorf("Undefined parameter (%T): %+v", param, param)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	if at != "" {
+		endpoint = at
+	} else {
+		endpoint = c.URL + parent + "/" + collection
+		if len(query) > 0 {
+			endpoint += "?" + query.Encode()
+		}
+	}
+
+	// ensure our endpoint is a real url
+	_, err = url.Parse(endpoint)
+	if err != nil {
+		err = errors.Wrap(err, "failed to parse endpoint")
+	}
+	return
+}
+
+// addAssetQuery adds the query parameters identifying `asset` to `query`,
+// prefixing their names with `prefix`.
+func addAssetQuery(query url.Values, prefix string, asset Asset) {
+	query.Add(prefix+"asset_type", asset.Type)
+	if asset.Code != "" {
+		query.Add(prefix+"asset_code", asset.Code)
+	}
+	if asset.Issuer != "" {
+		query.Add(prefix+"asset_issuer", asset.Issuer)
+	}
+}
+
+// pathSegment escapes `s` so that it can be used as a single segment of a url
+// path.  url.PathEscape is not used, as it is not available before go 1.8.
+func pathSegment(s string) string {
+	return strings.Replace((&url.URL{Path: s}).EscapedPath(), "/", "%2F", -1)
+}
+
+// load loads `endpoint`, decoding the response into `object`.  The returned
+// error can be either error object or horizon.Error object.
+func (c *Client) load(endpoint string, object interface{}) error {
+	resp, err := c.HTTP.Get(endpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to load endpoint")
+	}
+
+	return decodeResponse(resp, object)
+}
+
+// contextHTTP sends the requests of `http` bound to `ctx`, so that they are
+// aborted when it is done.
+type contextHTTP struct {
+	ctx  context.Context
+	http HTTP
+}
+
+func (h contextHTTP) Do(req *http.Request) (*http.Response, error) {
+	r := *req
+	r.Cancel = h.ctx.Done()
+	return h.http.Do(&r)
+}
+
+func (h contextHTTP) Get(endpoint string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	return h.Do(req)
+}
+
+func (h contextHTTP) PostForm(endpoint string, data url.Values) (*http.Response, error) {
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return h.Do(req)
+}
+
+const (
+	// defaultStreamRetry is the delay before reconnecting a stream used until
+	// horizon provides one using the `retry` field.
+	defaultStreamRetry = time.Second
+
+	// minStreamRetry is the shortest delay before reconnecting a stream, used
+	// instead of shorter delays requested by horizon, such as `retry: 0`.
+	minStreamRetry = defaultStreamRetry / 10
+
+	// maxStreamBackoff is the longest delay before reconnecting a stream.
+	maxStreamBackoff = time.Minute
+)
+
+// streamState is the state of a stream kept across reconnections.
+type streamState struct {
+	// lastID is the id of the last event received, used to resume the stream
+	lastID string
+
+	// retry is the reconnection delay requested by horizon
+	retry time.Duration
+}
+
+// permanentError wraps an error that ends a stream, rather than causing it to
+// reconnect.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+// streamBackoff returns the delay before reconnecting a stream after
+// `failures` consecutive failed attempts.  Without failures the delay
+// requested by horizon is used as is, unless shorter than minStreamRetry.
+// Otherwise the delay doubles with each failure, up to maxStreamBackoff, and is
+// randomized to between half and all of it so that clients disconnected at
+// once (e.g. by a horizon restart) do not all reconnect at once.
+func streamBackoff(retry time.Duration, failures int) time.Duration {
+	if retry < minStreamRetry {
+		retry = minStreamRetry
+	}
+	if failures == 0 {
+		return retry
+	}
+
+	delay := retry
+	for i := 1; i < failures && delay < maxStreamBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxStreamBackoff {
+		delay = maxStreamBackoff
+	}
+
+	half := int64(delay / 2)
+	if half == 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// parseEventFields returns the `id` and `retry` fields of a single encoded
+// event.  The retry field is provided in milliseconds.
+func parseEventFields(data []byte) (id string, retry time.Duration, hasRetry bool) {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		name, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			name, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+
+		switch name {
+		case "id":
+			id = value
+		case "retry":
+			ms, err := strconv.ParseUint(value, 10, 32)
+			if err == nil {
+				retry, hasRetry = time.Duration(ms)*time.Millisecond, true
+			}
+		}
+	}
+	return
+}
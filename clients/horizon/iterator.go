@@ -0,0 +1,376 @@
+package horizon
+
+import (
+	"golang.org/x/net/context"
+)
+
+// pager walks the records of a collection page by page, following the next
+// and prev links of each page.  It implements the behavior shared by the
+// typed iterators, such as TransactionIterator, which provide access to the
+// current record.
+//
+// Records are always kept in the order of the first page loaded.  Horizon
+// returns the records of a prev link in reverse order, so such pages are
+// reversed and their links swapped when loaded.
+type pager struct {
+	ctx     context.Context
+	client  ClientInterface
+	params  []interface{}
+	load    pageLoader
+	started bool
+
+	records []interface{}
+	index   int
+	next    string
+	prev    string
+	err     error
+}
+
+// pageLoader loads the page of a collection selected by `params` using
+// `client`, returning its links and records.
+type pageLoader func(client ClientInterface, params []interface{}) (PageLinks, []interface{}, error)
+
+func newPager(ctx context.Context, client ClientInterface, params []interface{}, load pageLoader) pager {
+	// the requests of a *Client are bound to the context, so that the load of
+	// a page is aborted when it is done.  Other implementations of
+	// ClientInterface are only checked between pages.
+	if c, ok := client.(*Client); ok && c.HTTP != nil {
+		client = &Client{URL: c.URL, HTTP: contextHTTP{ctx: ctx, http: c.HTTP}}
+	}
+
+	return pager{
+		ctx:    ctx,
+		client: client,
+		params: params,
+		load:   load,
+		index:  -1,
+	}
+}
+
+// Next advances the iterator to the next record, loading the next page of the
+// collection when needed.  It returns false when there are no more records or
+// an error occurred; use Err to tell them apart.  Calling Next again after
+// reaching the end of a collection loads any record added since.
+func (p *pager) Next() bool {
+	if !p.ready() {
+		return false
+	}
+
+	if p.index+1 < len(p.records) {
+		p.index++
+		return true
+	}
+
+	params := p.params
+	if p.started {
+		if p.next == "" {
+			p.index = len(p.records)
+			return false
+		}
+		params = []interface{}{At(p.next)}
+	}
+
+	links, records, ok := p.fetch(params)
+	if !ok {
+		return false
+	}
+
+	first := !p.started
+	p.started = true
+
+	if len(records) == 0 {
+		if first {
+			p.next, p.prev = links.Next.Href, links.Prev.Href
+		}
+		p.index = len(p.records)
+		return false
+	}
+
+	p.records, p.index = records, 0
+	p.next, p.prev = links.Next.Href, links.Prev.Href
+	return true
+}
+
+// Prev moves the iterator back to the previous record, loading the previous
+// page of the collection when needed.  It returns false when there are no
+// previous records or an error occurred; use Err to tell them apart.
+func (p *pager) Prev() bool {
+	if !p.ready() {
+		return false
+	}
+
+	if p.index-1 >= 0 && p.index-1 < len(p.records) {
+		p.index--
+		return true
+	}
+
+	if p.prev == "" {
+		p.index = -1
+		return false
+	}
+
+	links, records, ok := p.fetch([]interface{}{At(p.prev)})
+	if !ok {
+		return false
+	}
+
+	if len(records) == 0 {
+		p.index = -1
+		return false
+	}
+
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+
+	p.records, p.index = records, len(records)-1
+	p.next, p.prev = links.Prev.Href, links.Next.Href
+	return true
+}
+
+// Err returns the error that stopped the iterator, if any.
+func (p *pager) Err() error {
+	return p.err
+}
+
+// PagingToken returns the paging token of the current record, or an empty
+// string when there is no current record.  Pass it as a Cursor to resume
+// iterating after the current record, e.g. after a restart.
+func (p *pager) PagingToken() string {
+	return pagingToken(p.current())
+}
+
+// current returns the current record, or nil when there is none.
+func (p *pager) current() interface{} {
+	if p.index < 0 || p.index >= len(p.records) {
+		return nil
+	}
+	return p.records[p.index]
+}
+
+// ready returns true if the iterator may continue, recording the error of the
+// iterator's context when it is done.
+func (p *pager) ready() bool {
+	if p.err != nil {
+		return false
+	}
+
+	select {
+	case <-p.ctx.Done():
+		p.err = p.ctx.Err()
+		return false
+	default:
+		return true
+	}
+}
+
+// fetch loads a page using `params`, recording any error.  The error of the
+// iterator's context is recorded when it is done during the load.
+func (p *pager) fetch(params []interface{}) (PageLinks, []interface{}, bool) {
+	links, records, err := p.load(p.client, params)
+	if err != nil {
+		p.err = err
+		if p.ctx.Err() != nil {
+			p.err = p.ctx.Err()
+		}
+		return links, nil, false
+	}
+	return links, records, true
+}
+
+// EffectIterator iterates over a collection of effects.
+type EffectIterator struct {
+	pager
+}
+
+// NewEffectIterator returns an iterator over the effects loaded by
+// `client.LoadEffects(params...)`.  It stops once `ctx` is done,
+// aborting the load of a page in progress when `client` is a *Client.
+func NewEffectIterator(ctx context.Context, client ClientInterface, params ...interface{}) *EffectIterator {
+	return &EffectIterator{newPager(ctx, client, params, func(client ClientInterface, params []interface{}) (PageLinks, []interface{}, error) {
+		page, err := client.LoadEffects(params...)
+		records := make([]interface{}, len(page.Embedded.Records))
+		for i := range page.Embedded.Records {
+			records[i] = page.Embedded.Records[i]
+		}
+		return page.Links, records, err
+	})}
+}
+
+// Effect returns the current effect.
+func (it *EffectIterator) Effect() Effect {
+	effect, _ := it.current().(Effect)
+	return effect
+}
+
+// LedgerIterator iterates over a collection of ledgers.
+type LedgerIterator struct {
+	pager
+}
+
+// NewLedgerIterator returns an iterator over the ledgers loaded by
+// `client.LoadLedgers(params...)`.  It stops once `ctx` is done,
+// aborting the load of a page in progress when `client` is a *Client.
+func NewLedgerIterator(ctx context.Context, client ClientInterface, params ...interface{}) *LedgerIterator {
+	return &LedgerIterator{newPager(ctx, client, params, func(client ClientInterface, params []interface{}) (PageLinks, []interface{}, error) {
+		page, err := client.LoadLedgers(params...)
+		records := make([]interface{}, len(page.Embedded.Records))
+		for i := range page.Embedded.Records {
+			records[i] = page.Embedded.Records[i]
+		}
+		return page.Links, records, err
+	})}
+}
+
+// Ledger returns the current ledger.
+func (it *LedgerIterator) Ledger() Ledger {
+	ledger, _ := it.current().(Ledger)
+	return ledger
+}
+
+// OfferIterator iterates over the offers of an account.
+type OfferIterator struct {
+	pager
+}
+
+// NewOfferIterator returns an iterator over the offers loaded by
+// `client.LoadAccountOffers(accountID, params...)`.  It stops once `ctx` is done,
+// aborting the load of a page in progress when `client` is a *Client.
+func NewOfferIterator(ctx context.Context, client ClientInterface, accountID string, params ...interface{}) *OfferIterator {
+	return &OfferIterator{newPager(ctx, client, params, func(client ClientInterface, params []interface{}) (PageLinks, []interface{}, error) {
+		page, err := client.LoadAccountOffers(accountID, params...)
+		records := make([]interface{}, len(page.Embedded.Records))
+		for i := range page.Embedded.Records {
+			records[i] = page.Embedded.Records[i]
+		}
+		return page.Links, records, err
+	})}
+}
+
+// Offer returns the current offer.
+func (it *OfferIterator) Offer() Offer {
+	offer, _ := it.current().(Offer)
+	return offer
+}
+
+// OperationIterator iterates over a collection of operations.
+type OperationIterator struct {
+	pager
+}
+
+// NewOperationIterator returns an iterator over the operations loaded by
+// `client.LoadOperations(params...)`.  It stops once `ctx` is done,
+// aborting the load of a page in progress when `client` is a *Client.
+func NewOperationIterator(ctx context.Context, client ClientInterface, params ...interface{}) *OperationIterator {
+	return &OperationIterator{newPager(ctx, client, params, func(client ClientInterface, params []interface{}) (PageLinks, []interface{}, error) {
+		page, err := client.LoadOperations(params...)
+		records := make([]interface{}, len(page.Embedded.Records))
+		for i := range page.Embedded.Records {
+			records[i] = page.Embedded.Records[i]
+		}
+		return page.Links, records, err
+	})}
+}
+
+// Operation returns the current operation.
+func (it *OperationIterator) Operation() Operation {
+	operation, _ := it.current().(Operation)
+	return operation
+}
+
+// PaymentIterator iterates over a collection of payments.
+type PaymentIterator struct {
+	pager
+}
+
+// NewPaymentIterator returns an iterator over the payments loaded by
+// `client.LoadPayments(params...)`.  It stops once `ctx` is done,
+// aborting the load of a page in progress when `client` is a *Client.
+func NewPaymentIterator(ctx context.Context, client ClientInterface, params ...interface{}) *PaymentIterator {
+	return &PaymentIterator{newPager(ctx, client, params, func(client ClientInterface, params []interface{}) (PageLinks, []interface{}, error) {
+		page, err := client.LoadPayments(params...)
+		records := make([]interface{}, len(page.Embedded.Records))
+		for i := range page.Embedded.Records {
+			records[i] = page.Embedded.Records[i]
+		}
+		return page.Links, records, err
+	})}
+}
+
+// Payment returns the current payment.
+func (it *PaymentIterator) Payment() Payment {
+	payment, _ := it.current().(Payment)
+	return payment
+}
+
+// TradeIterator iterates over a collection of trades.
+type TradeIterator struct {
+	pager
+}
+
+// NewTradeIterator returns an iterator over the trades loaded by
+// `client.LoadTrades(params...)`.  It stops once `ctx` is done,
+// aborting the load of a page in progress when `client` is a *Client.
+func NewTradeIterator(ctx context.Context, client ClientInterface, params ...interface{}) *TradeIterator {
+	return &TradeIterator{newPager(ctx, client, params, func(client ClientInterface, params []interface{}) (PageLinks, []interface{}, error) {
+		page, err := client.LoadTrades(params...)
+		records := make([]interface{}, len(page.Embedded.Records))
+		for i := range page.Embedded.Records {
+			records[i] = page.Embedded.Records[i]
+		}
+		return page.Links, records, err
+	})}
+}
+
+// Trade returns the current trade.
+func (it *TradeIterator) Trade() Trade {
+	trade, _ := it.current().(Trade)
+	return trade
+}
+
+// TransactionIterator iterates over a collection of transactions.
+type TransactionIterator struct {
+	pager
+}
+
+// NewTransactionIterator returns an iterator over the transactions loaded by
+// `client.LoadTransactions(params...)`.  It stops once `ctx` is done,
+// aborting the load of a page in progress when `client` is a *Client.
+func NewTransactionIterator(ctx context.Context, client ClientInterface, params ...interface{}) *TransactionIterator {
+	return &TransactionIterator{newPager(ctx, client, params, func(client ClientInterface, params []interface{}) (PageLinks, []interface{}, error) {
+		page, err := client.LoadTransactions(params...)
+		records := make([]interface{}, len(page.Embedded.Records))
+		for i := range page.Embedded.Records {
+			records[i] = page.Embedded.Records[i]
+		}
+		return page.Links, records, err
+	})}
+}
+
+// Transaction returns the current transaction.
+func (it *TransactionIterator) Transaction() Transaction {
+	transaction, _ := it.current().(Transaction)
+	return transaction
+}
+
+// pagingToken returns the paging token of a record loaded by an iterator.
+func pagingToken(record interface{}) string {
+	switch record := record.(type) {
+	case Effect:
+		return record.PT
+	case Ledger:
+		return record.PT
+	case Offer:
+		return record.PT
+	case Operation:
+		return record.PT
+	case Payment:
+		return record.PagingToken
+	case Trade:
+		return record.PT
+	case Transaction:
+		return record.PagingToken
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,134 @@
+package horizon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func transactionsPage(next, prev string, tokens ...string) TransactionsPage {
+	var page TransactionsPage
+	page.Links.Next.Href = next
+	page.Links.Prev.Href = prev
+	for _, token := range tokens {
+		page.Embedded.Records = append(page.Embedded.Records, Transaction{PagingToken: token})
+	}
+	return page
+}
+
+func TestTransactionIterator(t *testing.T) {
+	var client MockClient
+	client.On("LoadTransactions", ForAccount("GABC"), Limit(2)).
+		Return(transactionsPage("/next1", "/prev1", "1", "2"), nil)
+	client.On("LoadTransactions", At("/next1")).
+		Return(transactionsPage("/next2", "/prev2", "3"), nil)
+	client.On("LoadTransactions", At("/next2")).
+		Return(transactionsPage("/next2", "/prev3"), nil).Once()
+
+	it := NewTransactionIterator(context.Background(), &client, ForAccount("GABC"), Limit(2))
+	assert.Equal(t, "", it.PagingToken())
+
+	var tokens []string
+	for it.Next() {
+		tokens = append(tokens, it.Transaction().PagingToken)
+		assert.Equal(t, it.Transaction().PagingToken, it.PagingToken())
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"1", "2", "3"}, tokens)
+
+	// records added later are picked up by calling Next again
+	client.On("LoadTransactions", At("/next2")).
+		Return(transactionsPage("/next4", "/prev4", "4"), nil).Once()
+	require.True(t, it.Next())
+	assert.Equal(t, "4", it.PagingToken())
+	client.AssertExpectations(t)
+}
+
+func TestTransactionIterator_Prev(t *testing.T) {
+	var client MockClient
+	client.On("LoadTransactions", Cursor("4"), Limit(2)).
+		Return(transactionsPage("/asc-after-6", "/desc-before-5", "5", "6"), nil)
+
+	// horizon returns the records of a prev link in reverse order
+	client.On("LoadTransactions", At("/desc-before-5")).
+		Return(transactionsPage("/desc-before-3", "/asc-after-4", "4", "3"), nil)
+	client.On("LoadTransactions", At("/desc-before-3")).
+		Return(transactionsPage("/desc-before-1", "/asc-after-2", "2", "1"), nil)
+	client.On("LoadTransactions", At("/desc-before-1")).
+		Return(transactionsPage("/desc-before-1", "/asc-after-1"), nil)
+	client.On("LoadTransactions", At("/asc-after-2")).
+		Return(transactionsPage("/asc-after-4", "/desc-before-3", "3", "4"), nil)
+	client.On("LoadTransactions", At("/asc-after-4")).
+		Return(transactionsPage("/asc-after-6", "/desc-before-5", "5", "6"), nil)
+
+	it := NewTransactionIterator(context.Background(), &client, Cursor("4"), Limit(2))
+	require.True(t, it.Next())
+	assert.Equal(t, "5", it.PagingToken())
+
+	var tokens []string
+	for it.Prev() {
+		tokens = append(tokens, it.PagingToken())
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"4", "3", "2", "1"}, tokens)
+
+	// moving forward again follows the swapped links of the reversed pages
+	tokens = nil
+	for i := 0; i < 6 && it.Next(); i++ {
+		tokens = append(tokens, it.PagingToken())
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6"}, tokens)
+}
+
+func TestTransactionIterator_Errors(t *testing.T) {
+	var client MockClient
+	client.On("LoadTransactions").
+		Return(transactionsPage("/next1", "/prev1", "1"), nil)
+	client.On("LoadTransactions", At("/next1")).
+		Return(TransactionsPage{}, errors.New("broken"))
+
+	it := NewTransactionIterator(context.Background(), &client)
+	require.True(t, it.Next())
+	assert.False(t, it.Next())
+	assert.EqualError(t, it.Err(), "broken")
+
+	// the iterator stops once its context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	it = NewTransactionIterator(ctx, &client)
+	require.True(t, it.Next())
+	cancel()
+	assert.False(t, it.Next())
+	assert.Equal(t, context.Canceled, it.Err())
+}
+
+func TestTransactionIterator_CancelLoad(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := &Client{URL: server.URL, HTTP: http.DefaultClient}
+	it := NewTransactionIterator(ctx, client)
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	done := make(chan bool)
+	go func() { done <- it.Next() }()
+
+	select {
+	case ok := <-done:
+		assert.False(t, ok)
+		assert.Equal(t, context.Canceled, it.Err())
+	case <-time.After(5 * time.Second):
+		t.Fatal("the page load was not aborted")
+	}
+}
@@ -4,6 +4,48 @@
 // Create an instance of `Client` to customize the server used, or alternatively
 // use `DefaultTestNetClient` or `DefaultPublicNetClient` to access the SDF run
 // horizon servers.
+//
+// Collections are loaded using methods such as `LoadTransactions`, which
+// accept a variable list of parameters.  The paging parameters `Cursor`,
+// `Limit` and `Order` are supported by every collection, `At` loads a
+// previously returned link instead, and the filters `ForAccount`, `ForLedger`,
+// `ForTransaction` and `ForOperation` restrict a collection to the records of
+// a single account, ledger, transaction or operation:
+//
+//	page, err := client.LoadPayments(ForAccount(address), Limit(200), OrderDesc)
+//
+// To walk every record of a collection, use an iterator such as
+// `TransactionIterator`, which loads further pages as needed:
+//
+//	it := NewTransactionIterator(ctx, client, ForAccount(address), Cursor(saved))
+//	for it.Next() {
+//		process(it.Transaction())
+//		saved = it.PagingToken()
+//	}
+//	if it.Err() != nil {
+//		...
+//	}
+//
+// Streams, such as `StreamPayments`, reconnect whenever the connection to
+// horizon is lost and resume after the last event received, so they only
+// return once their context is done, their handler fails or horizon rejects
+// the request.
+//
+// Use a `Submitter` rather than `SubmitTransaction` to submit a transaction
+// until it is applied, recovering from timeouts and bad sequence numbers:
+//
+//	s := &Submitter{Client: client, Sign: func(tx *build.TransactionBuilder) (string, error) {
+//		txe := tx.Sign(seed)
+//		return txe.Base64()
+//	}}
+//	report, err := s.Submit(ctx, tx)
+//
+// To spread requests across several horizon servers, failing over when one
+// of them is down or lags behind, use the client of a `Pool`:
+//
+//	pool, err := NewPool(http.DefaultClient, urls...)
+//	go pool.Watch(ctx, 0)
+//	client := pool.Client()
 package horizon
 
 import (
@@ -12,6 +54,7 @@ import (
 
 	"github.com/stellar/go/build"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
 	"golang.org/x/net/context"
 )
 
@@ -45,6 +88,39 @@ const (
 	OrderDesc Order = "desc"
 )
 
+// ForAccount is a parameter that restricts a collection to the records of an
+// account, e.g. `/accounts/{id}/transactions`.
+type ForAccount string
+
+// ForLedger is a parameter that restricts a collection to the records of the
+// ledger with the provided sequence, e.g. `/ledgers/{sequence}/operations`.
+type ForLedger int32
+
+// ForTransaction is a parameter that restricts a collection to the records of
+// the transaction with the provided hash, e.g. `/transactions/{hash}/effects`.
+type ForTransaction string
+
+// ForOperation is a parameter that restricts a collection to the records of
+// the operation with the provided id, e.g. `/operations/{id}/effects`.
+type ForOperation string
+
+// AssetPair is a parameter that restricts trades to those between the Base
+// and Counter assets.
+type AssetPair struct {
+	Base    Asset
+	Counter Asset
+}
+
+// PathRequest describes a payment path search.  Paths are found from any
+// asset held by SourceAccount to DestinationAmount of DestinationAsset
+// received by DestinationAccount.
+type PathRequest struct {
+	SourceAccount      string
+	DestinationAccount string
+	DestinationAsset   Asset
+	DestinationAmount  string
+}
+
 var (
 	// ErrResultCodesNotPopulated is the error returned from a call to
 	// ResultCodes() against a `Problem` value that doesn't have the
@@ -67,12 +143,33 @@ type Client struct {
 }
 
 type ClientInterface interface {
+	FundAccount(accountID string) (TransactionSuccess, error)
 	LoadAccount(accountID string) (Account, error)
+	LoadAccountData(accountID string, key string) (AccountData, error)
 	LoadAccountOffers(accountID string, params ...interface{}) (offers OffersPage, err error)
+	LoadEffects(params ...interface{}) (EffectsPage, error)
+	LoadLedger(sequence int32) (Ledger, error)
+	LoadLedgers(params ...interface{}) (LedgersPage, error)
 	LoadMemo(p *Payment) error
+	LoadMetrics() (Metrics, error)
+	LoadOperation(id string) (Operation, error)
+	LoadOperations(params ...interface{}) (OperationsPage, error)
 	LoadOrderBook(selling Asset, buying Asset, params ...interface{}) (orderBook OrderBookSummary, err error)
+	LoadPaths(request PathRequest) (PathsPage, error)
+	LoadPayment(id string) (Payment, error)
+	LoadPayments(params ...interface{}) (PaymentsPage, error)
+	LoadRoot() (Root, error)
+	LoadTrades(params ...interface{}) (TradesPage, error)
+	LoadTransaction(hash string) (Transaction, error)
+	LoadTransactions(params ...interface{}) (TransactionsPage, error)
+	SequenceForAccount(accountID string) (xdr.SequenceNumber, error)
+	StreamEffects(ctx context.Context, cursor *Cursor, handler EffectHandler, params ...interface{}) error
 	StreamLedgers(ctx context.Context, cursor *Cursor, handler LedgerHandler) error
+	StreamOffers(ctx context.Context, accountID string, cursor *Cursor, handler OfferHandler) error
+	StreamOperations(ctx context.Context, cursor *Cursor, handler OperationHandler, params ...interface{}) error
+	StreamOrderBook(ctx context.Context, selling Asset, buying Asset, handler OrderBookHandler) error
 	StreamPayments(ctx context.Context, accountID string, cursor *Cursor, handler PaymentHandler) error
+	StreamTrades(ctx context.Context, cursor *Cursor, handler TradeHandler, params ...interface{}) error
 	StreamTransactions(ctx context.Context, accountID string, cursor *Cursor, handler TransactionHandler) error
 	SubmitTransaction(txeBase64 string) (TransactionSuccess, error)
 }
@@ -90,12 +187,27 @@ type HTTP interface {
 	PostForm(url string, data url.Values) (resp *http.Response, err error)
 }
 
+// EffectHandler is a function that is called when a new effect is received
+type EffectHandler func(Effect)
+
 // LedgerHandler is a function that is called when a new ledger is received
 type LedgerHandler func(Ledger)
 
+// OfferHandler is a function that is called when an offer is received
+type OfferHandler func(Offer)
+
+// OperationHandler is a function that is called when a new operation is received
+type OperationHandler func(Operation)
+
+// OrderBookHandler is a function that is called when an order book is received
+type OrderBookHandler func(OrderBookSummary)
+
 // PaymentHandler is a function that is called when a new payment is received
 type PaymentHandler func(Payment)
 
+// TradeHandler is a function that is called when a new trade is received
+type TradeHandler func(Trade)
+
 // TransactionHandler is a function that is called when a new transaction is received
 type TransactionHandler func(Transaction)
 
@@ -304,6 +304,189 @@ var _ = Describe("Horizon", func() {
 			Expect(ok).To(BeFalse())
 		})
 	})
+	Describe("LoadRoot", func() {
+		It("success response", func() {
+			hmock.On("GET", "https://localhost/").ReturnString(200, rootResponse)
+
+			root, err := client.LoadRoot()
+			Expect(err).To(BeNil())
+			Expect(root.HorizonVersion).To(Equal("snapshot"))
+			Expect(root.HorizonSequence).To(Equal(int32(4615)))
+			Expect(root.NetworkPassphrase).To(Equal("Test SDF Network ; September 2015"))
+		})
+	})
+
+	Describe("LoadAccountData", func() {
+		It("success response", func() {
+			hmock.On(
+				"GET",
+				"https://localhost/accounts/GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H/data/hello%20world%2Fagain",
+			).ReturnString(200, `{"value": "d29ybGQ="}`)
+
+			data, err := client.LoadAccountData("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", "hello world/again")
+			Expect(err).To(BeNil())
+			value, err := data.Decode()
+			Expect(err).To(BeNil())
+			Expect(string(value)).To(Equal("world"))
+		})
+
+		It("failure response", func() {
+			hmock.On(
+				"GET",
+				"https://localhost/accounts/GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H/data/missing",
+			).ReturnString(404, notFoundResponse)
+
+			_, err := client.LoadAccountData("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", "missing")
+			_, ok := err.(*Error)
+			Expect(ok).To(BeTrue())
+		})
+	})
+
+	Describe("LoadLedger", func() {
+		It("success response", func() {
+			hmock.On("GET", "https://localhost/ledgers/69859").ReturnString(200, ledgerResponse)
+
+			ledger, err := client.LoadLedger(69859)
+			Expect(err).To(BeNil())
+			Expect(ledger.Sequence).To(Equal(int32(69859)))
+			Expect(ledger.Hash).To(Equal("71a40c0581d8d7c1158e1d9368024c5f9fd70de17a8d277cdd96781590cc10fb"))
+		})
+	})
+
+	Describe("LoadTransactions", func() {
+		It("uses the paging parameters", func() {
+			hmock.On(
+				"GET",
+				"https://localhost/transactions?cursor=now&limit=2&order=desc",
+			).ReturnString(200, transactionsResponse)
+
+			page, err := client.LoadTransactions(Cursor("now"), Limit(2), OrderDesc)
+			Expect(err).To(BeNil())
+			Expect(len(page.Embedded.Records)).To(Equal(1))
+			Expect(page.Embedded.Records[0].Hash).To(Equal("5131aed266a639a6eb4802a92fba310454e711ded830ed899745b9e777d7110c"))
+			Expect(page.Links.Next.Href).To(Equal("https://localhost/transactions?order=desc&limit=2&cursor=12884905984"))
+		})
+
+		It("filters by account", func() {
+			hmock.On(
+				"GET",
+				"https://localhost/accounts/GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H/transactions",
+			).ReturnString(200, transactionsResponse)
+
+			page, err := client.LoadTransactions(ForAccount("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"))
+			Expect(err).To(BeNil())
+			Expect(len(page.Embedded.Records)).To(Equal(1))
+		})
+
+		It("filters by ledger", func() {
+			hmock.On(
+				"GET",
+				"https://localhost/ledgers/3/transactions",
+			).ReturnString(200, transactionsResponse)
+
+			_, err := client.LoadTransactions(ForLedger(3))
+			Expect(err).To(BeNil())
+		})
+
+		It("rejects unsupported filters", func() {
+			_, err := client.LoadTransactions(ForOperation("12884905985"))
+			Expect(err).NotTo(BeNil())
+
+			_, err = client.LoadTransactions(ForAccount("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"), ForLedger(3))
+			Expect(err).NotTo(BeNil())
+
+			_, err = client.LoadTransactions("bogus")
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("connection error", func() {
+			hmock.On("GET", "https://localhost/transactions").ReturnError("http.Client error")
+
+			_, err := client.LoadTransactions()
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("http.Client error"))
+		})
+	})
+
+	Describe("LoadOperation", func() {
+		It("success response", func() {
+			hmock.On("GET", "https://localhost/operations/12884905985").ReturnString(200, operationResponse)
+
+			op, err := client.LoadOperation("12884905985")
+			Expect(err).To(BeNil())
+			Expect(op.Type).To(Equal("manage_offer"))
+			Expect(op.OfferID).To(Equal(int64(8)))
+			Expect(op.PriceR).To(Equal(Price{N: 1, D: 2}))
+			Expect(op.SellingAssetCode).To(Equal("USD"))
+		})
+	})
+
+	Describe("LoadEffects", func() {
+		It("filters by operation", func() {
+			hmock.On(
+				"GET",
+				"https://localhost/operations/12884905985/effects?limit=1",
+			).ReturnString(200, effectsResponse)
+
+			page, err := client.LoadEffects(ForOperation("12884905985"), Limit(1))
+			Expect(err).To(BeNil())
+			Expect(len(page.Embedded.Records)).To(Equal(1))
+			effect := page.Embedded.Records[0]
+			Expect(effect.Type).To(Equal("account_credited"))
+			Expect(effect.Amount).To(Equal("10.0000000"))
+			Expect(effect.AssetType).To(Equal("native"))
+		})
+	})
+
+	Describe("LoadTrades", func() {
+		It("filters by asset pair", func() {
+			hmock.On(
+				"GET",
+				"https://localhost/trades?base_asset_type=native&counter_asset_code=USD&counter_asset_issuer=GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H&counter_asset_type=credit_alphanum4",
+			).ReturnString(200, `{"_embedded": {"records": []}}`)
+
+			page, err := client.LoadTrades(AssetPair{
+				Base:    Asset{Type: "native"},
+				Counter: Asset{"credit_alphanum4", "USD", "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"},
+			})
+			Expect(err).To(BeNil())
+			Expect(len(page.Embedded.Records)).To(Equal(0))
+		})
+	})
+
+	Describe("LoadPaths", func() {
+		It("success response", func() {
+			hmock.On(
+				"GET",
+				"https://localhost/paths?destination_account=GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA&destination_amount=10&destination_asset_code=USD&destination_asset_issuer=GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H&destination_asset_type=credit_alphanum4&source_account=GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+			).ReturnString(200, pathsResponse)
+
+			paths, err := client.LoadPaths(PathRequest{
+				SourceAccount:      "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+				DestinationAccount: "GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA",
+				DestinationAsset:   Asset{"credit_alphanum4", "USD", "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"},
+				DestinationAmount:  "10",
+			})
+			Expect(err).To(BeNil())
+			Expect(len(paths.Embedded.Records)).To(Equal(1))
+			Expect(paths.Embedded.Records[0].SourceAmount).To(Equal("20.0000000"))
+			Expect(paths.Embedded.Records[0].SourceAssetType).To(Equal("native"))
+		})
+	})
+
+	Describe("FundAccount", func() {
+		It("success response", func() {
+			hmock.On(
+				"GET",
+				"https://localhost/friendbot?addr=GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+			).ReturnString(200, submitResponse)
+
+			response, err := client.FundAccount("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+			Expect(err).To(BeNil())
+			Expect(response.Ledger).To(Equal(int32(3128812)))
+		})
+	})
+
 })
 
 var accountResponse = `{
@@ -813,3 +996,141 @@ var transactionFailure = `{
     "result_xdr": "AAAAAAAAAAD////4AAAAAA=="
   }
 }`
+
+var rootResponse = `{
+  "_links": {
+    "self": {
+      "href": "https://localhost/"
+    }
+  },
+  "horizon_version": "snapshot",
+  "core_version": "v0.6.3",
+  "history_latest_ledger": 4615,
+  "history_elder_ledger": 1,
+  "core_latest_ledger": 4615,
+  "core_elder_ledger": 1,
+  "network_passphrase": "Test SDF Network ; September 2015",
+  "protocol_version": 8
+}`
+
+var ledgerResponse = `{
+  "_links": {
+    "self": {
+      "href": "https://localhost/ledgers/69859"
+    }
+  },
+  "id": "71a40c0581d8d7c1158e1d9368024c5f9fd70de17a8d277cdd96781590cc10fb",
+  "paging_token": "300042120331264",
+  "hash": "71a40c0581d8d7c1158e1d9368024c5f9fd70de17a8d277cdd96781590cc10fb",
+  "prev_hash": "78979bed15463bfc3b0c1915acc6aec866565d70ba6565f4e67ef2d97a0436ee",
+  "sequence": 69859,
+  "transaction_count": 0,
+  "operation_count": 0,
+  "closed_at": "2017-03-08T20:01:55Z",
+  "total_coins": "100000000000.0000000",
+  "fee_pool": "1.6334300",
+  "base_fee": 100,
+  "base_reserve": "10.0000000",
+  "max_tx_set_size": 50,
+  "protocol_version": 4
+}`
+
+var transactionsResponse = `{
+  "_links": {
+    "self": {
+      "href": "https://localhost/transactions?order=desc&limit=2&cursor="
+    },
+    "next": {
+      "href": "https://localhost/transactions?order=desc&limit=2&cursor=12884905984"
+    },
+    "prev": {
+      "href": "https://localhost/transactions?order=asc&limit=2&cursor=12884905984"
+    }
+  },
+  "_embedded": {
+    "records": [
+      {
+        "id": "5131aed266a639a6eb4802a92fba310454e711ded830ed899745b9e777d7110c",
+        "paging_token": "12884905984",
+        "hash": "5131aed266a639a6eb4802a92fba310454e711ded830ed899745b9e777d7110c",
+        "ledger": 3,
+        "created_at": "2017-03-08T19:56:25Z",
+        "source_account": "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+        "source_account_sequence": "1",
+        "fee_paid": 100,
+        "operation_count": 1,
+        "memo_type": "none",
+        "signatures": []
+      }
+    ]
+  }
+}`
+
+var operationResponse = `{
+  "_links": {
+    "self": {
+      "href": "https://localhost/operations/12884905985"
+    }
+  },
+  "id": "12884905985",
+  "paging_token": "12884905985",
+  "source_account": "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+  "type": "manage_offer",
+  "type_i": 3,
+  "created_at": "2017-03-08T19:56:25Z",
+  "transaction_hash": "5131aed266a639a6eb4802a92fba310454e711ded830ed899745b9e777d7110c",
+  "amount": "100.0000000",
+  "price": "0.5000000",
+  "price_r": {
+    "n": 1,
+    "d": 2
+  },
+  "buying_asset_type": "native",
+  "selling_asset_type": "credit_alphanum4",
+  "selling_asset_code": "USD",
+  "selling_asset_issuer": "GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA",
+  "offer_id": 8
+}`
+
+var effectsResponse = `{
+  "_links": {
+    "self": {
+      "href": "https://localhost/operations/12884905985/effects?order=asc&limit=1&cursor="
+    },
+    "next": {
+      "href": "https://localhost/operations/12884905985/effects?order=asc&limit=1&cursor=12884905985-1"
+    },
+    "prev": {
+      "href": "https://localhost/operations/12884905985/effects?order=desc&limit=1&cursor=12884905985-1"
+    }
+  },
+  "_embedded": {
+    "records": [
+      {
+        "id": "0000000012884905985-0000000001",
+        "paging_token": "12884905985-1",
+        "account": "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+        "type": "account_credited",
+        "type_i": 2,
+        "asset_type": "native",
+        "amount": "10.0000000"
+      }
+    ]
+  }
+}`
+
+var pathsResponse = `{
+  "_embedded": {
+    "records": [
+      {
+        "source_asset_type": "native",
+        "source_amount": "20.0000000",
+        "destination_asset_type": "credit_alphanum4",
+        "destination_asset_code": "USD",
+        "destination_asset_issuer": "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+        "destination_amount": "10.0000000",
+        "path": []
+      }
+    ]
+  }
+}`
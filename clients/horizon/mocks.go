@@ -1,6 +1,7 @@
 package horizon
 
 import (
+	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/net/context"
 )
@@ -10,12 +11,24 @@ type MockClient struct {
 	mock.Mock
 }
 
+// FundAccount is a mocking a method
+func (m *MockClient) FundAccount(accountID string) (TransactionSuccess, error) {
+	a := m.Called(accountID)
+	return a.Get(0).(TransactionSuccess), a.Error(1)
+}
+
 // LoadAccount is a mocking a method
 func (m *MockClient) LoadAccount(accountID string) (Account, error) {
 	a := m.Called(accountID)
 	return a.Get(0).(Account), a.Error(1)
 }
 
+// LoadAccountData is a mocking a method
+func (m *MockClient) LoadAccountData(accountID string, key string) (AccountData, error) {
+	a := m.Called(accountID, key)
+	return a.Get(0).(AccountData), a.Error(1)
+}
+
 // LoadAccountOffers is a mocking a method
 func (m *MockClient) LoadAccountOffers(accountID string, params ...interface{}) (offers OffersPage, err error) {
 	// There is no way to simply call:
@@ -31,30 +44,144 @@ func (m *MockClient) LoadAccountOffers(accountID string, params ...interface{})
 	return a.Get(0).(OffersPage), a.Error(1)
 }
 
+// LoadEffects is a mocking a method
+func (m *MockClient) LoadEffects(params ...interface{}) (EffectsPage, error) {
+	a := m.Called(params...)
+	return a.Get(0).(EffectsPage), a.Error(1)
+}
+
+// LoadLedger is a mocking a method
+func (m *MockClient) LoadLedger(sequence int32) (Ledger, error) {
+	a := m.Called(sequence)
+	return a.Get(0).(Ledger), a.Error(1)
+}
+
+// LoadLedgers is a mocking a method
+func (m *MockClient) LoadLedgers(params ...interface{}) (LedgersPage, error) {
+	a := m.Called(params...)
+	return a.Get(0).(LedgersPage), a.Error(1)
+}
+
 // LoadMemo is a mocking a method
 func (m *MockClient) LoadMemo(p *Payment) error {
 	a := m.Called(p)
 	return a.Error(0)
 }
 
+// LoadMetrics is a mocking a method
+func (m *MockClient) LoadMetrics() (Metrics, error) {
+	a := m.Called()
+	return a.Get(0).(Metrics), a.Error(1)
+}
+
+// LoadOperation is a mocking a method
+func (m *MockClient) LoadOperation(id string) (Operation, error) {
+	a := m.Called(id)
+	return a.Get(0).(Operation), a.Error(1)
+}
+
+// LoadOperations is a mocking a method
+func (m *MockClient) LoadOperations(params ...interface{}) (OperationsPage, error) {
+	a := m.Called(params...)
+	return a.Get(0).(OperationsPage), a.Error(1)
+}
+
 // LoadOrderBook is a mocking a method
 func (m *MockClient) LoadOrderBook(selling Asset, buying Asset, params ...interface{}) (orderBook OrderBookSummary, err error) {
 	a := m.Called(selling, buying, params)
 	return a.Get(0).(OrderBookSummary), a.Error(1)
 }
 
+// LoadPaths is a mocking a method
+func (m *MockClient) LoadPaths(request PathRequest) (PathsPage, error) {
+	a := m.Called(request)
+	return a.Get(0).(PathsPage), a.Error(1)
+}
+
+// LoadPayment is a mocking a method
+func (m *MockClient) LoadPayment(id string) (Payment, error) {
+	a := m.Called(id)
+	return a.Get(0).(Payment), a.Error(1)
+}
+
+// LoadPayments is a mocking a method
+func (m *MockClient) LoadPayments(params ...interface{}) (PaymentsPage, error) {
+	a := m.Called(params...)
+	return a.Get(0).(PaymentsPage), a.Error(1)
+}
+
+// LoadRoot is a mocking a method
+func (m *MockClient) LoadRoot() (Root, error) {
+	a := m.Called()
+	return a.Get(0).(Root), a.Error(1)
+}
+
+// LoadTrades is a mocking a method
+func (m *MockClient) LoadTrades(params ...interface{}) (TradesPage, error) {
+	a := m.Called(params...)
+	return a.Get(0).(TradesPage), a.Error(1)
+}
+
+// LoadTransaction is a mocking a method
+func (m *MockClient) LoadTransaction(hash string) (Transaction, error) {
+	a := m.Called(hash)
+	return a.Get(0).(Transaction), a.Error(1)
+}
+
+// LoadTransactions is a mocking a method
+func (m *MockClient) LoadTransactions(params ...interface{}) (TransactionsPage, error) {
+	a := m.Called(params...)
+	return a.Get(0).(TransactionsPage), a.Error(1)
+}
+
+// SequenceForAccount is a mocking a method
+func (m *MockClient) SequenceForAccount(accountID string) (xdr.SequenceNumber, error) {
+	a := m.Called(accountID)
+	return a.Get(0).(xdr.SequenceNumber), a.Error(1)
+}
+
+// StreamEffects is a mocking a method
+func (m *MockClient) StreamEffects(ctx context.Context, cursor *Cursor, handler EffectHandler, params ...interface{}) error {
+	a := m.Called(ctx, cursor, handler, params)
+	return a.Error(0)
+}
+
 // StreamLedgers is a mocking a method
 func (m *MockClient) StreamLedgers(ctx context.Context, cursor *Cursor, handler LedgerHandler) error {
 	a := m.Called(ctx, cursor, handler)
 	return a.Error(0)
 }
 
+// StreamOffers is a mocking a method
+func (m *MockClient) StreamOffers(ctx context.Context, accountID string, cursor *Cursor, handler OfferHandler) error {
+	a := m.Called(ctx, accountID, cursor, handler)
+	return a.Error(0)
+}
+
+// StreamOperations is a mocking a method
+func (m *MockClient) StreamOperations(ctx context.Context, cursor *Cursor, handler OperationHandler, params ...interface{}) error {
+	a := m.Called(ctx, cursor, handler, params)
+	return a.Error(0)
+}
+
+// StreamOrderBook is a mocking a method
+func (m *MockClient) StreamOrderBook(ctx context.Context, selling Asset, buying Asset, handler OrderBookHandler) error {
+	a := m.Called(ctx, selling, buying, handler)
+	return a.Error(0)
+}
+
 // StreamPayments is a mocking a method
 func (m *MockClient) StreamPayments(ctx context.Context, accountID string, cursor *Cursor, handler PaymentHandler) error {
 	a := m.Called(ctx, accountID, cursor, handler)
 	return a.Error(0)
 }
 
+// StreamTrades is a mocking a method
+func (m *MockClient) StreamTrades(ctx context.Context, cursor *Cursor, handler TradeHandler, params ...interface{}) error {
+	a := m.Called(ctx, cursor, handler, params)
+	return a.Error(0)
+}
+
 // StreamTransactions is a mocking a method
 func (m *MockClient) StreamTransactions(ctx context.Context, accountID string, cursor *Cursor, handler TransactionHandler) error {
 	a := m.Called(ctx, accountID, cursor, handler)
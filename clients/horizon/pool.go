@@ -0,0 +1,385 @@
+package horizon
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"golang.org/x/net/context"
+)
+
+const (
+	// DefaultMaxLag is the number of ledgers a node of a Pool whose MaxLag is
+	// zero may lag behind the most up-to-date node before it is considered
+	// stale.
+	DefaultMaxLag = 2
+
+	// DefaultProbeInterval is the delay between probes used by Watch when
+	// it is given no interval.
+	DefaultProbeInterval = 5 * time.Second
+)
+
+// ErrNoNodes is returned by NewPool when no URL is provided.
+var ErrNoNodes = errors.New("no horizon url provided")
+
+// Pool spreads the requests of a client across several horizon servers
+// ("nodes") serving the same network.  It implements the HTTP interface, so
+// use it as the HTTP field of a Client, for example using Client:
+//
+//	pool, err := horizon.NewPool(http.DefaultClient, urls...)
+//	go pool.Watch(ctx, 0)
+//	client := pool.Client()
+//
+// The health of each node is probed by loading its root resource.  Requests
+// are sent to the nodes whose `history_latest_ledger` is at most MaxLag
+// ledgers behind the most up-to-date node, in turn.  A node whose request
+// fails or that responds with a 5xx status is considered down until it is
+// probed successfully again, and GET requests are retried using the next
+// node.  Streams stick to a single node, so that events are never replayed
+// from a node that lags behind, until that node fails.
+//
+// Requests to a URL not served by one of the nodes, such as a link to
+// another server, are sent as is.
+type Pool struct {
+	// HTTP is used to make the requests to the nodes.
+	HTTP HTTP
+
+	// MaxLag is the number of ledgers a node may lag behind before it is
+	// considered stale.  DefaultMaxLag is used when zero.
+	MaxLag int32
+
+	lock   sync.Mutex
+	nodes  []*poolNode
+	next   int
+	stream *poolNode
+	probed bool
+}
+
+// NodeStatus describes the health of a node of a Pool, as of its last probe
+// or request.
+type NodeStatus struct {
+	URL string
+
+	// Healthy is false when the last probe or request of the node failed,
+	// which is described by Err.
+	Healthy bool
+	Err     error
+
+	// LatestLedger is the `history_latest_ledger` reported by the node when
+	// it was last probed, and ProbedAt the time of that probe.
+	LatestLedger int32
+	ProbedAt     time.Time
+}
+
+type poolNode struct {
+	base *url.URL
+	NodeStatus
+}
+
+// NewPool returns a pool of the horizon servers at `urls`, which are sent
+// requests using `client`.
+func NewPool(client HTTP, urls ...string) (*Pool, error) {
+	if len(urls) == 0 {
+		return nil, ErrNoNodes
+	}
+
+	p := &Pool{HTTP: client}
+	for _, raw := range urls {
+		raw = strings.TrimRight(raw, "/")
+		base, err := url.Parse(raw)
+		if err != nil || base.Host == "" {
+			return nil, errors.Errorf("invalid horizon url: %q", raw)
+		}
+
+		p.nodes = append(p.nodes, &poolNode{
+			base:       base,
+			NodeStatus: NodeStatus{URL: raw, Healthy: true},
+		})
+	}
+
+	return p, nil
+}
+
+// Client returns a client whose requests are spread across the pool.
+func (p *Pool) Client() *Client {
+	return &Client{
+		URL:  p.nodes[0].URL,
+		HTTP: p,
+	}
+}
+
+// Nodes returns the status of every node of the pool.
+func (p *Pool) Nodes() []NodeStatus {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	result := make([]NodeStatus, len(p.nodes))
+	for i, n := range p.nodes {
+		result[i] = n.NodeStatus
+	}
+	return result
+}
+
+// Probe loads the root resource of every node concurrently, recording its
+// health and latest ledger.
+func (p *Pool) Probe() {
+	var wg sync.WaitGroup
+	for _, n := range p.nodes {
+		wg.Add(1)
+		go func(n *poolNode) {
+			defer wg.Done()
+
+			var root Root
+			resp, err := p.HTTP.Get(n.URL + "/")
+			if err == nil {
+				err = decodeResponse(resp, &root)
+			}
+
+			p.lock.Lock()
+			defer p.lock.Unlock()
+			n.ProbedAt = time.Now()
+			n.Healthy = err == nil
+			n.Err = err
+			if err == nil {
+				n.LatestLedger = root.HorizonSequence
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	p.lock.Lock()
+	p.probed = true
+	p.lock.Unlock()
+}
+
+// Watch probes the nodes of the pool every `interval`, or every
+// DefaultProbeInterval when it is zero, until ctx is done.
+func (p *Pool) Watch(ctx context.Context, interval time.Duration) {
+	if interval == 0 {
+		interval = DefaultProbeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.Probe()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Do implements HTTP.
+func (p *Pool) Do(req *http.Request) (*http.Response, error) {
+	if p.route(req.URL) == "" {
+		return p.HTTP.Do(req)
+	}
+
+	stream := strings.Contains(req.Header.Get("Accept"), "text/event-stream")
+	retry := req.Method == "GET" || req.Method == "HEAD"
+
+	var (
+		resp *http.Response
+		err  error
+	)
+	for i, n := range p.candidates(stream) {
+		if i > 0 {
+			if !retry {
+				break
+			}
+			if resp != nil {
+				resp.Body.Close()
+			}
+		}
+
+		attempt := new(http.Request)
+		*attempt = *req
+		attempt.URL = n.rebase(req.URL, p.route(req.URL))
+		attempt.Host = ""
+
+		resp, err = p.HTTP.Do(attempt)
+		switch {
+		case err != nil && canceled(req):
+			// the caller gave up on the request, which says nothing about
+			// the health of the node
+			return nil, err
+		case err != nil:
+			p.failed(n, err)
+		case resp.StatusCode >= 500 && !timedOutSubmission(req, resp):
+			p.failed(n, errors.Errorf("horizon responded with status %d", resp.StatusCode))
+		default:
+			p.succeeded(n, stream)
+			return resp, nil
+		}
+	}
+
+	return resp, err
+}
+
+// canceled returns true if `req` was canceled using its Cancel channel.
+func canceled(req *http.Request) bool {
+	select {
+	case <-req.Cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// Get implements HTTP.
+func (p *Pool) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return p.Do(req)
+}
+
+// PostForm implements HTTP.
+func (p *Pool) PostForm(url string, data url.Values) (*http.Response, error) {
+	req, err := http.NewRequest("POST", url, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return p.Do(req)
+}
+
+// route returns the path of `u` relative to the node serving it, or an empty
+// string when no node serves it.
+func (p *Pool) route(u *url.URL) string {
+	for _, n := range p.nodes {
+		if u.Scheme != n.base.Scheme || u.Host != n.base.Host {
+			continue
+		}
+
+		prefix := strings.TrimRight(n.base.Path, "/")
+		if strings.HasPrefix(u.Path, prefix) {
+			path := strings.TrimPrefix(u.Path, prefix)
+			if path == "" {
+				path = "/"
+			}
+			return path
+		}
+	}
+	return ""
+}
+
+// rebase returns a copy of `u` pointing to `path` on the node.
+func (n *poolNode) rebase(u *url.URL, path string) *url.URL {
+	result := *u
+	result.Scheme = n.base.Scheme
+	result.Host = n.base.Host
+	result.Path = strings.TrimRight(n.base.Path, "/") + path
+	result.RawPath = ""
+	return &result
+}
+
+// candidates returns the nodes to try, in order: the up-to-date nodes first,
+// starting with the next node in turn (or the node streams stick to), then
+// the stale nodes from the most to the least up-to-date, and finally the
+// nodes that are down.
+func (p *Pool) candidates(stream bool) []*poolNode {
+	p.lock.Lock()
+	probed := p.probed
+	p.lock.Unlock()
+	if !probed {
+		p.Probe()
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	maxLag := p.MaxLag
+	if maxLag == 0 {
+		maxLag = DefaultMaxLag
+	}
+
+	var best int32
+	for _, n := range p.nodes {
+		if n.Healthy && n.LatestLedger > best {
+			best = n.LatestLedger
+		}
+	}
+
+	var fresh, stale, down []*poolNode
+	for i := range p.nodes {
+		// rotate the nodes so that fresh ones are used in turn
+		n := p.nodes[(p.next+i)%len(p.nodes)]
+		switch {
+		case !n.Healthy:
+			down = append(down, n)
+		case best-n.LatestLedger > maxLag:
+			stale = append(stale, n)
+		default:
+			fresh = append(fresh, n)
+		}
+	}
+
+	if stream {
+		// streams start on the most up-to-date node, and then stick to it
+		// for as long as it is up-to-date
+		byLatestLedger(fresh)
+		for i, n := range fresh {
+			if n == p.stream {
+				copy(fresh[1:i+1], fresh[:i])
+				fresh[0] = n
+			}
+		}
+	} else {
+		p.next = (p.next + 1) % len(p.nodes)
+	}
+	byLatestLedger(stale)
+
+	result := append(fresh, stale...)
+	return append(result, down...)
+}
+
+func (p *Pool) succeeded(n *poolNode, stream bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	n.Healthy = true
+	n.Err = nil
+	if stream {
+		p.stream = n
+	}
+}
+
+func (p *Pool) failed(n *poolNode, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	n.Healthy = false
+	n.Err = err
+	if p.stream == n {
+		p.stream = nil
+	}
+}
+
+// byLatestLedger sorts `nodes` by latest ledger, the most up-to-date first,
+// keeping the order of nodes at the same ledger.
+func byLatestLedger(nodes []*poolNode) {
+	for i := 1; i < len(nodes); i++ {
+		for j := i; j > 0 && nodes[j].LatestLedger > nodes[j-1].LatestLedger; j-- {
+			nodes[j], nodes[j-1] = nodes[j-1], nodes[j]
+		}
+	}
+}
+
+// timedOutSubmission returns true if `resp` reports that horizon timed out
+// waiting for the transaction submitted by `req` to be applied, which does
+// not mean the node is unhealthy.
+func timedOutSubmission(req *http.Request, resp *http.Response) bool {
+	return req.Method == "POST" && resp.StatusCode == http.StatusGatewayTimeout
+}
+
+// ensure that Pool implements HTTP
+var _ HTTP = &Pool{}
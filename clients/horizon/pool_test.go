@@ -0,0 +1,219 @@
+package horizon
+
+import (
+	"fmt"
+	"net/http"
+	stdtest "net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// poolTestNode is a horizon server at ledger `latest` that responds to every
+// request but the root resource with `status`.
+type poolTestNode struct {
+	*stdtest.Server
+	name string
+
+	lock     sync.Mutex
+	latest   int32
+	status   int
+	requests int
+}
+
+func newPoolTestNode(name string, latest int32) *poolTestNode {
+	n := &poolTestNode{name: name, latest: latest, status: http.StatusOK}
+	n.Server = stdtest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.lock.Lock()
+		defer n.lock.Unlock()
+
+		if r.URL.Path == "/" {
+			fmt.Fprintf(w, `{"history_latest_ledger": %d}`, n.latest)
+			return
+		}
+
+		n.requests++
+		w.WriteHeader(n.status)
+		if n.status >= 400 {
+			fmt.Fprintf(w, `{"type": "server_error", "status": %d}`, n.status)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %q, "account_id": %q, "sequence": "1"}`, n.name, n.name)
+	}))
+	return n
+}
+
+func (n *poolTestNode) set(latest int32, status int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.latest, n.status = latest, status
+}
+
+func (n *poolTestNode) served() int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.requests
+}
+
+func newTestPool(t *testing.T, nodes ...*poolTestNode) *Pool {
+	var urls []string
+	for _, n := range nodes {
+		urls = append(urls, n.URL)
+	}
+
+	pool, err := NewPool(http.DefaultClient, urls...)
+	require.NoError(t, err)
+	return pool
+}
+
+func TestPool_PrefersUpToDateNodes(t *testing.T) {
+	a, b, c := newPoolTestNode("a", 10), newPoolTestNode("b", 20), newPoolTestNode("c", 19)
+	defer a.Close()
+	defer b.Close()
+	defer c.Close()
+
+	client := newTestPool(t, a, b, c).Client()
+	for i := 0; i < 6; i++ {
+		_, err := client.LoadAccount("x")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 0, a.served())
+	assert.Equal(t, 6, b.served()+c.served())
+	assert.NotZero(t, b.served())
+	assert.NotZero(t, c.served())
+}
+
+func TestPool_Failover(t *testing.T) {
+	a, b := newPoolTestNode("a", 20), newPoolTestNode("b", 20)
+	defer a.Close()
+	defer b.Close()
+
+	pool := newTestPool(t, a, b)
+	client := pool.Client()
+	a.set(20, http.StatusServiceUnavailable)
+
+	for i := 0; i < 3; i++ {
+		account, err := client.LoadAccount("x")
+		require.NoError(t, err)
+		assert.Equal(t, "b", account.ID)
+	}
+	assert.Equal(t, 1, a.served())
+
+	nodes := pool.Nodes()
+	assert.False(t, nodes[0].Healthy)
+	assert.Error(t, nodes[0].Err)
+	assert.True(t, nodes[1].Healthy)
+
+	// a node is used again once probed successfully, unless it is stale
+	a.set(20, http.StatusOK)
+	b.set(30, http.StatusOK)
+	pool.Probe()
+	_, err := client.LoadAccount("x")
+	require.NoError(t, err)
+	assert.Equal(t, 1, a.served())
+	assert.Equal(t, int32(20), pool.Nodes()[0].LatestLedger)
+
+	// requests are sent to the remaining nodes when every node fails
+	b.set(30, http.StatusInternalServerError)
+	account, err := client.LoadAccount("x")
+	require.NoError(t, err)
+	assert.Equal(t, "a", account.ID)
+
+	// submissions are not retried
+	a.set(30, http.StatusInternalServerError)
+	b.set(30, http.StatusInternalServerError)
+	pool.Probe()
+	before := a.served() + b.served()
+	_, err = client.SubmitTransaction("AAAA")
+	herr, ok := err.(*Error)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, herr.Problem.Status)
+	assert.Equal(t, before+1, a.served()+b.served())
+}
+
+func TestPool_StickyStreams(t *testing.T) {
+	a, b := newPoolTestNode("a", 20), newPoolTestNode("b", 21)
+	defer a.Close()
+	defer b.Close()
+
+	pool := newTestPool(t, a, b)
+	stream := func() string {
+		req, err := http.NewRequest("GET", a.URL+"/ledgers", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "text/event-stream")
+
+		var account Account
+		resp, err := pool.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, decodeResponse(resp, &account))
+		return account.ID
+	}
+
+	// streams start on the most up-to-date node, even when requested from
+	// another one
+	assert.Equal(t, "b", stream())
+
+	a.set(22, http.StatusOK)
+	pool.Probe()
+	assert.Equal(t, "b", stream())
+	_, err := pool.Client().LoadAccount("x")
+	require.NoError(t, err)
+	assert.Equal(t, "b", stream())
+
+	b.set(21, http.StatusServiceUnavailable)
+	assert.Equal(t, "a", stream())
+	b.set(22, http.StatusOK)
+	pool.Probe()
+	assert.Equal(t, "a", stream())
+}
+
+func TestPool_OtherServers(t *testing.T) {
+	a, other := newPoolTestNode("a", 20), newPoolTestNode("other", 1)
+	defer a.Close()
+	defer other.Close()
+
+	pool := newTestPool(t, a)
+	resp, err := pool.Get(other.URL + "/accounts/x")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, other.served())
+	assert.Equal(t, 0, a.served())
+
+	_, err = NewPool(http.DefaultClient)
+	assert.Equal(t, ErrNoNodes, err)
+	_, err = NewPool(http.DefaultClient, "not a url")
+	assert.Error(t, err)
+}
+
+func TestPool_CanceledRequests(t *testing.T) {
+	a, b := newPoolTestNode("a", 20), newPoolTestNode("b", 20)
+	defer a.Close()
+	defer b.Close()
+
+	pool := newTestPool(t, a, b)
+	pool.Probe()
+
+	// hold the response of every node until the request is canceled
+	a.lock.Lock()
+	b.lock.Lock()
+
+	cancel := make(chan struct{})
+	req, err := http.NewRequest("GET", a.URL+"/accounts/x", nil)
+	require.NoError(t, err)
+	req.Cancel = cancel
+	time.AfterFunc(10*time.Millisecond, func() { close(cancel) })
+
+	_, err = pool.Do(req)
+	a.lock.Unlock()
+	b.lock.Unlock()
+	assert.Error(t, err)
+
+	for _, n := range pool.Nodes() {
+		assert.True(t, n.Healthy)
+		assert.NoError(t, n.Err)
+	}
+}
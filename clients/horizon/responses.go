@@ -81,6 +81,16 @@ type Asset struct {
 	Issuer string `json:"asset_issuer,omitempty"`
 }
 
+// AccountData is the value of a single data entry of an account.
+type AccountData struct {
+	Value string `json:"value"`
+}
+
+// Decode returns the decoded value of the data entry.
+func (d AccountData) Decode() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(d.Value)
+}
+
 type Balance struct {
 	Balance string `json:"balance"`
 	Limit   string `json:"limit,omitempty"`
@@ -117,6 +127,58 @@ type Ledger struct {
 	ProtocolVersion  int32     `json:"protocol_version"`
 }
 
+// Effect is a single effect.  Which fields are populated depends on the type
+// of the effect, identified by Type.
+type Effect struct {
+	Links struct {
+		Operation Link `json:"operation"`
+		Succeeds  Link `json:"succeeds"`
+		Precedes  Link `json:"precedes"`
+	} `json:"_links"`
+
+	ID      string `json:"id"`
+	PT      string `json:"paging_token"`
+	Account string `json:"account"`
+	Type    string `json:"type"`
+	TypeI   int32  `json:"type_i"`
+
+	// account_created fields
+	StartingBalance string `json:"starting_balance"`
+
+	// account_credited/account_debited/trustline_* fields
+	AssetType   string `json:"asset_type"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer"`
+	Amount      string `json:"amount"`
+	Limit       string `json:"limit"`
+	Trustor     string `json:"trustor"`
+
+	// account_*_updated fields
+	LowThreshold  int32  `json:"low_threshold"`
+	MedThreshold  int32  `json:"med_threshold"`
+	HighThreshold int32  `json:"high_threshold"`
+	HomeDomain    string `json:"home_domain"`
+	AuthRequired  *bool  `json:"auth_required_flag"`
+	AuthRevokable *bool  `json:"auth_revokable_flag"`
+
+	// signer_* fields
+	Weight    int32  `json:"weight"`
+	PublicKey string `json:"public_key"`
+	Key       string `json:"key"`
+
+	// trade fields
+	Seller            string `json:"seller"`
+	OfferID           int64  `json:"offer_id"`
+	SoldAmount        string `json:"sold_amount"`
+	SoldAssetType     string `json:"sold_asset_type"`
+	SoldAssetCode     string `json:"sold_asset_code"`
+	SoldAssetIssuer   string `json:"sold_asset_issuer"`
+	BoughtAmount      string `json:"bought_amount"`
+	BoughtAssetType   string `json:"bought_asset_type"`
+	BoughtAssetCode   string `json:"bought_asset_code"`
+	BoughtAssetIssuer string `json:"bought_asset_issuer"`
+}
+
 type Link struct {
 	Href      string `json:"href"`
 	Templated bool   `json:"templated,omitempty"`
@@ -138,6 +200,86 @@ type Offer struct {
 	Price   string `json:"price"`
 }
 
+// Metrics is a snapshot of the metrics collected by horizon, keyed by metric
+// name.
+type Metrics map[string]json.RawMessage
+
+// Operation is a single operation.  Which fields are populated depends on the
+// type of the operation, identified by Type.
+type Operation struct {
+	Links struct {
+		Self        Link `json:"self"`
+		Transaction Link `json:"transaction"`
+		Effects     Link `json:"effects"`
+		Succeeds    Link `json:"succeeds"`
+		Precedes    Link `json:"precedes"`
+	} `json:"_links"`
+
+	ID              string    `json:"id"`
+	PT              string    `json:"paging_token"`
+	SourceAccount   string    `json:"source_account"`
+	Type            string    `json:"type"`
+	TypeI           int32     `json:"type_i"`
+	LedgerCloseTime time.Time `json:"created_at"`
+	TransactionHash string    `json:"transaction_hash"`
+
+	// create_account/account_merge fields
+	StartingBalance string `json:"starting_balance"`
+	Funder          string `json:"funder"`
+	Account         string `json:"account"`
+	Into            string `json:"into"`
+
+	// payment/path_payment/change_trust/allow_trust fields
+	AssetType   string `json:"asset_type"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Amount      string `json:"amount"`
+
+	// path_payment fields
+	Path              []Asset `json:"path"`
+	SourceMax         string  `json:"source_max"`
+	SourceAssetType   string  `json:"source_asset_type"`
+	SourceAssetCode   string  `json:"source_asset_code"`
+	SourceAssetIssuer string  `json:"source_asset_issuer"`
+
+	// manage_offer/create_passive_offer fields
+	OfferID            int64  `json:"offer_id"`
+	Price              string `json:"price"`
+	PriceR             Price  `json:"price_r"`
+	BuyingAssetType    string `json:"buying_asset_type"`
+	BuyingAssetCode    string `json:"buying_asset_code"`
+	BuyingAssetIssuer  string `json:"buying_asset_issuer"`
+	SellingAssetType   string `json:"selling_asset_type"`
+	SellingAssetCode   string `json:"selling_asset_code"`
+	SellingAssetIssuer string `json:"selling_asset_issuer"`
+
+	// set_options fields
+	HomeDomain      string   `json:"home_domain"`
+	InflationDest   string   `json:"inflation_dest"`
+	MasterKeyWeight *int     `json:"master_key_weight"`
+	SignerKey       string   `json:"signer_key"`
+	SignerWeight    *int     `json:"signer_weight"`
+	SetFlags        []int    `json:"set_flags"`
+	SetFlagsS       []string `json:"set_flags_s"`
+	ClearFlags      []int    `json:"clear_flags"`
+	ClearFlagsS     []string `json:"clear_flags_s"`
+	LowThreshold    *int     `json:"low_threshold"`
+	MedThreshold    *int     `json:"med_threshold"`
+	HighThreshold   *int     `json:"high_threshold"`
+
+	// change_trust/allow_trust fields
+	Limit     string `json:"limit"`
+	Trustee   string `json:"trustee"`
+	Trustor   string `json:"trustor"`
+	Authorize bool   `json:"authorize"`
+
+	// manage_data fields
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
 type OrderBookSummary struct {
 	Bids    []PriceLevel `json:"bids"`
 	Asks    []PriceLevel `json:"asks"`
@@ -170,17 +312,70 @@ type Signer struct {
 	Type      string `json:"type"`
 }
 
+// PageLinks are the links of a page of records, used to load the previous and
+// next pages.
+type PageLinks struct {
+	Self Link `json:"self"`
+	Next Link `json:"next"`
+	Prev Link `json:"prev"`
+}
+
 type OffersPage struct {
-	Links struct {
-		Self Link `json:"self"`
-		Next Link `json:"next"`
-		Prev Link `json:"prev"`
-	} `json:"_links"`
+	Links    PageLinks `json:"_links"`
 	Embedded struct {
 		Records []Offer `json:"records"`
 	} `json:"_embedded"`
 }
 
+type LedgersPage struct {
+	Links    PageLinks `json:"_links"`
+	Embedded struct {
+		Records []Ledger `json:"records"`
+	} `json:"_embedded"`
+}
+
+type TransactionsPage struct {
+	Links    PageLinks `json:"_links"`
+	Embedded struct {
+		Records []Transaction `json:"records"`
+	} `json:"_embedded"`
+}
+
+type OperationsPage struct {
+	Links    PageLinks `json:"_links"`
+	Embedded struct {
+		Records []Operation `json:"records"`
+	} `json:"_embedded"`
+}
+
+type PaymentsPage struct {
+	Links    PageLinks `json:"_links"`
+	Embedded struct {
+		Records []Payment `json:"records"`
+	} `json:"_embedded"`
+}
+
+type EffectsPage struct {
+	Links    PageLinks `json:"_links"`
+	Embedded struct {
+		Records []Effect `json:"records"`
+	} `json:"_embedded"`
+}
+
+type TradesPage struct {
+	Links    PageLinks `json:"_links"`
+	Embedded struct {
+		Records []Trade `json:"records"`
+	} `json:"_embedded"`
+}
+
+type PathsPage struct {
+	Links    PageLinks `json:"_links"`
+	Embedded struct {
+		Records []Path `json:"records"`
+	} `json:"_embedded"`
+}
+
 type Payment struct {
 	ID          string `json:"id"`
 	Type        string `json:"type"`
@@ -207,6 +402,18 @@ type Payment struct {
 	}
 }
 
+type Path struct {
+	SourceAssetType        string  `json:"source_asset_type"`
+	SourceAssetCode        string  `json:"source_asset_code,omitempty"`
+	SourceAssetIssuer      string  `json:"source_asset_issuer,omitempty"`
+	SourceAmount           string  `json:"source_amount"`
+	DestinationAssetType   string  `json:"destination_asset_type"`
+	DestinationAssetCode   string  `json:"destination_asset_code,omitempty"`
+	DestinationAssetIssuer string  `json:"destination_asset_issuer,omitempty"`
+	DestinationAmount      string  `json:"destination_amount"`
+	Path                   []Asset `json:"path"`
+}
+
 type Price struct {
 	N int32 `json:"n"`
 	D int32 `json:"d"`
@@ -218,6 +425,53 @@ type PriceLevel struct {
 	Amount string `json:"amount"`
 }
 
+type Root struct {
+	Links struct {
+		Account             Link `json:"account"`
+		AccountTransactions Link `json:"account_transactions"`
+		Friendbot           Link `json:"friendbot"`
+		Metrics             Link `json:"metrics"`
+		OrderBook           Link `json:"order_book"`
+		Self                Link `json:"self"`
+		Transaction         Link `json:"transaction"`
+		Transactions        Link `json:"transactions"`
+	} `json:"_links"`
+
+	HorizonVersion       string `json:"horizon_version"`
+	StellarCoreVersion   string `json:"core_version"`
+	HorizonSequence      int32  `json:"history_latest_ledger"`
+	HistoryElderSequence int32  `json:"history_elder_ledger"`
+	CoreSequence         int32  `json:"core_latest_ledger"`
+	CoreElderSequence    int32  `json:"core_elder_ledger"`
+	NetworkPassphrase    string `json:"network_passphrase"`
+	ProtocolVersion      int32  `json:"protocol_version"`
+}
+
+type Trade struct {
+	Links struct {
+		Self      Link `json:"self"`
+		Base      Link `json:"base"`
+		Counter   Link `json:"counter"`
+		Operation Link `json:"operation"`
+	} `json:"_links"`
+
+	ID                 string    `json:"id"`
+	PT                 string    `json:"paging_token"`
+	LedgerCloseTime    time.Time `json:"ledger_close_time"`
+	OfferID            string    `json:"offer_id"`
+	BaseAccount        string    `json:"base_account"`
+	BaseAmount         string    `json:"base_amount"`
+	BaseAssetType      string    `json:"base_asset_type"`
+	BaseAssetCode      string    `json:"base_asset_code,omitempty"`
+	BaseAssetIssuer    string    `json:"base_asset_issuer,omitempty"`
+	CounterAccount     string    `json:"counter_account"`
+	CounterAmount      string    `json:"counter_amount"`
+	CounterAssetType   string    `json:"counter_asset_type"`
+	CounterAssetCode   string    `json:"counter_asset_code,omitempty"`
+	CounterAssetIssuer string    `json:"counter_asset_issuer,omitempty"`
+	BaseIsSeller       bool      `json:"base_is_seller"`
+}
+
 type Transaction struct {
 	ID              string    `json:"id"`
 	PagingToken     string    `json:"paging_token"`
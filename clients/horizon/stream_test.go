@@ -0,0 +1,116 @@
+package horizon
+
+import (
+	"fmt"
+	"net/http"
+	stdtest "net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestStream_Resumes(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		requests []*http.Request
+	)
+
+	server := stdtest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests = append(requests, r)
+		n := len(requests)
+		lock.Unlock()
+
+		switch n {
+		case 1, 3:
+			// send a single ledger, then close the connection like a horizon
+			// restart would
+			sequence := n
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "retry: 1\nevent: open\ndata: \"hello\"\n\n")
+			fmt.Fprintf(w, "id: %d\ndata: {\"sequence\": %d, \"paging_token\": \"%d\"}\n\n", sequence, sequence, sequence)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"type": "service_unavailable", "status": 503}`)
+		default:
+			w.Header().Set("Content-Type", "text/event-stream")
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{URL: server.URL, HTTP: http.DefaultClient}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var sequences []int32
+	cursor := Cursor("now")
+	err := client.StreamLedgers(ctx, &cursor, func(l Ledger) {
+		sequences = append(sequences, l.Sequence)
+		if len(sequences) == 2 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 3}, sequences)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, requests, 3)
+	assert.Equal(t, "now", requests[0].URL.Query().Get("cursor"))
+	assert.Equal(t, "", requests[0].Header.Get("Last-Event-ID"))
+	assert.Equal(t, "text/event-stream", requests[0].Header.Get("Accept"))
+	for _, r := range requests[1:] {
+		assert.Equal(t, "1", r.URL.Query().Get("cursor"))
+		assert.Equal(t, "1", r.Header.Get("Last-Event-ID"))
+	}
+}
+
+func TestStream_ClientError(t *testing.T) {
+	server := stdtest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"type": "bad_request", "title": "Bad Request", "status": 400}`)
+	}))
+	defer server.Close()
+
+	client := &Client{URL: server.URL, HTTP: http.DefaultClient}
+	err := client.StreamEffects(context.Background(), nil, func(Effect) {}, ForAccount("GABC"))
+	require.Error(t, err)
+	herr, ok := err.(*Error)
+	require.True(t, ok)
+	assert.Equal(t, "bad_request", herr.Problem.Type)
+
+	err = client.StreamEffects(context.Background(), nil, func(Effect) {}, AssetPair{})
+	assert.Error(t, err)
+}
+
+func TestStreamBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, streamBackoff(time.Second, 0))
+
+	for i := 0; i < 20; i++ {
+		delay := streamBackoff(time.Second, 3)
+		assert.True(t, delay >= 2*time.Second && delay <= 4*time.Second, delay.String())
+
+		delay = streamBackoff(time.Second, 100)
+		assert.True(t, delay >= maxStreamBackoff/2 && delay <= maxStreamBackoff, delay.String())
+
+		// a zero retry must not make the client reconnect in a tight loop
+		delay = streamBackoff(0, 3)
+		assert.True(t, delay >= 2*minStreamRetry && delay <= 4*minStreamRetry, delay.String())
+	}
+	assert.Equal(t, minStreamRetry, streamBackoff(0, 0))
+}
+
+func TestParseEventFields(t *testing.T) {
+	id, retry, ok := parseEventFields([]byte("retry: 1000\r\nevent: open\r\ndata: \"hello\"\r\n\r\n"))
+	assert.Equal(t, "", id)
+	assert.Equal(t, time.Second, retry)
+	assert.True(t, ok)
+
+	id, _, ok = parseEventFields([]byte("id: 12884905984\ndata: {}\n\n"))
+	assert.Equal(t, "12884905984", id)
+	assert.False(t, ok)
+}
@@ -0,0 +1,352 @@
+package horizon
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+	"golang.org/x/net/context"
+)
+
+const (
+	// DefaultSubmitAttempts is the number of submissions made by a Submitter
+	// whose MaxAttempts is zero.
+	DefaultSubmitAttempts = 5
+
+	// DefaultPollInterval is the delay between checks for a transaction
+	// whose submission timed out used by a Submitter whose PollInterval is
+	// zero.
+	DefaultPollInterval = 5 * time.Second
+
+	// DefaultPollTimeout is how long a Submitter whose PollTimeout is zero
+	// checks for a transaction whose submission timed out before submitting
+	// it again.
+	DefaultPollTimeout = time.Minute
+)
+
+// SubmitErrorKind classifies the errors returned by SubmitTransaction by how
+// a submission may recover from them.
+type SubmitErrorKind int
+
+const (
+	// SubmitErrorFatal describes an error the submission cannot recover
+	// from, such as a failed transaction or a malformed request.
+	SubmitErrorFatal SubmitErrorKind = iota
+
+	// SubmitErrorTimeout describes an error after which the outcome of the
+	// submission is unknown: horizon timed out waiting for the transaction
+	// to be included in a ledger, or the request failed before a response
+	// was received.  The transaction may still be applied.
+	SubmitErrorTimeout
+
+	// SubmitErrorBadSequence describes a transaction whose sequence number
+	// is not the next one of its source account.
+	SubmitErrorBadSequence
+
+	// SubmitErrorUnavailable describes a submission horizon rejected without
+	// considering the transaction, because it is overloaded or rate limited.
+	SubmitErrorUnavailable
+)
+
+// ClassifySubmitError returns the kind of an error returned by
+// SubmitTransaction.
+func ClassifySubmitError(err error) SubmitErrorKind {
+	herr, ok := errors.Cause(err).(*Error)
+	if !ok {
+		return SubmitErrorTimeout
+	}
+
+	switch herr.Problem.Status {
+	case http.StatusGatewayTimeout:
+		return SubmitErrorTimeout
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return SubmitErrorUnavailable
+	}
+
+	codes, err := herr.ResultCodes()
+	if err == nil && codes.TransactionCode == "tx_bad_seq" {
+		return SubmitErrorBadSequence
+	}
+
+	return SubmitErrorFatal
+}
+
+func (kind SubmitErrorKind) String() string {
+	switch kind {
+	case SubmitErrorFatal:
+		return "fatal"
+	case SubmitErrorTimeout:
+		return "timeout"
+	case SubmitErrorBadSequence:
+		return "bad sequence"
+	case SubmitErrorUnavailable:
+		return "unavailable"
+	default:
+		return "unknown"
+	}
+}
+
+// Submitter submits transactions to horizon, recovering from the failures
+// that do not need the attention of the caller:
+//
+// - When the outcome of a submission is unknown, such as when horizon times
+// out, the transaction is looked up by hash until it is found or PollTimeout
+// elapses, after which it is submitted again.
+//
+// - When the sequence number of the transaction is wrong, the sequence number
+// of its source account is loaded again and the transaction is rebuilt using
+// the next one and signed again using Sign.  Transactions whose outcome was
+// unknown are looked up first, so that a transaction applied after all is
+// never submitted twice.
+//
+// - When horizon is unavailable, the transaction is submitted again after
+// PollInterval.
+type Submitter struct {
+	Client ClientInterface
+
+	// Sign signs tx and returns its base64 encoded envelope, for example
+	// using `tx.Sign(seed)` and `Base64()`.  It is called before the first
+	// submission and each time the sequence number of tx changes.
+	Sign func(tx *build.TransactionBuilder) (string, error)
+
+	// MaxAttempts is the number of submissions made before giving up.
+	MaxAttempts int
+
+	// PollInterval is the delay between lookups of a transaction whose
+	// outcome is unknown, and before submitting a transaction again.
+	PollInterval time.Duration
+
+	// PollTimeout is how long a transaction whose outcome is unknown is
+	// looked up before it is submitted again.
+	PollTimeout time.Duration
+}
+
+// SubmissionReport describes the submission of a transaction by a Submitter.
+type SubmissionReport struct {
+	// Result is the transaction applied, if any.
+	Result TransactionSuccess
+
+	// Attempts records each submission made, in order.
+	Attempts []SubmissionAttempt
+}
+
+// SubmissionAttempt describes a single submission of a transaction.
+type SubmissionAttempt struct {
+	Hash     string
+	Sequence xdr.SequenceNumber
+	Start    time.Time
+	Duration time.Duration
+
+	// Err is the error returned by horizon, or nil when the submission
+	// succeeded.
+	Err  error
+	Kind SubmitErrorKind
+
+	// Polls is the number of times the transaction was looked up after its
+	// outcome was unknown, and Found reports whether it was.
+	Polls int
+	Found bool
+}
+
+// Submit submits tx, whose NetworkPassphrase must be set, until it is applied
+// or an error that cannot be recovered from occurs.  The returned report is
+// never nil, and records every attempt made even when an error is returned.
+func (s *Submitter) Submit(ctx context.Context, tx *build.TransactionBuilder) (*SubmissionReport, error) {
+	report := &SubmissionReport{}
+	if tx.Err != nil {
+		return report, errors.Wrap(tx.Err, "build transaction failed")
+	}
+
+	maxAttempts := s.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultSubmitAttempts
+	}
+
+	// unknown holds the hashes of the transactions whose outcome is unknown
+	var unknown []string
+
+	envelope, hash, err := s.sign(tx)
+	if err != nil {
+		return report, err
+	}
+
+	for len(report.Attempts) < maxAttempts {
+		attempt := SubmissionAttempt{
+			Hash:     hash,
+			Sequence: tx.TX.SeqNum,
+			Start:    time.Now(),
+		}
+
+		result, err := s.Client.SubmitTransaction(envelope)
+		if err == nil {
+			report.record(attempt, &result)
+			return report, nil
+		}
+
+		attempt.Err = err
+		attempt.Kind = ClassifySubmitError(err)
+
+		switch attempt.Kind {
+		case SubmitErrorTimeout:
+			unknown = appendHash(unknown, hash)
+			found, perr := s.poll(ctx, &attempt)
+			report.record(attempt, found)
+			if perr != nil || found != nil {
+				return report, perr
+			}
+		case SubmitErrorBadSequence:
+			// a transaction submitted earlier may have been applied after
+			// all, in which case its sequence number was consumed by it
+			found, lerr := s.lookup(unknown)
+			if lerr != nil {
+				report.record(attempt, nil)
+				return report, lerr
+			}
+			if found != nil {
+				attempt.Found = true
+				report.record(attempt, found)
+				return report, nil
+			}
+
+			report.record(attempt, nil)
+
+			envelope, hash, err = s.resequence(tx)
+			if err != nil {
+				return report, err
+			}
+		case SubmitErrorUnavailable:
+			report.record(attempt, nil)
+			if !sleep(ctx, s.pollInterval()) {
+				return report, ctx.Err()
+			}
+		default:
+			report.record(attempt, nil)
+			return report, err
+		}
+	}
+
+	return report, errors.Errorf("transaction not applied after %d attempts", len(report.Attempts))
+}
+
+// record appends `attempt` to the report, along with the transaction applied
+// if any.
+func (report *SubmissionReport) record(attempt SubmissionAttempt, result *TransactionSuccess) {
+	attempt.Duration = time.Since(attempt.Start)
+	report.Attempts = append(report.Attempts, attempt)
+	if result != nil {
+		report.Result = *result
+	}
+}
+
+// sign signs tx, returning its envelope and hash.
+func (s *Submitter) sign(tx *build.TransactionBuilder) (envelope string, hash string, err error) {
+	hash, err = tx.HashHex()
+	if err != nil {
+		return "", "", errors.Wrap(err, "hash transaction failed")
+	}
+
+	envelope, err = s.Sign(tx)
+	if err != nil {
+		return "", "", errors.Wrap(err, "sign transaction failed")
+	}
+
+	return envelope, hash, nil
+}
+
+// resequence updates the sequence number of tx to the next one of its source
+// account and signs it again.
+func (s *Submitter) resequence(tx *build.TransactionBuilder) (string, string, error) {
+	seq, err := s.Client.SequenceForAccount(tx.TX.SourceAccount.Address())
+	if err != nil {
+		return "", "", errors.Wrap(err, "load sequence failed")
+	}
+
+	tx.TX.SeqNum = seq + 1
+	return s.sign(tx)
+}
+
+// poll looks up the transaction of `attempt` until it is found, PollTimeout
+// elapses or ctx is done.  It returns the transaction if it was found.
+func (s *Submitter) poll(ctx context.Context, attempt *SubmissionAttempt) (*TransactionSuccess, error) {
+	timeout := s.PollTimeout
+	if timeout == 0 {
+		timeout = DefaultPollTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		if !sleep(ctx, s.pollInterval()) {
+			return nil, ctx.Err()
+		}
+
+		attempt.Polls++
+		found, err := s.lookup([]string{attempt.Hash})
+		if err != nil {
+			// the transaction can be looked up again, or submitted again
+			// when polling times out
+			continue
+		}
+		if found != nil {
+			attempt.Found = true
+			return found, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// lookup returns the first of the transactions identified by `hashes` that
+// was applied, or nil when none of them was.
+func (s *Submitter) lookup(hashes []string) (*TransactionSuccess, error) {
+	for _, hash := range hashes {
+		tx, err := s.Client.LoadTransaction(hash)
+		if herr, ok := errors.Cause(err).(*Error); ok && herr.Problem.Status == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "load transaction failed")
+		}
+
+		return &TransactionSuccess{
+			Hash:   tx.Hash,
+			Ledger: tx.Ledger,
+			Env:    tx.EnvelopeXdr,
+			Result: tx.ResultXdr,
+			Meta:   tx.ResultMetaXdr,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func (s *Submitter) pollInterval() time.Duration {
+	if s.PollInterval == 0 {
+		return DefaultPollInterval
+	}
+	return s.PollInterval
+}
+
+// appendHash appends `hash` to `hashes` unless it is already present.
+func appendHash(hashes []string, hash string) []string {
+	for _, h := range hashes {
+		if h == hash {
+			return hashes
+		}
+	}
+	return append(hashes, hash)
+}
+
+// sleep waits for `d`, returning false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,191 @@
+package horizon
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+const submitterSeed = "SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"
+
+func newSubmitter(client *MockClient) (*Submitter, *[]xdr.SequenceNumber) {
+	var signed []xdr.SequenceNumber
+	return &Submitter{
+		Client: client,
+		Sign: func(tx *build.TransactionBuilder) (string, error) {
+			signed = append(signed, tx.TX.SeqNum)
+			txe := tx.Sign(submitterSeed)
+			return txe.Base64()
+		},
+		PollInterval: time.Millisecond,
+		PollTimeout:  20 * time.Millisecond,
+	}, &signed
+}
+
+func submitterTransaction(seq uint64) *build.TransactionBuilder {
+	return build.Transaction(
+		build.SourceAccount{AddressOrSeed: submitterSeed},
+		build.Sequence{Sequence: seq},
+		build.TestNetwork,
+		build.Payment(
+			build.Destination{AddressOrSeed: "GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA"},
+			build.NativeAmount{Amount: "50"},
+		),
+	)
+}
+
+func problemError(status int, codes string) *Error {
+	herr := &Error{Problem: Problem{Status: status}}
+	if codes != "" {
+		herr.Problem.Extras = map[string]json.RawMessage{
+			"result_codes": json.RawMessage(codes),
+		}
+	}
+	return herr
+}
+
+var (
+	errTimeout     = problemError(http.StatusGatewayTimeout, "")
+	errNotFound    = problemError(http.StatusNotFound, "")
+	errBadSequence = problemError(http.StatusBadRequest, `{"transaction": "tx_bad_seq"}`)
+)
+
+func TestClassifySubmitError(t *testing.T) {
+	cases := []struct {
+		err  error
+		kind SubmitErrorKind
+	}{
+		{errors.New("connection reset"), SubmitErrorTimeout},
+		{errTimeout, SubmitErrorTimeout},
+		{errBadSequence, SubmitErrorBadSequence},
+		{problemError(http.StatusServiceUnavailable, ""), SubmitErrorUnavailable},
+		{problemError(http.StatusTooManyRequests, ""), SubmitErrorUnavailable},
+		{problemError(http.StatusBadRequest, `{"transaction": "tx_failed", "operations": ["op_underfunded"]}`), SubmitErrorFatal},
+		{problemError(http.StatusBadRequest, ""), SubmitErrorFatal},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.kind, ClassifySubmitError(c.err), c.err.Error())
+	}
+}
+
+func TestSubmitter_Timeout(t *testing.T) {
+	var client MockClient
+	s, _ := newSubmitter(&client)
+	tx := submitterTransaction(5)
+	hash, err := tx.HashHex()
+	require.NoError(t, err)
+
+	client.On("SubmitTransaction", mock.Anything).Return(TransactionSuccess{}, errTimeout).Once()
+	client.On("LoadTransaction", hash).Return(Transaction{}, errNotFound).Once()
+	client.On("LoadTransaction", hash).Return(Transaction{Hash: hash, Ledger: 7}, nil).Once()
+
+	report, err := s.Submit(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, hash, report.Result.Hash)
+	assert.Equal(t, int32(7), report.Result.Ledger)
+
+	require.Len(t, report.Attempts, 1)
+	assert.Equal(t, SubmitErrorTimeout, report.Attempts[0].Kind)
+	assert.Equal(t, 2, report.Attempts[0].Polls)
+	assert.True(t, report.Attempts[0].Found)
+	client.AssertExpectations(t)
+}
+
+func TestSubmitter_BadSequence(t *testing.T) {
+	var client MockClient
+	s, signed := newSubmitter(&client)
+	tx := submitterTransaction(5)
+
+	client.On("SubmitTransaction", mock.Anything).Return(TransactionSuccess{}, errBadSequence).Once()
+	client.On("SequenceForAccount", keypair.MustParse(submitterSeed).Address()).
+		Return(xdr.SequenceNumber(8), nil)
+	client.On("SubmitTransaction", mock.Anything).Return(TransactionSuccess{Hash: "applied"}, nil).Once()
+
+	report, err := s.Submit(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, "applied", report.Result.Hash)
+	assert.Equal(t, []xdr.SequenceNumber{5, 9}, *signed)
+
+	require.Len(t, report.Attempts, 2)
+	assert.Equal(t, SubmitErrorBadSequence, report.Attempts[0].Kind)
+	assert.Equal(t, xdr.SequenceNumber(5), report.Attempts[0].Sequence)
+	assert.Nil(t, report.Attempts[1].Err)
+	assert.Equal(t, xdr.SequenceNumber(9), report.Attempts[1].Sequence)
+	assert.NotEqual(t, report.Attempts[0].Hash, report.Attempts[1].Hash)
+	client.AssertExpectations(t)
+}
+
+// appliedLateClient is a MockClient whose transactions are only found once
+// `applied` is set.
+type appliedLateClient struct {
+	*MockClient
+	applied bool
+}
+
+func (c *appliedLateClient) LoadTransaction(hash string) (Transaction, error) {
+	if !c.applied {
+		return Transaction{}, errNotFound
+	}
+	return Transaction{Hash: hash}, nil
+}
+
+func TestSubmitter_TimeoutThenBadSequence(t *testing.T) {
+	var client MockClient
+	late := &appliedLateClient{MockClient: &client}
+	s, signed := newSubmitter(&client)
+	s.Client = late
+	tx := submitterTransaction(5)
+	hash, err := tx.HashHex()
+	require.NoError(t, err)
+
+	// the transaction is applied only after polling gives up, so submitting
+	// it again fails with a bad sequence number
+	client.On("SubmitTransaction", mock.Anything).Return(TransactionSuccess{}, errTimeout).Once()
+	client.On("SubmitTransaction", mock.Anything).Return(TransactionSuccess{}, errBadSequence).Once().
+		Run(func(mock.Arguments) { late.applied = true })
+
+	report, err := s.Submit(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, hash, report.Result.Hash)
+	assert.Equal(t, []xdr.SequenceNumber{5}, *signed)
+
+	require.Len(t, report.Attempts, 2)
+	assert.Equal(t, SubmitErrorTimeout, report.Attempts[0].Kind)
+	assert.False(t, report.Attempts[0].Found)
+	assert.NotZero(t, report.Attempts[0].Polls)
+	assert.Equal(t, SubmitErrorBadSequence, report.Attempts[1].Kind)
+	assert.True(t, report.Attempts[1].Found)
+	client.AssertExpectations(t)
+}
+
+func TestSubmitter_Errors(t *testing.T) {
+	var client MockClient
+	s, _ := newSubmitter(&client)
+	failed := problemError(http.StatusBadRequest, `{"transaction": "tx_failed"}`)
+
+	client.On("SubmitTransaction", mock.Anything).Return(TransactionSuccess{}, failed).Once()
+	report, err := s.Submit(context.Background(), submitterTransaction(5))
+	assert.Equal(t, failed, err)
+	require.Len(t, report.Attempts, 1)
+	assert.Equal(t, SubmitErrorFatal, report.Attempts[0].Kind)
+
+	// submissions stop after MaxAttempts
+	unavailable := problemError(http.StatusServiceUnavailable, "")
+	client.On("SubmitTransaction", mock.Anything).Return(TransactionSuccess{}, unavailable).Times(2)
+	s.MaxAttempts = 2
+	report, err = s.Submit(context.Background(), submitterTransaction(5))
+	assert.EqualError(t, err, "transaction not applied after 2 attempts")
+	assert.Len(t, report.Attempts, 2)
+	client.AssertExpectations(t)
+}
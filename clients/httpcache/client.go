@@ -0,0 +1,170 @@
+package httpcache
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Get returns the cached response for `url` if it has not expired, or loads
+// it using HTTP otherwise.  The body of the returned response must be closed
+// as usual.
+func (c *Client) Get(url string) (*http.Response, error) {
+	c.lock.Lock()
+	if e, ok := c.lookup(url); ok {
+		c.lock.Unlock()
+		return e.response()
+	}
+
+	// wait for the response to a concurrent request of the same url
+	if cl, ok := c.inflight[url]; ok {
+		c.lock.Unlock()
+		cl.wg.Wait()
+		if cl.entry == nil {
+			return c.HTTP.Get(url)
+		}
+		return cl.entry.response()
+	}
+
+	cl := &call{}
+	cl.wg.Add(1)
+	if c.inflight == nil {
+		c.inflight = map[string]*call{}
+	}
+	c.inflight[url] = cl
+	c.lock.Unlock()
+
+	e, resp := c.fetch(url)
+
+	c.lock.Lock()
+	if e != nil && e.expires.After(c.clock()) {
+		c.store(e)
+	}
+	delete(c.inflight, url)
+	cl.entry = e
+	c.lock.Unlock()
+	cl.wg.Done()
+
+	if e == nil {
+		return resp, nil
+	}
+	return e.response()
+}
+
+// fetch loads `url` using HTTP, returning the entry caching its result.  When
+// the response body is too large to be cached, the response itself is
+// returned instead.
+func (c *Client) fetch(url string) (*entry, *http.Response) {
+	resp, err := c.HTTP.Get(url)
+	if err != nil {
+		return &entry{url: url, err: err, expires: c.clock().Add(c.negativeTTL())}, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, MaxBodySize+1))
+	if err != nil {
+		resp.Body.Close()
+		return &entry{url: url, err: err, expires: c.clock().Add(c.negativeTTL())}, nil
+	}
+
+	if len(body) > MaxBodySize {
+		resp.Body = readCloser{
+			Reader: io.MultiReader(bytes.NewReader(body), resp.Body),
+			Closer: resp.Body,
+		}
+		return nil, resp
+	}
+	resp.Body.Close()
+
+	return &entry{
+		url:     url,
+		resp:    resp,
+		body:    body,
+		expires: c.clock().Add(c.ttl(resp)),
+	}, nil
+}
+
+// lookup returns the unexpired entry cached for `url`, removing it if it
+// expired.  The caller must hold the lock of the client.
+func (c *Client) lookup(url string) (*entry, bool) {
+	elem, ok := c.entries[url]
+	if !ok {
+		return nil, false
+	}
+
+	e := elem.Value.(*entry)
+	if !e.expires.After(c.clock()) {
+		c.lru.Remove(elem)
+		delete(c.entries, url)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+	return e, true
+}
+
+// store caches `e`, evicting the least recently used entries when the cache is
+// full.  The caller must hold the lock of the client.
+func (c *Client) store(e *entry) {
+	if c.entries == nil {
+		c.entries = map[string]*list.Element{}
+		c.lru = list.New()
+	}
+
+	if elem, ok := c.entries[e.url]; ok {
+		c.lru.Remove(elem)
+	}
+	c.entries[e.url] = c.lru.PushFront(e)
+
+	for len(c.entries) > c.maxEntries() {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).url)
+	}
+}
+
+// ttl returns the time `resp` should be cached for.
+func (c *Client) ttl(resp *http.Response) time.Duration {
+	cc := parseCacheControl(resp.Header.Get("Cache-Control"))
+	if cc.noStore {
+		return 0
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return c.negativeTTL()
+	}
+
+	if cc.maxAge >= 0 {
+		return cc.maxAge
+	}
+	if c.TTL == 0 {
+		return DefaultTTL
+	}
+	return c.TTL
+}
+
+func (c *Client) negativeTTL() time.Duration {
+	if c.NegativeTTL == 0 {
+		return DefaultNegativeTTL
+	}
+	return c.NegativeTTL
+}
+
+func (c *Client) maxEntries() int {
+	if c.MaxEntries <= 0 {
+		return DefaultMaxEntries
+	}
+	return c.MaxEntries
+}
+
+func (c *Client) clock() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// ensure that Client implements HTTP
+var _ HTTP = &Client{}
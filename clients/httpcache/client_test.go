@@ -0,0 +1,218 @@
+package httpcache
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testHTTP responds to every request using `respond`, counting the requests
+// of each url.
+type testHTTP struct {
+	respond func(url string) (*http.Response, error)
+
+	lock     sync.Mutex
+	requests map[string]int
+}
+
+func (h *testHTTP) Get(url string) (*http.Response, error) {
+	h.lock.Lock()
+	if h.requests == nil {
+		h.requests = map[string]int{}
+	}
+	h.requests[url]++
+	h.lock.Unlock()
+
+	return h.respond(url)
+}
+
+func (h *testHTTP) count(url string) int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.requests[url]
+}
+
+func respondWith(status int, cacheControl string) func(string) (*http.Response, error) {
+	return func(url string) (*http.Response, error) {
+		resp := &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("body of " + url)),
+		}
+		if cacheControl != "" {
+			resp.Header.Set("Cache-Control", cacheControl)
+		}
+		return resp, nil
+	}
+}
+
+// testClock is a clock that only moves forward when told to.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time          { return c.now }
+func (c *testClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+func newTestClient(h HTTP) (*Client, *testClock) {
+	clock := &testClock{now: time.Unix(1500000000, 0)}
+	return &Client{HTTP: h, now: clock.Now}, clock
+}
+
+func get(t *testing.T, c *Client, url string) string {
+	resp, err := c.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return fmt.Sprintf("%d %s", resp.StatusCode, body)
+}
+
+func TestClient_TTL(t *testing.T) {
+	h := &testHTTP{respond: respondWith(http.StatusOK, "")}
+	c, clock := newTestClient(h)
+
+	assert.Equal(t, "200 body of a", get(t, c, "a"))
+	assert.Equal(t, "200 body of a", get(t, c, "a"))
+	assert.Equal(t, 1, h.count("a"))
+
+	clock.Advance(DefaultTTL - time.Second)
+	get(t, c, "a")
+	assert.Equal(t, 1, h.count("a"))
+	clock.Advance(time.Second)
+	get(t, c, "a")
+	assert.Equal(t, 2, h.count("a"))
+
+	// max-age overrides the TTL of the client
+	h.respond = respondWith(http.StatusOK, "public, max-age=30")
+	c.TTL = time.Hour
+	get(t, c, "b")
+	clock.Advance(29 * time.Second)
+	get(t, c, "b")
+	assert.Equal(t, 1, h.count("b"))
+	clock.Advance(time.Second)
+	get(t, c, "b")
+	assert.Equal(t, 2, h.count("b"))
+
+	for _, cc := range []string{"no-store", "no-cache", "max-age=0"} {
+		h.respond = respondWith(http.StatusOK, cc)
+		get(t, c, cc)
+		get(t, c, cc)
+		assert.Equal(t, 2, h.count(cc), cc)
+	}
+
+	// successful responses are not cached when the TTL is negative
+	h.respond = respondWith(http.StatusOK, "")
+	c.TTL = -1
+	get(t, c, "c")
+	get(t, c, "c")
+	assert.Equal(t, 2, h.count("c"))
+}
+
+func TestClient_NegativeTTL(t *testing.T) {
+	h := &testHTTP{respond: respondWith(http.StatusNotFound, "max-age=3600")}
+	c, clock := newTestClient(h)
+
+	assert.Equal(t, "404 body of a", get(t, c, "a"))
+	assert.Equal(t, "404 body of a", get(t, c, "a"))
+	assert.Equal(t, 1, h.count("a"))
+	clock.Advance(DefaultNegativeTTL)
+	get(t, c, "a")
+	assert.Equal(t, 2, h.count("a"))
+
+	h.respond = func(string) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}
+	for i := 0; i < 2; i++ {
+		_, err := c.Get("b")
+		assert.EqualError(t, err, "connection refused")
+	}
+	assert.Equal(t, 1, h.count("b"))
+
+	// negative caching is disabled when the negative TTL is negative
+	c.NegativeTTL = -1
+	for i := 0; i < 2; i++ {
+		_, err := c.Get("c")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2, h.count("c"))
+}
+
+func TestClient_MaxEntries(t *testing.T) {
+	h := &testHTTP{respond: respondWith(http.StatusOK, "")}
+	c, _ := newTestClient(h)
+	c.MaxEntries = 2
+
+	get(t, c, "a")
+	get(t, c, "b")
+	get(t, c, "a")
+	get(t, c, "c") // evicts b, the least recently used
+	assert.Equal(t, 2, c.Len())
+
+	get(t, c, "a")
+	get(t, c, "c")
+	assert.Equal(t, 1, h.count("a"))
+	assert.Equal(t, 1, h.count("c"))
+	get(t, c, "b")
+	assert.Equal(t, 2, h.count("b"))
+
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+	get(t, c, "a")
+	assert.Equal(t, 2, h.count("a"))
+}
+
+func TestClient_LargeBody(t *testing.T) {
+	large := strings.Repeat("0", MaxBodySize+1)
+	h := &testHTTP{respond: func(string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(large)),
+		}, nil
+	}}
+	c, _ := newTestClient(h)
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, "200 "+large, get(t, c, "a"))
+	}
+	assert.Equal(t, 2, h.count("a"))
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestClient_Concurrent(t *testing.T) {
+	release := make(chan struct{})
+	h := &testHTTP{respond: func(url string) (*http.Response, error) {
+		<-release
+		return respondWith(http.StatusOK, "")(url)
+	}}
+	c := &Client{HTTP: h}
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 10)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			bodies[i] = get(t, c, "a")
+		}(i)
+	}
+
+	// give every goroutine a chance to wait for the first request
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, h.count("a"))
+	for _, body := range bodies {
+		assert.Equal(t, "200 body of a", body)
+	}
+}
@@ -0,0 +1,81 @@
+package httpcache
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// entry is the cached result of a request: either a response, whose body was
+// read, or an error.
+type entry struct {
+	url     string
+	resp    *http.Response
+	body    []byte
+	err     error
+	expires time.Time
+}
+
+// call is a request in flight, whose result is shared with the concurrent
+// requests of the same url.  entry is nil when the result cannot be shared.
+type call struct {
+	wg    sync.WaitGroup
+	entry *entry
+}
+
+// cacheControl holds the directives of a `Cache-Control` header relevant to
+// a client cache.  maxAge is negative when not specified.
+type cacheControl struct {
+	noStore bool
+	maxAge  time.Duration
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// response returns a copy of the cached response, or the cached error.
+func (e *entry) response() (*http.Response, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	header := make(http.Header, len(e.resp.Header))
+	for key, values := range e.resp.Header {
+		header[key] = append([]string(nil), values...)
+	}
+
+	resp := *e.resp
+	resp.Header = header
+	resp.Body = ioutil.NopCloser(bytes.NewReader(e.body))
+	resp.ContentLength = int64(len(e.body))
+	return &resp, nil
+}
+
+// parseCacheControl parses the value of a `Cache-Control` header.  Responses
+// that must be revalidated before being used (`no-cache`) are not stored, as
+// a client cache does not revalidate them.
+func parseCacheControl(value string) cacheControl {
+	cc := cacheControl{maxAge: -1}
+
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store", directive == "no-cache":
+			cc.noStore = true
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.ParseInt(strings.Trim(directive[len("max-age="):], `"`), 10, 64)
+			if err == nil && seconds >= 0 {
+				cc.maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	return cc
+}
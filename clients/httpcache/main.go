@@ -0,0 +1,96 @@
+// Package httpcache provides an http client that caches the responses of GET
+// requests in memory, honoring their `Cache-Control` header.
+//
+// It implements the HTTP interfaces of the stellartoml and federation
+// packages, so that a single cache can be shared by both to avoid loading the
+// same stellar.toml files and federation records over and over again:
+//
+//	cache := &httpcache.Client{HTTP: http.DefaultClient}
+//	client := &federation.Client{
+//		HTTP:        cache,
+//		Horizon:     horizon.DefaultPublicNetClient,
+//		StellarTOML: &stellartoml.Client{HTTP: cache},
+//	}
+//
+// Failed requests, and responses whose status is not 2xx, are cached too, but
+// for a shorter time, so that a domain that is down or does not know an
+// address is not requested again for every lookup.
+package httpcache
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTTL is the time a successful response is cached for when it does
+	// not specify a `max-age` and the TTL of the client is zero.
+	DefaultTTL = 10 * time.Minute
+
+	// DefaultNegativeTTL is the time a failed request, or a response whose
+	// status is not 2xx, is cached for when the NegativeTTL of the client is
+	// zero.
+	DefaultNegativeTTL = time.Minute
+
+	// DefaultMaxEntries is the number of responses cached when the
+	// MaxEntries of the client is zero.
+	DefaultMaxEntries = 10000
+
+	// MaxBodySize is the size of the largest response body cached.  Larger
+	// responses are returned as is, without being cached.
+	MaxBodySize = 1024 * 1024
+)
+
+// HTTP represents the http client used by a Client to make the requests whose
+// responses are not cached.
+type HTTP interface {
+	Get(url string) (*http.Response, error)
+}
+
+// Client is an http client caching the responses it loads using HTTP.  The
+// least recently used response is evicted once MaxEntries responses are
+// cached.  It is safe for concurrent use, and concurrent requests of the same
+// url are only sent once.
+type Client struct {
+	HTTP HTTP
+
+	// TTL is the time a successful response is cached for, unless its
+	// `Cache-Control` header specifies a `max-age`.  DefaultTTL is used when
+	// zero, and such responses are not cached when negative.
+	TTL time.Duration
+
+	// NegativeTTL is the time a failed request, or a response whose status is
+	// not 2xx, is cached for.  DefaultNegativeTTL is used when zero, and such
+	// responses are not cached when negative.
+	NegativeTTL time.Duration
+
+	// MaxEntries is the maximum number of responses cached.
+	// DefaultMaxEntries is used when zero.
+	MaxEntries int
+
+	lock     sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List
+	inflight map[string]*call
+
+	// now returns the current time, and is replaced by tests
+	now func() time.Time
+}
+
+// Len returns the number of responses currently cached, including those that
+// expired but were not evicted yet.
+func (c *Client) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.entries)
+}
+
+// Purge removes every cached response.
+func (c *Client) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = nil
+	c.lru = nil
+}
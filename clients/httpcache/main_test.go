@@ -0,0 +1,32 @@
+package httpcache_test
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/stellar/go/clients/federation"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/clients/httpcache"
+	"github.com/stellar/go/clients/stellartoml"
+)
+
+// ExampleClient shares a cache between a federation client and the client it
+// uses to load stellar.toml files.
+func ExampleClient() {
+	cache := &httpcache.Client{HTTP: http.DefaultClient}
+	client := &federation.Client{
+		HTTP:        cache,
+		Horizon:     horizon.DefaultPublicNetClient,
+		StellarTOML: &stellartoml.Client{HTTP: cache},
+	}
+
+	// only the first lookup loads the stellar.toml file of stellar.org
+	for _, address := range []string{"scott*stellar.org", "bartek*stellar.org"} {
+		resp, err := client.LookupByAddress(address)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(resp.AccountID)
+	}
+}
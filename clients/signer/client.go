@@ -0,0 +1,85 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+)
+
+// Keypair returns a keypair for the provided address whose signatures are
+// produced by the signing service.
+func (c *Client) Keypair(address string) (*Keypair, error) {
+	kp, err := keypair.Parse(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse address failed")
+	}
+
+	if _, ok := kp.(*keypair.FromAddress); !ok {
+		return nil, errors.New("a public address is required, not a seed")
+	}
+
+	return &Keypair{client: c, public: kp}, nil
+}
+
+// Sign requests the signing service to sign data using the key identified by
+// address.  The returned signature has been verified to be valid.
+func (c *Client) Sign(address string, data []byte) ([]byte, error) {
+	kp, err := c.Keypair(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return kp.Sign(data)
+}
+
+func (c *Client) sign(kp keypair.KP, data []byte) ([]byte, error) {
+	body, err := json.Marshal(SignRequest{
+		Address: kp.Address(),
+		Data:    data,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "encode request failed")
+	}
+
+	req, err := http.NewRequest("POST", c.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "create request failed")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request errored")
+	}
+	defer resp.Body.Close()
+
+	limited := io.LimitReader(resp.Body, SignerResponseMaxSize)
+
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		msg, _ := ioutil.ReadAll(limited)
+		return nil, fmt.Errorf(
+			"signing service responded with %d: %s",
+			resp.StatusCode,
+			bytes.TrimSpace(msg),
+		)
+	}
+
+	var result SignResponse
+	err = json.NewDecoder(limited).Decode(&result)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode response failed")
+	}
+
+	err = kp.Verify(data, result.Signature)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	return result.Signature, nil
+}
@@ -0,0 +1,135 @@
+package signer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	seed  = "SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"
+	other = "SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY"
+)
+
+var address = keypair.MustParse(seed).Address()
+
+// newSigningService returns a stand-in signing service that signs requests
+// using the provided seeds.
+func newSigningService(t *testing.T, seeds ...string) *httptest.Server {
+	keys := map[string]keypair.KP{}
+	for _, s := range seeds {
+		kp := keypair.MustParse(s)
+		keys[kp.Address()] = kp
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+
+		var req SignRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		kp, ok := keys[req.Address]
+		if !ok {
+			http.Error(w, "unknown key", http.StatusNotFound)
+			return
+		}
+
+		sig, err := kp.Sign(req.Data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(SignResponse{Signature: sig})
+	}))
+}
+
+func TestKeypair(t *testing.T) {
+	server := newSigningService(t, seed)
+	defer server.Close()
+
+	client := &Client{URL: server.URL, HTTP: http.DefaultClient}
+
+	kp, err := client.Keypair(address)
+	require.NoError(t, err)
+	assert.Equal(t, address, kp.Address())
+
+	local := keypair.MustParse(seed)
+	assert.Equal(t, local.Hint(), kp.Hint())
+
+	sig, err := kp.Sign([]byte("hello"))
+	require.NoError(t, err)
+	expected, err := local.Sign([]byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, sig)
+	assert.NoError(t, kp.Verify([]byte("hello"), sig))
+
+	dsig, err := kp.SignDecorated([]byte("hello"))
+	require.NoError(t, err)
+	expectedD, err := local.SignDecorated([]byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, expectedD, dsig)
+
+	// seeds are refused
+	_, err = client.Keypair(seed)
+	assert.Error(t, err)
+
+	// unknown keys surface the service's error
+	unknown := keypair.MustParse(other).Address()
+	_, err = client.Sign(unknown, []byte("hello"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "404")
+	}
+}
+
+func TestKeypair_InvalidSignature(t *testing.T) {
+	// a misbehaving service that answers every request with a signature from
+	// the wrong key
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sig, _ := keypair.MustParse(other).Sign([]byte("hello"))
+		json.NewEncoder(w).Encode(SignResponse{Signature: sig})
+	}))
+	defer server.Close()
+
+	client := &Client{URL: server.URL, HTTP: http.DefaultClient}
+	_, err := client.Sign(address, []byte("hello"))
+	assert.Equal(t, ErrInvalidSignature, err)
+}
+
+func TestSignWith(t *testing.T) {
+	server := newSigningService(t, seed)
+	defer server.Close()
+
+	client := &Client{URL: server.URL, HTTP: http.DefaultClient}
+	remote, err := client.Keypair(address)
+	require.NoError(t, err)
+
+	tx := build.Transaction(
+		build.SourceAccount{address},
+		build.Sequence{1},
+		build.TestNetwork,
+		build.Payment(
+			build.Destination{"GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA"},
+			build.NativeAmount{"50"},
+		),
+	)
+
+	// a remote signer mixed with a local seed
+	txe := tx.SignWith(remote, keypair.MustParse(other))
+	require.NoError(t, txe.Err)
+	require.Len(t, txe.E.Signatures, 2)
+
+	local := tx.Sign(seed, other)
+	require.NoError(t, local.Err)
+	assert.Equal(t, local.E.Signatures, txe.E.Signatures)
+}
@@ -0,0 +1,39 @@
+package signer
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// Address returns the address of the remote key.
+func (kp *Keypair) Address() string {
+	return kp.public.Address()
+}
+
+// Hint returns the signature hint of the remote key.
+func (kp *Keypair) Hint() [4]byte {
+	return kp.public.Hint()
+}
+
+// Verify verifies locally that sig is a signature of input by the remote key.
+func (kp *Keypair) Verify(input []byte, sig []byte) error {
+	return kp.public.Verify(input, sig)
+}
+
+// Sign requests the signing service to sign input.
+func (kp *Keypair) Sign(input []byte) ([]byte, error) {
+	return kp.client.sign(kp.public, input)
+}
+
+// SignDecorated requests the signing service to sign input, and decorates the
+// resulting signature with the remote key's hint.
+func (kp *Keypair) SignDecorated(input []byte) (xdr.DecoratedSignature, error) {
+	sig, err := kp.Sign(input)
+	if err != nil {
+		return xdr.DecoratedSignature{}, err
+	}
+
+	return xdr.DecoratedSignature{
+		Hint:      xdr.SignatureHint(kp.Hint()),
+		Signature: xdr.Signature(sig),
+	}, nil
+}
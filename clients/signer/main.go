@@ -0,0 +1,64 @@
+// Package signer provides a client for remote signing services, allowing
+// transactions to be signed without the signing seed ever being held in the
+// memory of the calling process.
+//
+// The client speaks a minimal JSON protocol: it POSTs a request of the form
+// `{"address": "G...", "data": "<base64>"}` to the service's URL, and expects
+// a response of the form `{"signature": "<base64>"}` containing the ed25519
+// signature of the data by the key identified by address.  Every signature
+// received is verified before it is returned.
+package signer
+
+import (
+	"net/http"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+)
+
+// SignerResponseMaxSize is the maximum size of a response from a signing
+// service
+const SignerResponseMaxSize = 10 * 1024
+
+// ErrInvalidSignature is returned when a signing service responds with a
+// signature that does not verify against the requested key.
+var ErrInvalidSignature = errors.New("signing service returned an invalid signature")
+
+// Client represents a client of a remote signing service.
+type Client struct {
+	// URL is the endpoint of the signing service to which signing requests are
+	// posted.
+	URL string
+
+	// HTTP is the http client used to contact the signing service
+	HTTP HTTP
+}
+
+// HTTP represents the http client that a signer client uses to make http
+// requests.
+type HTTP interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Keypair is a keypair.KP whose signatures are produced by a remote signing
+// service.  It can be used with the build package's SignWith mutator.
+type Keypair struct {
+	client *Client
+	public keypair.KP
+}
+
+// SignRequest represents the body of a request made to a signing service.
+type SignRequest struct {
+	Address string `json:"address"`
+	Data    []byte `json:"data"`
+}
+
+// SignResponse represents the body of a successful response from a signing
+// service.
+type SignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// confirm interface conformity
+var _ keypair.KP = &Keypair{}
+var _ HTTP = http.DefaultClient
@@ -3,6 +3,7 @@ package stellartoml
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/BurntSushi/toml"
@@ -44,6 +45,36 @@ func (c *Client) GetStellarToml(domain string) (resp *Response, err error) {
 	return
 }
 
+// LintStellarToml lints the stellar.toml file served for a given domain using
+// Lint, also reporting a missing `Access-Control-Allow-Origin: *` header, which
+// SEP-1 requires so that web applications can load the file.
+func (c *Client) LintStellarToml(domain string) ([]Problem, error) {
+	hresp, err := c.HTTP.Get(c.url(domain))
+	if err != nil {
+		return nil, errors.Wrap(err, "http request errored")
+	}
+	defer hresp.Body.Close()
+
+	if !(hresp.StatusCode >= 200 && hresp.StatusCode < 300) {
+		return nil, errors.New("http request failed with non-200 status code")
+	}
+
+	// read past the limit, so that oversized files can be linted too
+	data, err := ioutil.ReadAll(io.LimitReader(hresp.Body, lintMaxSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "read response failed")
+	}
+
+	var problems []Problem
+	if hresp.Header.Get("Access-Control-Allow-Origin") != "*" {
+		problems = append(problems, Problem{
+			Reason: "served without the `Access-Control-Allow-Origin: *` header",
+		})
+	}
+
+	return append(problems, Lint(data)...), nil
+}
+
 // GetStellarTomlByAddress returns stellar.toml file of a domain fetched from a
 // given address
 func (c *Client) GetStellarTomlByAddress(addy string) (*Response, error) {
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stellar/go/support/http/httptest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -63,3 +64,37 @@ func TestClient(t *testing.T) {
 		assert.Contains(t, err.Error(), "toml decode failed")
 	}
 }
+
+func TestClient_LintStellarToml(t *testing.T) {
+	h := httptest.NewClient()
+	c := &Client{HTTP: h}
+
+	h.
+		On("GET", "https://stellar.org/.well-known/stellar.toml").
+		Return(func(*http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusOK, validToml)
+			resp.Header.Set("Access-Control-Allow-Origin", "*")
+			return resp, nil
+		})
+	problems, err := c.LintStellarToml("stellar.org")
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	// missing CORS header
+	h.
+		On("GET", "https://nocors.org/.well-known/stellar.toml").
+		ReturnString(http.StatusOK, `SIGNING_KEY = "GBAD"`)
+	problems, err = c.LintStellarToml("nocors.org")
+	require.NoError(t, err)
+	if assert.Len(t, problems, 2) {
+		assert.Contains(t, problems[0].Reason, "Access-Control-Allow-Origin")
+		assert.Equal(t, "SIGNING_KEY", problems[1].Field)
+	}
+
+	// not found
+	h.
+		On("GET", "https://missing.org/.well-known/stellar.toml").
+		ReturnNotFound()
+	_, err = c.LintStellarToml("missing.org")
+	assert.EqualError(t, err, "http request failed with non-200 status code")
+}
@@ -0,0 +1,204 @@
+package stellartoml
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stellar/go/strkey"
+)
+
+// currency statuses and anchored asset types allowed by SEP-1
+var (
+	currencyStatuses = []string{"live", "dead", "test", "private"}
+	anchorAssetTypes = []string{"fiat", "crypto", "stock", "bond", "commodity", "realestate", "other"}
+)
+
+// Lint decodes the stellar.toml file `data` and returns every problem found
+// in it, including those reported by Validate.  A file that is too large is
+// reported as a problem, but still checked.
+func Lint(data []byte) []Problem {
+	var problems []Problem
+	if len(data) > StellarTomlMaxSize {
+		problems = append(problems, Problem{
+			Reason: fmt.Sprintf("file is %d bytes long, exceeding the %d bytes limit", len(data), StellarTomlMaxSize),
+		})
+	}
+
+	var resp Response
+	_, err := toml.Decode(string(data), &resp)
+	if err != nil {
+		return append(problems, Problem{Reason: "invalid toml: " + err.Error()})
+	}
+
+	return append(problems, resp.Validate()...)
+}
+
+// Validate returns the problems found in `r`: missing required fields,
+// malformed account ids, urls and asset codes, and values not allowed by
+// SEP-1.
+func (r *Response) Validate() []Problem {
+	var v validation
+
+	v.url("FEDERATION_SERVER", r.FederationServer)
+	v.url("AUTH_SERVER", r.AuthServer)
+	v.url("TRANSFER_SERVER", r.TransferServer)
+	v.url("KYC_SERVER", r.KYCServer)
+	v.url("WEB_AUTH_ENDPOINT", r.WebAuthEndpoint)
+	v.url("HORIZON_URL", r.HorizonURL)
+	v.accountID("SIGNING_KEY", r.SigningKey)
+	for i, account := range r.Accounts {
+		v.accountID(fmt.Sprintf("ACCOUNTS[%d]", i), account)
+	}
+
+	// issuers must say who they are
+	doc := r.Documentation
+	if len(r.Currencies) > 0 {
+		v.required("DOCUMENTATION.ORG_NAME", doc.OrgName)
+		v.required("DOCUMENTATION.ORG_URL", doc.OrgURL)
+	}
+	v.url("DOCUMENTATION.ORG_URL", doc.OrgURL)
+	v.url("DOCUMENTATION.ORG_LOGO", doc.OrgLogo)
+	v.url("DOCUMENTATION.ORG_PHYSICAL_ADDRESS_ATTESTATION", doc.OrgPhysicalAddressAttestation)
+	v.url("DOCUMENTATION.ORG_PHONE_NUMBER_ATTESTATION", doc.OrgPhoneNumberAttestation)
+
+	for i, p := range r.Principals {
+		field := fmt.Sprintf("PRINCIPALS[%d].", i)
+		v.required(field+"name", p.Name)
+		v.required(field+"email", p.Email)
+	}
+
+	for i, c := range r.Currencies {
+		v.currency(fmt.Sprintf("CURRENCIES[%d].", i), c)
+	}
+
+	for i, val := range r.Validators {
+		field := fmt.Sprintf("VALIDATORS[%d].", i)
+		v.required(field+"PUBLIC_KEY", val.PublicKey)
+		v.accountID(field+"PUBLIC_KEY", val.PublicKey)
+		v.url(field+"HISTORY", val.History)
+	}
+
+	return v.problems
+}
+
+func (p Problem) String() string {
+	if p.Field == "" {
+		return p.Reason
+	}
+	return fmt.Sprintf("%s: %s", p.Field, p.Reason)
+}
+
+// validation accumulates the problems found by Validate.
+type validation struct {
+	problems []Problem
+}
+
+func (v *validation) add(field, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		Field:  field,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validation) currency(field string, c Currency) {
+	switch {
+	case c.Code == "" && c.CodeTemplate == "":
+		v.add(field+"code", "is required, unless code_template is set")
+	case c.Code != "" && !validAssetCode(c.Code, false):
+		v.add(field+"code", "%q is not a valid asset code", c.Code)
+	case c.CodeTemplate != "" && !validAssetCode(c.CodeTemplate, true):
+		v.add(field+"code_template", "%q is not a valid asset code template", c.CodeTemplate)
+	}
+
+	v.required(field+"issuer", c.Issuer)
+	v.accountID(field+"issuer", c.Issuer)
+	v.oneOf(field+"status", c.Status, currencyStatuses)
+	v.oneOf(field+"anchor_asset_type", c.AnchorAssetType, anchorAssetTypes)
+	v.url(field+"image", c.Image)
+	v.url(field+"approval_server", c.ApprovalServer)
+
+	if c.DisplayDecimals < 0 || c.DisplayDecimals > 7 {
+		v.add(field+"display_decimals", "must be between 0 and 7")
+	}
+	if c.IsAssetAnchored {
+		v.required(field+"anchor_asset_type", c.AnchorAssetType)
+	}
+	if c.Regulated {
+		v.required(field+"approval_server", c.ApprovalServer)
+	}
+
+	addresses := len(c.CollateralAddresses)
+	if n := len(c.CollateralAddressMessages); n > 0 && n != addresses {
+		v.add(field+"collateral_address_messages", "has %d entries for %d collateral addresses", n, addresses)
+	}
+	if n := len(c.CollateralAddressSignatures); n > 0 && n != addresses {
+		v.add(field+"collateral_address_signatures", "has %d entries for %d collateral addresses", n, addresses)
+	}
+}
+
+func (v *validation) required(field, value string) {
+	if value == "" {
+		v.add(field, "is required")
+	}
+}
+
+// accountID checks that `value`, unless empty, is a valid account id.
+func (v *validation) accountID(field, value string) {
+	if value == "" {
+		return
+	}
+
+	_, err := strkey.Decode(strkey.VersionByteAccountID, value)
+	if err != nil {
+		v.add(field, "%q is not a valid account id", value)
+	}
+}
+
+// url checks that `value`, unless empty, is an absolute https url.
+func (v *validation) url(field, value string) {
+	if value == "" {
+		return
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		v.add(field, "%q is not a valid url", value)
+		return
+	}
+	if u.Scheme != "https" {
+		v.add(field, "%q must use https", value)
+	}
+}
+
+// oneOf checks that `value`, unless empty, is one of `allowed`.
+func (v *validation) oneOf(field, value string, allowed []string) {
+	if value == "" {
+		return
+	}
+
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(field, "%q is not one of %v", value, allowed)
+}
+
+// validAssetCode returns true if `code` is made of 1 to 12 alphanumeric
+// characters, or of question marks too when it is a template.
+func validAssetCode(code string, template bool) bool {
+	if len(code) < 1 || len(code) > 12 {
+		return false
+	}
+
+	for _, c := range code {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '?' && template:
+		default:
+			return false
+		}
+	}
+	return true
+}
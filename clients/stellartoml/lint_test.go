@@ -0,0 +1,107 @@
+package stellartoml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	issuer    = "GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ"
+	validator = "GDXQB3OMMQ6MGG43PWFBZWBFKBBDUZIVSUDAZZTRAWQZKES2CDSE5HKJ"
+)
+
+const validToml = `
+VERSION = "2.0.0"
+NETWORK_PASSPHRASE = "Public Global Stellar Network ; September 2015"
+FEDERATION_SERVER = "https://example.com/federation"
+SIGNING_KEY = "` + issuer + `"
+ACCOUNTS = ["` + issuer + `"]
+
+[DOCUMENTATION]
+ORG_NAME = "Example"
+ORG_URL = "https://example.com"
+
+[[PRINCIPALS]]
+name = "Jane Doe"
+email = "jane@example.com"
+
+[[CURRENCIES]]
+code = "USD"
+issuer = "` + issuer + `"
+status = "live"
+display_decimals = 2
+is_asset_anchored = true
+anchor_asset_type = "fiat"
+anchor_asset = "USD"
+
+[[CURRENCIES]]
+code_template = "CORN????????"
+issuer = "` + issuer + `"
+
+[[VALIDATORS]]
+ALIAS = "example"
+PUBLIC_KEY = "` + validator + `"
+HOST = "core.example.com:11625"
+HISTORY = "https://history.example.com"
+`
+
+func TestLint(t *testing.T) {
+	assert.Empty(t, Lint([]byte(validToml)))
+
+	problems := Lint([]byte(`
+SIGNING_KEY = "GBAD"
+ACCOUNTS = ["` + issuer + `", "SCZANGBA5YHTNYVVV4C3U252E2B6P6F5T3U6MM63WBSBZATAQI3EBTQ4"]
+TRANSFER_SERVER = "http://example.com"
+
+[[PRINCIPALS]]
+name = "Jane Doe"
+
+[[CURRENCIES]]
+issuer = "` + issuer + `"
+
+[[CURRENCIES]]
+code = "TOOLONGASSETCODE"
+status = "gone"
+display_decimals = 9
+is_asset_anchored = true
+collateral_addresses = ["a", "b"]
+collateral_address_messages = ["a"]
+
+[[VALIDATORS]]
+HOST = "core.example.com:11625"
+`))
+
+	var found []string
+	for _, p := range problems {
+		found = append(found, p.String())
+	}
+	assert.Equal(t, []string{
+		`TRANSFER_SERVER: "http://example.com" must use https`,
+		`SIGNING_KEY: "GBAD" is not a valid account id`,
+		`ACCOUNTS[1]: "SCZANGBA5YHTNYVVV4C3U252E2B6P6F5T3U6MM63WBSBZATAQI3EBTQ4" is not a valid account id`,
+		`DOCUMENTATION.ORG_NAME: is required`,
+		`DOCUMENTATION.ORG_URL: is required`,
+		`PRINCIPALS[0].email: is required`,
+		`CURRENCIES[0].code: is required, unless code_template is set`,
+		`CURRENCIES[1].code: "TOOLONGASSETCODE" is not a valid asset code`,
+		`CURRENCIES[1].issuer: is required`,
+		`CURRENCIES[1].status: "gone" is not one of [live dead test private]`,
+		`CURRENCIES[1].display_decimals: must be between 0 and 7`,
+		`CURRENCIES[1].anchor_asset_type: is required`,
+		`CURRENCIES[1].collateral_address_messages: has 1 entries for 2 collateral addresses`,
+		`VALIDATORS[0].PUBLIC_KEY: is required`,
+	}, found)
+
+	// oversized files are still checked
+	problems = Lint([]byte(validToml + "# " + strings.Repeat("0", StellarTomlMaxSize)))
+	if assert.Len(t, problems, 1) {
+		assert.Contains(t, problems[0].String(), "exceeding the 102400 bytes limit")
+	}
+
+	problems = Lint([]byte(`FEDERATION_SERVER = `))
+	if assert.Len(t, problems, 1) {
+		assert.Contains(t, problems[0].String(), "invalid toml")
+	}
+}
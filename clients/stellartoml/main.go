@@ -2,8 +2,13 @@ package stellartoml
 
 import "net/http"
 
-// StellarTomlMaxSize is the maximum size of stellar.toml file
-const StellarTomlMaxSize = 5 * 1024
+// StellarTomlMaxSize is the maximum size of stellar.toml file, as set by
+// SEP-1.
+const StellarTomlMaxSize = 100 * 1024
+
+// lintMaxSize is the maximum size of the stellar.toml files read by
+// LintStellarToml.
+const lintMaxSize = 10 * StellarTomlMaxSize
 
 // WellKnownPath represents the url path at which the stellar.toml file should
 // exist to conform to the federation protocol.
@@ -29,12 +34,111 @@ type HTTP interface {
 	Get(url string) (*http.Response, error)
 }
 
-// Response represents the results of successfully resolving a stellar.toml file
+// Response represents the results of successfully resolving a stellar.toml
+// file, as described by SEP-1:
+// https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0001.md
 type Response struct {
-	AuthServer       string `toml:"AUTH_SERVER"`
-	FederationServer string `toml:"FEDERATION_SERVER"`
-	EncryptionKey    string `toml:"ENCRYPTION_KEY"`
-	SigningKey       string `toml:"SIGNING_KEY"`
+	Version           string   `toml:"VERSION"`
+	NetworkPassphrase string   `toml:"NETWORK_PASSPHRASE"`
+	FederationServer  string   `toml:"FEDERATION_SERVER"`
+	AuthServer        string   `toml:"AUTH_SERVER"`
+	TransferServer    string   `toml:"TRANSFER_SERVER"`
+	KYCServer         string   `toml:"KYC_SERVER"`
+	WebAuthEndpoint   string   `toml:"WEB_AUTH_ENDPOINT"`
+	HorizonURL        string   `toml:"HORIZON_URL"`
+	EncryptionKey     string   `toml:"ENCRYPTION_KEY"`
+	SigningKey        string   `toml:"SIGNING_KEY"`
+	Accounts          []string `toml:"ACCOUNTS"`
+
+	Documentation Documentation `toml:"DOCUMENTATION"`
+	Principals    []Principal   `toml:"PRINCIPALS"`
+	Currencies    []Currency    `toml:"CURRENCIES"`
+	Validators    []Validator   `toml:"VALIDATORS"`
+}
+
+// Documentation describes the organization publishing a stellar.toml file.
+type Documentation struct {
+	OrgName                       string `toml:"ORG_NAME"`
+	OrgDBA                        string `toml:"ORG_DBA"`
+	OrgURL                        string `toml:"ORG_URL"`
+	OrgLogo                       string `toml:"ORG_LOGO"`
+	OrgDescription                string `toml:"ORG_DESCRIPTION"`
+	OrgPhysicalAddress            string `toml:"ORG_PHYSICAL_ADDRESS"`
+	OrgPhysicalAddressAttestation string `toml:"ORG_PHYSICAL_ADDRESS_ATTESTATION"`
+	OrgPhoneNumber                string `toml:"ORG_PHONE_NUMBER"`
+	OrgPhoneNumberAttestation     string `toml:"ORG_PHONE_NUMBER_ATTESTATION"`
+	OrgKeybase                    string `toml:"ORG_KEYBASE"`
+	OrgTwitter                    string `toml:"ORG_TWITTER"`
+	OrgGithub                     string `toml:"ORG_GITHUB"`
+	OrgOfficialEmail              string `toml:"ORG_OFFICIAL_EMAIL"`
+	OrgLicensingAuthority         string `toml:"ORG_LICENSING_AUTHORITY"`
+	OrgLicenseType                string `toml:"ORG_LICENSE_TYPE"`
+	OrgLicenseNumber              string `toml:"ORG_LICENSE_NUMBER"`
+}
+
+// Principal describes a point of contact of the organization publishing a
+// stellar.toml file.
+type Principal struct {
+	Name                  string `toml:"name"`
+	Email                 string `toml:"email"`
+	Keybase               string `toml:"keybase"`
+	Telegram              string `toml:"telegram"`
+	Twitter               string `toml:"twitter"`
+	Github                string `toml:"github"`
+	IDPhotoHash           string `toml:"id_photo_hash"`
+	VerificationPhotoHash string `toml:"verification_photo_hash"`
+}
+
+// Currency describes an asset issued by the organization publishing a
+// stellar.toml file.  CodeTemplate is used instead of Code to describe a range
+// of assets, using `?` as a wildcard character.
+type Currency struct {
+	Code            string `toml:"code"`
+	CodeTemplate    string `toml:"code_template"`
+	Issuer          string `toml:"issuer"`
+	Status          string `toml:"status"`
+	DisplayDecimals int    `toml:"display_decimals"`
+	Name            string `toml:"name"`
+	Desc            string `toml:"desc"`
+	Conditions      string `toml:"conditions"`
+	Image           string `toml:"image"`
+
+	FixedNumber int64 `toml:"fixed_number"`
+	MaxNumber   int64 `toml:"max_number"`
+	IsUnlimited bool  `toml:"is_unlimited"`
+
+	IsAssetAnchored             bool     `toml:"is_asset_anchored"`
+	AnchorAssetType             string   `toml:"anchor_asset_type"`
+	AnchorAsset                 string   `toml:"anchor_asset"`
+	RedemptionInstructions      string   `toml:"redemption_instructions"`
+	CollateralAddresses         []string `toml:"collateral_addresses"`
+	CollateralAddressMessages   []string `toml:"collateral_address_messages"`
+	CollateralAddressSignatures []string `toml:"collateral_address_signatures"`
+
+	Regulated        bool   `toml:"regulated"`
+	ApprovalServer   string `toml:"approval_server"`
+	ApprovalCriteria string `toml:"approval_criteria"`
+}
+
+// Validator describes a stellar-core validator run by the organization
+// publishing a stellar.toml file.
+type Validator struct {
+	Alias       string `toml:"ALIAS"`
+	DisplayName string `toml:"DISPLAY_NAME"`
+	PublicKey   string `toml:"PUBLIC_KEY"`
+	Host        string `toml:"HOST"`
+	History     string `toml:"HISTORY"`
+}
+
+// Problem describes a single problem found when linting a stellar.toml file.
+type Problem struct {
+	// Field is the path of the field at fault, such as
+	// `CURRENCIES[0].issuer`, or an empty string when the problem concerns
+	// the file as a whole.
+	Field string
+
+	// Reason is a human readable description of the problem.
+	Reason string
 }
 
 // GetStellarToml returns stellar.toml file for a given domain
@@ -47,3 +151,8 @@ func GetStellarToml(domain string) (*Response, error) {
 func GetStellarTomlByAddress(addy string) (*Response, error) {
 	return DefaultClient.GetStellarTomlByAddress(addy)
 }
+
+// LintStellarToml lints the stellar.toml file served for a given domain
+func LintStellarToml(domain string) ([]Problem, error) {
+	return DefaultClient.LintStellarToml(domain)
+}
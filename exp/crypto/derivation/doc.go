@@ -1,3 +1,7 @@
 // Package derivation provides functions for ed25519 key derivation as described in:
 // https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+//
+// It also provides BIP-39 mnemonic support, as described in:
+// https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki
+// so that accounts can be derived from mnemonics as described in SEP-0005.
 package derivation
@@ -0,0 +1,168 @@
+package derivation
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/stellar/go/keypair"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Mnemonic support as described in:
+// https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki
+
+const (
+	// seedIterations is the number of PBKDF2 rounds used to derive a seed
+	// from a mnemonic.
+	seedIterations = 2048
+	// seedSaltPrefix prefixes the passphrase in the PBKDF2 salt.
+	seedSaltPrefix = "mnemonic"
+)
+
+var (
+	ErrInvalidWordCount = errors.New("Invalid mnemonic word count: must be 12, 15, 18, 21 or 24")
+	ErrInvalidEntropy   = errors.New("Invalid entropy length: must be 128 to 256 bits, in multiples of 32")
+	ErrInvalidChecksum  = errors.New("Invalid mnemonic checksum")
+	ErrInvalidIndex     = errors.New("Invalid account index")
+
+	wordIndex = map[string]int{}
+)
+
+func init() {
+	for i, word := range englishWordlist {
+		wordIndex[word] = i
+	}
+}
+
+// NewMnemonic generates a random mnemonic of `words` words.  24 word mnemonics
+// are the most common for stellar wallets.
+func NewMnemonic(words int) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", ErrInvalidWordCount
+	}
+
+	// every 3 words encode 32 bits of entropy plus a 1 bit checksum
+	entropy := make([]byte, words/3*4)
+	_, err := io.ReadFull(rand.Reader, entropy)
+	if err != nil {
+		return "", err
+	}
+
+	return MnemonicFromEntropy(entropy)
+}
+
+// MnemonicFromEntropy encodes `entropy` as a mnemonic.
+func MnemonicFromEntropy(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", ErrInvalidEntropy
+	}
+
+	checksumBits := uint(bits / 32)
+	hash := sha256.Sum256(entropy)
+
+	// append the checksum bits to the entropy
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, checksumBits)
+	data.Or(data, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	words := make([]string, (bits+int(checksumBits))/11)
+	mask := big.NewInt(2047)
+	for i := len(words) - 1; i >= 0; i-- {
+		index := new(big.Int).And(data, mask)
+		words[i] = englishWordlist[index.Int64()]
+		data.Rsh(data, 11)
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// EntropyFromMnemonic decodes the entropy encoded by `mnemonic`, validating
+// its checksum.
+func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, ErrInvalidWordCount
+	}
+
+	data := new(big.Int)
+	for _, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("Invalid mnemonic word: %q", word)
+		}
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(int64(index)))
+	}
+
+	checksumBits := uint(len(words) / 3)
+	checksum := new(big.Int).And(data, big.NewInt(1<<checksumBits-1))
+	data.Rsh(data, checksumBits)
+
+	// restore any leading zero bytes dropped by big.Int
+	raw := data.Bytes()
+	entropy := make([]byte, len(words)/3*4)
+	copy(entropy[len(entropy)-len(raw):], raw)
+
+	hash := sha256.Sum256(entropy)
+	if checksum.Int64() != int64(hash[0]>>(8-checksumBits)) {
+		return nil, ErrInvalidChecksum
+	}
+
+	return entropy, nil
+}
+
+// ValidateMnemonic returns an error if `mnemonic` is not a valid mnemonic
+// made from words of the english wordlist.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := EntropyFromMnemonic(mnemonic)
+	return err
+}
+
+// SeedFromMnemonic validates `mnemonic` and derives the seed it represents,
+// protected by the optional `passphrase`.  Use the seed with `DeriveForPath`.
+func SeedFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	err := ValidateMnemonic(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	password := []byte(strings.Join(words, " "))
+	salt := []byte(norm.NFKD.String(seedSaltPrefix + passphrase))
+
+	return pbkdf2.Key(password, salt, seedIterations, 64, sha512.New), nil
+}
+
+// AccountKeypair derives the keypair of the account at `index` from `seed`,
+// using `StellarAccountPathFormat`.
+func AccountKeypair(seed []byte, index uint32) (*keypair.Full, error) {
+	if index >= FirstHardenedIndex {
+		return nil, ErrInvalidIndex
+	}
+
+	key, err := DeriveForPath(fmt.Sprintf(StellarAccountPathFormat, index), seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return keypair.FromRawSeed(key.RawSeed())
+}
+
+// MnemonicAccountKeypair derives the keypair of the account at `index` from
+// `mnemonic` and the optional `passphrase`, as described in SEP-0005.
+func MnemonicAccountKeypair(mnemonic, passphrase string, index uint32) (*keypair.Full, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return AccountKeypair(seed, index)
+}
@@ -0,0 +1,173 @@
+package derivation
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleMnemonicAccountKeypair() {
+	mnemonic := "illness spike retreat truth genius clock brain pass fit cave bargain toe"
+
+	kp, err := MnemonicAccountKeypair(mnemonic, "", 0)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(kp.Address())
+
+	// Output:
+	// GDRXE2BQUC3AZNPVFSCEZ76NJ3WWL25FYFK6RGZGIEKWE4SOOHSUJUJ6
+}
+
+func TestWordlist(t *testing.T) {
+	assert.Len(t, englishWordlist, 2048)
+	// crc32 of english.txt, as published alongside the BIP-39 wordlists
+	assert.Equal(t, uint32(0xc1dbd296), crc32.ChecksumIEEE([]byte(englishWords)))
+}
+
+// SEP-0005 test vectors:
+// https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0005.md
+var sep5Vectors = []struct {
+	Mnemonic   string
+	Passphrase string
+	Seed       string
+	Accounts   []string
+}{
+	{
+		Mnemonic: "illness spike retreat truth genius clock brain pass fit cave bargain toe",
+		Seed:     "e4a5a632e70943ae7f07659df1332160937fad82587216a4c64315a0fb39497ee4a01f76ddab4cba68147977f3a147b6ad584c41808e8238a07f6cc4b582f186",
+		Accounts: []string{
+			"GDRXE2BQUC3AZNPVFSCEZ76NJ3WWL25FYFK6RGZGIEKWE4SOOHSUJUJ6 SBGWSG6BTNCKCOB3DIFBGCVMUPQFYPA2G4O34RMTB343OYPXU5DJDVMN",
+			"GBAW5XGWORWVFE2XTJYDTLDHXTY2Q2MO73HYCGB3XMFMQ562Q2W2GJQX SCEPFFWGAG5P2VX5DHIYK3XEMZYLTYWIPWYEKXFHSK25RVMIUNJ7CTIS",
+			"GAY5PRAHJ2HIYBYCLZXTHID6SPVELOOYH2LBPH3LD4RUMXUW3DOYTLXW SDAILLEZCSA67DUEP3XUPZJ7NYG7KGVRM46XA7K5QWWUIGADUZCZWTJP",
+		},
+	},
+	{
+		Mnemonic: "bench hurt jump file august wise shallow faculty impulse spring exact slush thunder author capable act festival slice deposit sauce coconut afford frown better",
+		Seed:     "937ae91f6ab6f12461d9936dfc1375ea5312d097f3f1eb6fed6a82fbe38c85824da8704389831482db0433e5f6c6c9700ff1946aa75ad8cc2654d6e40f567866",
+		Accounts: []string{
+			"GC3MMSXBWHL6CPOAVERSJITX7BH76YU252WGLUOM5CJX3E7UCYZBTPJQ SAEWIVK3VLNEJ3WEJRZXQGDAS5NVG2BYSYDFRSH4GKVTS5RXNVED5AX7",
+			"GB3MTYFXPBZBUINVG72XR7AQ6P2I32CYSXWNRKJ2PV5H5C7EAM5YYISO SBKSABCPDWXDFSZISAVJ5XKVIEWV4M5O3KBRRLSPY3COQI7ZP423FYB4",
+			"GDYF7GIHS2TRGJ5WW4MZ4ELIUIBINRNYPPAWVQBPLAZXC2JRDI4DGAKU SD5CCQAFRIPB3BWBHQYQ5SC66IB2AVMFNWWPBYGSUXVRZNCIRJ7IHESQ",
+		},
+	},
+	{
+		Mnemonic:   "cable spray genius state float twenty onion head street palace net private method loan turn phrase state blanket interest dry amazing dress blast tube",
+		Passphrase: "p4ssphr4se",
+		Seed:       "d425d39998fb42ce4cf31425f0eaec2f0a68f47655ea030d6d26e70200d8ff8bd4326b4bdf562ea8640a1501ae93ccd0fd7992116da5dfa24900e570a742a489",
+		Accounts: []string{
+			"GDAHPZ2NSYIIHZXM56Y36SBVTV5QKFIZGYMMBHOU53ETUSWTP62B63EQ SAFWTGXVS7ELMNCXELFWCFZOPMHUZ5LXNBGUVRCY3FHLFPXK4QPXYP2X",
+			"GDY47CJARRHHL66JH3RJURDYXAMIQ5DMXZLP3TDAUJ6IN2GUOFX4OJOC SBQPDFUGLMWJYEYXFRM5TQX3AX2BR47WKI4FDS7EJQUSEUUVY72MZPJF",
+			"GCLAQF5H5LGJ2A6ACOMNEHSWYDJ3VKVBUBHDWFGRBEPAVZ56L4D7JJID SAF2LXRW6FOSVQNC4HHIIDURZL4SCGCG7UEGG23ZQG6Q2DKIGMPZV6BZ",
+		},
+	},
+	{
+		Mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		Seed:     "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4",
+		Accounts: []string{
+			"GB3JDWCQJCWMJ3IILWIGDTQJJC5567PGVEVXSCVPEQOTDN64VJBDQBYX SBUV3MRWKNS6AYKZ6E6MOUVF2OYMON3MIUASWL3JLY5E3ISDJFELYBRZ",
+			"GDVSYYTUAJ3ACHTPQNSTQBDQ4LDHQCMNY4FCEQH5TJUMSSLWQSTG42MV SCHDCVCWGAKGIMTORV6K5DYYV3BY4WG3RA4M6MCBGJLHUCWU2MC6DL66",
+			"GBFPWBTN4AXHPWPTQVQBP4KRZ2YVYYOGRMV2PEYL2OBPPJDP7LECEVHR SAPLVTLUXSDLFRDGCCFLPDZMTCEVMP3ZXTM74EBJCVKZKM34LGQPF7K3",
+		},
+	},
+}
+
+func TestSEP5Vectors(t *testing.T) {
+	for _, v := range sep5Vectors {
+		seed, err := SeedFromMnemonic(v.Mnemonic, v.Passphrase)
+		require.NoError(t, err)
+		assert.Equal(t, v.Seed, hex.EncodeToString(seed))
+
+		for i, expected := range v.Accounts {
+			kp, err := MnemonicAccountKeypair(v.Mnemonic, v.Passphrase, uint32(i))
+			require.NoError(t, err)
+			assert.Equal(t, expected, kp.Address()+" "+kp.Seed())
+		}
+	}
+}
+
+func TestMnemonicFromEntropy(t *testing.T) {
+	// BIP-39 reference vectors
+	cases := []struct {
+		Entropy  string
+		Mnemonic string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		},
+		{
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		},
+	}
+
+	for _, kase := range cases {
+		entropy, _ := hex.DecodeString(kase.Entropy)
+
+		mnemonic, err := MnemonicFromEntropy(entropy)
+		require.NoError(t, err)
+		assert.Equal(t, kase.Mnemonic, mnemonic)
+
+		decoded, err := EntropyFromMnemonic(mnemonic)
+		require.NoError(t, err)
+		assert.Equal(t, entropy, decoded)
+	}
+
+	_, err := MnemonicFromEntropy(make([]byte, 15))
+	assert.Equal(t, ErrInvalidEntropy, err)
+}
+
+func TestSeedFromMnemonic_Passphrase(t *testing.T) {
+	seed, err := SeedFromMnemonic(
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"TREZOR",
+	)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		hex.EncodeToString(seed),
+	)
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	assert.NoError(t, ValidateMnemonic(sep5Vectors[1].Mnemonic))
+
+	// extra whitespace is ignored
+	assert.NoError(t, ValidateMnemonic("  "+strings.Replace(sep5Vectors[0].Mnemonic, " ", "\n ", -1)))
+
+	assert.Equal(t, ErrInvalidChecksum, ValidateMnemonic(
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+	))
+	assert.Equal(t, ErrInvalidWordCount, ValidateMnemonic("abandon abandon about"))
+	assert.Error(t, ValidateMnemonic(
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon stellar",
+	))
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for _, words := range []int{12, 15, 18, 21, 24} {
+		mnemonic, err := NewMnemonic(words)
+		require.NoError(t, err)
+		assert.Len(t, strings.Fields(mnemonic), words)
+		assert.NoError(t, ValidateMnemonic(mnemonic))
+	}
+
+	_, err := NewMnemonic(13)
+	assert.Equal(t, ErrInvalidWordCount, err)
+}
+
+func TestAccountKeypair_InvalidIndex(t *testing.T) {
+	_, err := AccountKeypair(make([]byte, 64), FirstHardenedIndex)
+	assert.Equal(t, ErrInvalidIndex, err)
+}
@@ -47,6 +47,16 @@ func (kp *FromAddress) SignDecorated(input []byte) (xdr.DecoratedSignature, erro
 	return xdr.DecoratedSignature{}, ErrCannotSign
 }
 
+// SignMessage always returns ErrCannotSign, as only the address is known.
+func (kp *FromAddress) SignMessage(message []byte) ([]byte, error) {
+	return nil, ErrCannotSign
+}
+
+// VerifyMessage verifies a signature of `message` made using SignMessage.
+func (kp *FromAddress) VerifyMessage(message []byte, signature []byte) error {
+	return VerifyMessage(kp, message, signature)
+}
+
 func (kp *FromAddress) publicKey() *[32]byte {
 	bytes := strkey.MustDecode(strkey.VersionByteAccountID, kp.address)
 	var result [32]byte
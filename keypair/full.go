@@ -56,6 +56,17 @@ func (kp *Full) SignDecorated(input []byte) (xdr.DecoratedSignature, error) {
 	}, nil
 }
 
+// SignMessage signs the off-chain message `message`, as described by
+// MessageHash.
+func (kp *Full) SignMessage(message []byte) ([]byte, error) {
+	return SignMessage(kp, message)
+}
+
+// VerifyMessage verifies a signature of `message` made using SignMessage.
+func (kp *Full) VerifyMessage(message []byte, signature []byte) error {
+	return VerifyMessage(kp, message, signature)
+}
+
 func (kp *Full) publicKey() *[32]byte {
 	pub, _ := kp.keys()
 	return pub
@@ -0,0 +1,249 @@
+package keypair
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// KeystoreVersion is the version of the keystore format written by this
+	// package.
+	KeystoreVersion = 1
+
+	// KeystoreCipher names the authenticated cipher used to encrypt keystore
+	// seeds: NaCl's secretbox construction.
+	KeystoreCipher = "xsalsa20-poly1305"
+
+	// KeystoreKDF names the key derivation function used to derive keystore
+	// encryption keys from passwords.
+	KeystoreKDF = "scrypt"
+
+	// maxScryptN, maxScryptR, maxScryptP and maxScryptCost bound the cost of
+	// the keystores we are willing to decrypt, so that a malicious keystore
+	// cannot make us allocate unbounded memory or spin for hours.  scrypt
+	// uses about 128·N·r bytes of memory and its running time grows with
+	// N·r·p, so the product is bounded too: the largest keystore accepted
+	// takes about 1GB of memory, or as much time to decrypt.
+	maxScryptN    = 1 << 20
+	maxScryptR    = 32
+	maxScryptP    = 16
+	maxScryptCost = 1 << 23
+)
+
+// DefaultScryptParams are the scrypt parameters used by SaveKeystore.
+var DefaultScryptParams = ScryptParams{N: 1 << 18, R: 8, P: 1}
+
+// Keystore is a seed encrypted using a key derived from a password, along with
+// the address of the seed and some metadata.  It is serialized as JSON.
+type Keystore struct {
+	Version   int            `json:"version"`
+	Address   string         `json:"address"`
+	Name      string         `json:"name,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	Crypto    KeystoreCrypto `json:"crypto"`
+}
+
+// KeystoreCrypto holds the encrypted seed of a keystore and the parameters
+// needed to decrypt it.
+type KeystoreCrypto struct {
+	Cipher     string       `json:"cipher"`
+	CipherText []byte       `json:"ciphertext"`
+	Nonce      []byte       `json:"nonce"`
+	KDF        string       `json:"kdf"`
+	KDFParams  ScryptParams `json:"kdfparams"`
+}
+
+// ScryptParams are the parameters of the scrypt key derivation function.
+type ScryptParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+}
+
+// NewKeystore encrypts the seed of `kp` using a key derived from `password`
+// with scrypt, configured using `params`.  A random salt is generated when
+// params.Salt is empty.
+func NewKeystore(kp *Full, password []byte, params ScryptParams) (*Keystore, error) {
+	if len(params.Salt) == 0 {
+		params.Salt = make([]byte, 32)
+		_, err := io.ReadFull(rand.Reader, params.Salt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := params.key(password)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [24]byte
+	_, err = io.ReadFull(rand.Reader, nonce[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keystore{
+		Version:   KeystoreVersion,
+		Address:   kp.Address(),
+		CreatedAt: time.Now().UTC(),
+		Crypto: KeystoreCrypto{
+			Cipher:     KeystoreCipher,
+			CipherText: secretbox.Seal(nil, kp.rawSeed(), &nonce, key),
+			Nonce:      nonce[:],
+			KDF:        KeystoreKDF,
+			KDFParams:  params,
+		},
+	}, nil
+}
+
+// ReadKeystore reads a JSON encoded keystore from `r`.
+func ReadKeystore(r io.Reader) (*Keystore, error) {
+	var ks Keystore
+	err := json.NewDecoder(r).Decode(&ks)
+	if err != nil {
+		return nil, err
+	}
+
+	if ks.Version != KeystoreVersion {
+		return nil, fmt.Errorf("keystore: unsupported version %d", ks.Version)
+	}
+
+	return &ks, nil
+}
+
+// LoadKeystore reads the keystore at `path` and decrypts it using `password`.
+func LoadKeystore(path string, password []byte) (*Full, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ks, err := ReadKeystore(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return ks.Decrypt(password)
+}
+
+// SaveKeystore encrypts `kp` using `password` and DefaultScryptParams, and
+// writes the resulting keystore to a new file at `path` that only its owner
+// may read.  It fails if a file already exists at `path`.
+func SaveKeystore(path string, kp *Full, password []byte) error {
+	ks, err := NewKeystore(kp, password, DefaultScryptParams)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = ks.Write(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Decrypt decrypts the keystore's seed using `password`.  ErrInvalidPassword
+// is returned when the password is wrong or the keystore has been tampered
+// with.
+func (ks *Keystore) Decrypt(password []byte) (*Full, error) {
+	if ks.Crypto.Cipher != KeystoreCipher {
+		return nil, fmt.Errorf("keystore: unsupported cipher %q", ks.Crypto.Cipher)
+	}
+
+	if ks.Crypto.KDF != KeystoreKDF {
+		return nil, fmt.Errorf("keystore: unsupported kdf %q", ks.Crypto.KDF)
+	}
+
+	if err := ks.Crypto.KDFParams.checkCost(); err != nil {
+		return nil, err
+	}
+
+	if len(ks.Crypto.Nonce) != 24 {
+		return nil, fmt.Errorf("keystore: invalid nonce")
+	}
+
+	key, err := ks.Crypto.KDFParams.key(password)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [24]byte
+	copy(nonce[:], ks.Crypto.Nonce)
+
+	raw, ok := secretbox.Open(nil, ks.Crypto.CipherText, &nonce, key)
+	if !ok || len(raw) != 32 {
+		return nil, ErrInvalidPassword
+	}
+
+	var rawSeed [32]byte
+	copy(rawSeed[:], raw)
+
+	kp, err := FromRawSeed(rawSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	// the address is stored in plain text, so check that it was not changed
+	if kp.Address() != ks.Address {
+		return nil, fmt.Errorf("keystore: seed does not match address %s", ks.Address)
+	}
+
+	return kp, nil
+}
+
+// Write writes the keystore to `w` as JSON.
+func (ks *Keystore) Write(w io.Writer) error {
+	raw, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(raw, '\n'))
+	return err
+}
+
+// checkCost returns an error if deriving a key using `params` would cost
+// more than the bounds above.
+func (params ScryptParams) checkCost() error {
+	switch {
+	case params.N > maxScryptN:
+		return fmt.Errorf("keystore: scrypt N of %d is too large", params.N)
+	case params.R > maxScryptR:
+		return fmt.Errorf("keystore: scrypt r of %d is too large", params.R)
+	case params.P > maxScryptP:
+		return fmt.Errorf("keystore: scrypt p of %d is too large", params.P)
+	case params.N < 1 || params.R < 1 || params.P < 1:
+		return fmt.Errorf("keystore: invalid scrypt parameters")
+	case params.N*params.R*params.P > maxScryptCost:
+		return fmt.Errorf("keystore: scrypt cost of N·r·p = %d is too large", params.N*params.R*params.P)
+	}
+	return nil
+}
+
+func (params ScryptParams) key(password []byte) (*[32]byte, error) {
+	raw, err := scrypt.Key(password, params.Salt, params.N, params.R, params.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: %s", err)
+	}
+
+	var key [32]byte
+	copy(key[:], raw)
+	return &key, nil
+}
@@ -0,0 +1,113 @@
+package keypair
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("keypair.Keystore", func() {
+	var (
+		kp       *Full
+		password = []byte("correct horse battery staple")
+		params   = ScryptParams{N: 1 << 10, R: 8, P: 1}
+	)
+
+	BeforeEach(func() {
+		kp = &Full{seed}
+	})
+
+	It("round trips through JSON", func() {
+		ks, err := NewKeystore(kp, password, params)
+		Expect(err).To(BeNil())
+		Expect(ks.Version).To(Equal(KeystoreVersion))
+		Expect(ks.Address).To(Equal(address))
+
+		var buf bytes.Buffer
+		Expect(ks.Write(&buf)).To(Succeed())
+		Expect(buf.String()).NotTo(ContainSubstring(seed))
+
+		loaded, err := ReadKeystore(&buf)
+		Expect(err).To(BeNil())
+
+		decrypted, err := loaded.Decrypt(password)
+		Expect(err).To(BeNil())
+		Expect(decrypted.Seed()).To(Equal(seed))
+	})
+
+	It("rejects the wrong password", func() {
+		ks, err := NewKeystore(kp, password, params)
+		Expect(err).To(BeNil())
+
+		_, err = ks.Decrypt([]byte("hunter2"))
+		Expect(err).To(Equal(ErrInvalidPassword))
+	})
+
+	It("rejects tampered keystores", func() {
+		ks, err := NewKeystore(kp, password, params)
+		Expect(err).To(BeNil())
+
+		ks.Crypto.CipherText[0] ^= 0xff
+		_, err = ks.Decrypt(password)
+		Expect(err).To(Equal(ErrInvalidPassword))
+		ks.Crypto.CipherText[0] ^= 0xff
+
+		ks.Address = "GAXEMCEXBERNSRXOEKD4JAIKVECIXQCENHEBRVSPX2TTYZPMNEDSQCNQ"
+		_, err = ks.Decrypt(password)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects keystores that are too costly to decrypt", func() {
+		ks, err := NewKeystore(kp, password, params)
+		Expect(err).To(BeNil())
+
+		for _, costly := range []ScryptParams{
+			{N: 1 << 21, R: 8, P: 1},
+			{N: 1 << 10, R: 1 << 20, P: 1},
+			{N: 1 << 10, R: 8, P: 1 << 20},
+			{N: 1 << 20, R: 32, P: 16},
+		} {
+			costly.Salt = ks.Crypto.KDFParams.Salt
+			ks.Crypto.KDFParams = costly
+
+			start := time.Now()
+			_, err = ks.Decrypt(password)
+			Expect(err).To(MatchError(ContainSubstring("too large")))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		}
+	})
+
+	It("rejects unknown versions", func() {
+		_, err := ReadKeystore(bytes.NewBufferString(`{"version": 2}`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("saves and loads keystore files", func() {
+		dir, err := ioutil.TempDir("", "keystore")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+
+		defaults := DefaultScryptParams
+		DefaultScryptParams = params
+		defer func() { DefaultScryptParams = defaults }()
+
+		path := filepath.Join(dir, "test.json")
+		Expect(SaveKeystore(path, kp, password)).To(Succeed())
+
+		info, err := os.Stat(path)
+		Expect(err).To(BeNil())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		// existing keystores are never overwritten
+		Expect(SaveKeystore(path, kp, password)).NotTo(Succeed())
+
+		loaded, err := LoadKeystore(path, password)
+		Expect(err).To(BeNil())
+		Expect(loaded.Address()).To(Equal(address))
+	})
+})
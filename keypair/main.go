@@ -23,6 +23,10 @@ var (
 	// ErrCannotSign is returned when attempting to sign a message when
 	// the keypair does not have the secret key available
 	ErrCannotSign = errors.New("cannot sign")
+
+	// ErrInvalidPassword is returned when a keystore cannot be decrypted,
+	// either because the password is wrong or the keystore was tampered with.
+	ErrInvalidPassword = errors.New("keystore: invalid password")
 )
 
 const (
@@ -0,0 +1,113 @@
+package keypair
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/stellar/go/strkey"
+)
+
+// MessagePrefix is prepended to every message before it is hashed and signed
+// by SignMessage.  Transaction signatures are made over a hash that starts
+// with a network id, so the prefix ensures that a signed message can never be
+// used as a transaction signature, and vice versa.
+const MessagePrefix = "Stellar Signed Message:\n"
+
+// ErrInvalidSignedMessage is returned by ParseSignedMessage when the provided
+// string is not an encoded signed message.
+var ErrInvalidSignedMessage = errors.New("invalid signed message")
+
+// SignedMessage is an off-chain message signed by the keypair of Address.
+type SignedMessage struct {
+	Address   string
+	Message   []byte
+	Signature []byte
+}
+
+// MessageHash returns the hash signed by SignMessage for `message`: the sha256
+// hash of MessagePrefix followed by the message.
+func MessageHash(message []byte) [32]byte {
+	return sha256.Sum256(append([]byte(MessagePrefix), message...))
+}
+
+// SignMessage signs `message` using `kp`, as described by MessageHash.
+func SignMessage(kp KP, message []byte) ([]byte, error) {
+	hash := MessageHash(message)
+	return kp.Sign(hash[:])
+}
+
+// VerifyMessage verifies that `signature` is a signature of `message` made by
+// `kp` using SignMessage.
+func VerifyMessage(kp KP, message []byte, signature []byte) error {
+	hash := MessageHash(message)
+	return kp.Verify(hash[:], signature)
+}
+
+// NewSignedMessage signs `message` using `kp`, returning the result as a
+// SignedMessage.
+func NewSignedMessage(kp KP, message []byte) (*SignedMessage, error) {
+	sig, err := SignMessage(kp, message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedMessage{
+		Address:   kp.Address(),
+		Message:   message,
+		Signature: sig,
+	}, nil
+}
+
+// ParseSignedMessage decodes a signed message encoded by SignedMessage.String.
+// It does not verify the signature; use Verify for that.
+func ParseSignedMessage(s string) (*SignedMessage, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidSignedMessage
+	}
+
+	_, err := strkey.Decode(strkey.VersionByteAccountID, parts[0])
+	if err != nil {
+		return nil, ErrInvalidSignedMessage
+	}
+
+	message, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidSignedMessage
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(sig) != 64 {
+		return nil, ErrInvalidSignedMessage
+	}
+
+	return &SignedMessage{
+		Address:   parts[0],
+		Message:   message,
+		Signature: sig,
+	}, nil
+}
+
+// String encodes the signed message compactly, as its address, message and
+// signature separated by periods.  The message and signature are encoded using
+// unpadded url-safe base64, so the result can be pasted into emails, support
+// tickets and urls.
+func (m *SignedMessage) String() string {
+	return strings.Join([]string{
+		m.Address,
+		base64.RawURLEncoding.EncodeToString(m.Message),
+		base64.RawURLEncoding.EncodeToString(m.Signature),
+	}, ".")
+}
+
+// Verify verifies that the message was signed by the keypair of its address.
+func (m *SignedMessage) Verify() error {
+	_, err := strkey.Decode(strkey.VersionByteAccountID, m.Address)
+	if err != nil {
+		return err
+	}
+
+	return VerifyMessage(&FromAddress{m.Address}, m.Message, m.Signature)
+}
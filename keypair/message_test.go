@@ -0,0 +1,75 @@
+package keypair
+
+import (
+	"crypto/sha256"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("signed messages", func() {
+	var (
+		full    *Full
+		public  *FromAddress
+		message = []byte("I own this account, ticket #1234")
+	)
+
+	BeforeEach(func() {
+		full = &Full{seed}
+		public = &FromAddress{address}
+	})
+
+	It("signs and verifies messages", func() {
+		sig, err := full.SignMessage(message)
+		Expect(err).To(BeNil())
+
+		Expect(full.VerifyMessage(message, sig)).To(Succeed())
+		Expect(public.VerifyMessage(message, sig)).To(Succeed())
+		Expect(public.VerifyMessage([]byte("something else"), sig)).To(Equal(ErrInvalidSignature))
+
+		// the raw message was not what was signed
+		Expect(public.Verify(message, sig)).To(Equal(ErrInvalidSignature))
+
+		_, err = public.SignMessage(message)
+		Expect(err).To(Equal(ErrCannotSign))
+	})
+
+	It("hashes the prefixed message", func() {
+		hash := MessageHash(message)
+		Expect(hash).To(Equal(sha256.Sum256([]byte(MessagePrefix + string(message)))))
+	})
+
+	It("round trips the compact encoding", func() {
+		signed, err := NewSignedMessage(full, message)
+		Expect(err).To(BeNil())
+
+		encoded := signed.String()
+		Expect(encoded).To(HavePrefix(address + "."))
+		Expect(encoded).NotTo(ContainSubstring("="))
+
+		parsed, err := ParseSignedMessage(encoded)
+		Expect(err).To(BeNil())
+		Expect(parsed).To(Equal(signed))
+		Expect(parsed.Verify()).To(Succeed())
+
+		parsed.Message = []byte("I own every account")
+		Expect(parsed.Verify()).To(Equal(ErrInvalidSignature))
+	})
+
+	It("rejects malformed encodings", func() {
+		signed, err := NewSignedMessage(full, message)
+		Expect(err).To(BeNil())
+		encoded := signed.String()
+
+		for _, bad := range []string{
+			"",
+			address,
+			encoded + ".extra",
+			"GBAD." + encoded[len(address)+1:],
+			encoded[:len(encoded)-4],
+		} {
+			_, err := ParseSignedMessage(bad)
+			Expect(err).To(Equal(ErrInvalidSignedMessage), bad)
+		}
+	})
+})
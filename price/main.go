@@ -10,9 +10,14 @@ import (
 	"math"
 	"math/big"
 
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/xdr"
 )
 
+// ErrInvalidPrice is returned when a price's numerator or denominator is not
+// positive.
+var ErrInvalidPrice = errors.New("invalid price")
+
 // Parse  calculates and returns the best rational approximation of the given
 // real number price while still keeping both the numerator and the denominator
 // of the resulting value within the precision limits of a 32-bit signed
@@ -21,6 +26,126 @@ func Parse(v string) (xdr.Price, error) {
 	return continuedFraction(v)
 }
 
+// Invert returns the inverse of `p`, i.e. the price of the other asset of an
+// offer.
+func Invert(p xdr.Price) (xdr.Price, error) {
+	if p.N <= 0 || p.D <= 0 {
+		return xdr.Price{}, ErrInvalidPrice
+	}
+
+	return xdr.Price{N: p.D, D: p.N}, nil
+}
+
+// Mul returns `a` multiplied by `p`, rounded using `r`.  An offer's price is
+// the amount of the buying asset paid for one unit of the selling asset, so
+// Mul converts an amount of the selling asset into an amount of the buying
+// asset.  The computation is done the same way stellar-core crosses offers:
+// the full product is divided by the denominator before rounding.
+func Mul(a xdr.Int64, p xdr.Price, r amount.Rounding) (xdr.Int64, error) {
+	if p.N <= 0 || p.D <= 0 {
+		return xdr.Int64(0), ErrInvalidPrice
+	}
+
+	return amount.MulDiv(int64(a), int64(p.N), int64(p.D), r)
+}
+
+// Div returns `a` divided by `p`, rounded using `r`.  It is the inverse of Mul,
+// converting an amount of an offer's buying asset into an amount of its
+// selling asset.
+//
+// When crossing an offer stellar-core rounds the amount of the selling asset
+// received down, and the amount of the buying asset paid for it up, so that the
+// owner of the offer never receives less than its price:
+//
+//	received, _ := price.Div(send, p, amount.RoundDown)
+//	paid, _ := price.Mul(received, p, amount.RoundUp)
+func Div(a xdr.Int64, p xdr.Price, r amount.Rounding) (xdr.Int64, error) {
+	if p.N <= 0 || p.D <= 0 {
+		return xdr.Int64(0), ErrInvalidPrice
+	}
+
+	return amount.MulDiv(int64(a), int64(p.D), int64(p.N), r)
+}
+
+// Approximate returns the price closest to `v` whose denominator is at most
+// `maxDenominator` and whose numerator fits within a 32-bit signed integer.
+// Unlike Parse, which returns the last convergent of the continued fraction of
+// a price that fits, Approximate also considers the semiconvergents, so the
+// result is the best rational approximation within the bound.
+func Approximate(v *big.Rat, maxDenominator int32) (xdr.Price, error) {
+	if v.Sign() <= 0 || maxDenominator <= 0 {
+		return xdr.Price{}, ErrInvalidPrice
+	}
+
+	maxN := big.NewInt(math.MaxInt32)
+	maxD := big.NewInt(int64(maxDenominator))
+
+	if v.Num().Cmp(maxN) <= 0 && v.Denom().Cmp(maxD) <= 0 {
+		return xdr.Price{
+			N: xdr.Int32(v.Num().Int64()),
+			D: xdr.Int32(v.Denom().Int64()),
+		}, nil
+	}
+
+	// p0/q0 and p1/q1 are the last two convergents that fit within the bounds
+	var (
+		p0, q0 = big.NewInt(0), big.NewInt(1)
+		p1, q1 = big.NewInt(1), big.NewInt(0)
+		n, d   = new(big.Int).Set(v.Num()), new(big.Int).Set(v.Denom())
+	)
+
+	for d.Sign() != 0 {
+		a, m := new(big.Int).QuoRem(n, d, new(big.Int))
+
+		p2 := new(big.Int).Mul(a, p1)
+		p2.Add(p2, p0)
+		q2 := new(big.Int).Mul(a, q1)
+		q2.Add(q2, q0)
+
+		if p2.Cmp(maxN) > 0 || q2.Cmp(maxD) > 0 {
+			break
+		}
+
+		p0, q0, p1, q1 = p1, q1, p2, q2
+		n, d = d, m
+	}
+
+	if q1.Sign() == 0 {
+		return xdr.Price{}, errors.New("Couldn't find approximation")
+	}
+
+	// the best semiconvergent uses the largest multiple of the last convergent
+	// that keeps both the numerator and denominator within the bounds
+	k := new(big.Int).Sub(maxD, q0)
+	k.Quo(k, q1)
+	if p1.Sign() > 0 {
+		kn := new(big.Int).Sub(maxN, p0)
+		kn.Quo(kn, p1)
+		if kn.Cmp(k) < 0 {
+			k = kn
+		}
+	}
+
+	sn := new(big.Int).Mul(k, p1)
+	sn.Add(sn, p0)
+	sd := new(big.Int).Mul(k, q1)
+	sd.Add(sd, q0)
+
+	n, d = p1, q1
+	if sn.Sign() > 0 && sd.Sign() > 0 && distance(v, sn, sd).Cmp(distance(v, p1, q1)) < 0 {
+		n, d = sn, sd
+	}
+
+	if n.Sign() == 0 {
+		return xdr.Price{}, errors.New("Couldn't find approximation")
+	}
+
+	return xdr.Price{
+		N: xdr.Int32(n.Int64()),
+		D: xdr.Int32(d.Int64()),
+	}, nil
+}
+
 // continuedFraction calculates and returns the best rational approximation of
 // the given real number.
 func continuedFraction(price string) (xdrPrice xdr.Price, err error) {
@@ -91,3 +216,10 @@ func floor(n *big.Rat) *big.Rat {
 	f.SetInt(z)
 	return f
 }
+
+// distance returns the absolute difference between `v` and n/d.
+func distance(v *big.Rat, n, d *big.Int) *big.Rat {
+	r := new(big.Rat).SetFrac(n, d)
+	r.Sub(r, v)
+	return r.Abs(r)
+}
@@ -1,8 +1,11 @@
 package price_test
 
 import (
+	"math"
+	"math/big"
 	"testing"
 
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/price"
 	"github.com/stellar/go/xdr"
 )
@@ -55,3 +58,110 @@ func TestParse(t *testing.T) {
 		t.Error("Expected error")
 	}
 }
+
+func TestInvert(t *testing.T) {
+	p, err := price.Invert(xdr.Price{1, 3})
+	if err != nil || p != (xdr.Price{3, 1}) {
+		t.Errorf("Expected 3/1, got %v (%v)", p, err)
+	}
+
+	_, err = price.Invert(xdr.Price{0, 3})
+	if err != price.ErrInvalidPrice {
+		t.Errorf("Expected ErrInvalidPrice, got %v", err)
+	}
+}
+
+func TestMulDiv(t *testing.T) {
+	p := xdr.Price{2, 3}
+
+	cases := []struct {
+		Name string
+		Fn   func(xdr.Int64, xdr.Price, amount.Rounding) (xdr.Int64, error)
+		A    xdr.Int64
+		R    amount.Rounding
+		I    xdr.Int64
+	}{
+		{"mul", price.Mul, 30, amount.RoundDown, 20},
+		{"mul down", price.Mul, 10, amount.RoundDown, 6},
+		{"mul up", price.Mul, 10, amount.RoundUp, 7},
+		{"mul half even", price.Mul, 10, amount.RoundHalfEven, 7},
+		{"div", price.Div, 20, amount.RoundDown, 30},
+		{"div down", price.Div, 7, amount.RoundDown, 10},
+		{"div up", price.Div, 7, amount.RoundUp, 11},
+		{"div half even", price.Div, 5, amount.RoundHalfEven, 8},
+	}
+
+	for _, c := range cases {
+		o, err := c.Fn(c.A, p, c.R)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.Name, err)
+			continue
+		}
+
+		if o != c.I {
+			t.Errorf("%s: got %d, not %d", c.Name, o, c.I)
+		}
+	}
+
+	// crossing an offer never pays less than its price
+	received, _ := price.Div(7, p, amount.RoundDown)
+	paid, _ := price.Mul(received, p, amount.RoundUp)
+	if received != 10 || paid != 7 {
+		t.Errorf("Expected to receive 10 for 7, got %d for %d", received, paid)
+	}
+
+	_, err := price.Mul(1, xdr.Price{1, 0}, amount.RoundDown)
+	if err != price.ErrInvalidPrice {
+		t.Errorf("Expected ErrInvalidPrice, got %v", err)
+	}
+
+	_, err = price.Mul(math.MaxInt64, xdr.Price{2, 1}, amount.RoundDown)
+	if err != amount.ErrOverflow {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+}
+
+func TestApproximate(t *testing.T) {
+	cases := []struct {
+		S    string
+		MaxD int32
+		P    xdr.Price
+	}{
+		{"3.141592653589793", 1000, xdr.Price{355, 113}},
+		{"3.141592653589793", 100, xdr.Price{311, 99}},
+		{"0.333", 10, xdr.Price{1, 3}},
+		{"0.85334384", 1000, xdr.Price{803, 941}},
+		{"0.85334384", math.MaxInt32, xdr.Price{5333399, 6250000}},
+		{"0.5", 10, xdr.Price{1, 2}},
+		{"0.0000000003", math.MaxInt32, xdr.Price{1, math.MaxInt32}},
+	}
+
+	for _, c := range cases {
+		v, _ := new(big.Rat).SetString(c.S)
+
+		o, err := price.Approximate(v, c.MaxD)
+		if err != nil {
+			t.Errorf("Couldn't approximate %s: %v+", c.S, err)
+			continue
+		}
+
+		if o != c.P {
+			t.Errorf("%s approximated to %d, not %d", c.S, o, c.P)
+		}
+	}
+
+	// the numerator is bounded too
+	v, _ := new(big.Rat).SetString("1.4142135623730951")
+	o, err := price.Approximate(v, math.MaxInt32)
+	if err != nil || o.N <= 0 || o.D <= 0 {
+		t.Errorf("Couldn't approximate %s: %v+", v, err)
+	}
+
+	for _, s := range []string{"0", "-1", "2147483649"} {
+		v, _ := new(big.Rat).SetString(s)
+		_, err := price.Approximate(v, 1000)
+		if err == nil {
+			t.Errorf("Expected error approximating %s", s)
+		}
+	}
+}
@@ -262,41 +262,27 @@ func (base *Base) GetAsset(prefix string) (result xdr.Asset) {
 	if base.Err != nil {
 		return
 	}
-	var value interface{}
 
 	t := base.GetAssetType(prefix + "asset_type")
+	if base.Err != nil {
+		return
+	}
 
-	switch t {
-	case xdr.AssetTypeAssetTypeCreditAlphanum4:
-		a := xdr.AssetAlphaNum4{}
-		a.Issuer = base.GetAccountID(prefix + "asset_issuer")
-
-		c := base.GetString(prefix + "asset_code")
-		if len(c) > len(a.AssetCode) {
-			base.SetInvalidField(prefix+"asset_code", nil)
-			return
-		}
-
-		copy(a.AssetCode[:len(c)], []byte(c))
-		value = a
-	case xdr.AssetTypeAssetTypeCreditAlphanum12:
-		a := xdr.AssetAlphaNum12{}
-		a.Issuer = base.GetAccountID(prefix + "asset_issuer")
-
-		c := base.GetString(prefix + "asset_code")
-		if len(c) > len(a.AssetCode) {
-			base.SetInvalidField(prefix+"asset_code", nil)
+	var code, issuer string
+	if t != xdr.AssetTypeAssetTypeNative {
+		aid := base.GetAccountID(prefix + "asset_issuer")
+		code = base.GetString(prefix + "asset_code")
+		if base.Err != nil {
 			return
 		}
-
-		copy(a.AssetCode[:len(c)], []byte(c))
-		value = a
+		issuer = aid.Address()
 	}
 
-	result, err := xdr.NewAsset(t, value)
+	result, err := xdr.BuildAsset(xdr.AssetTypeToString[t], code, issuer)
 	if err != nil {
-		panic(err)
+		base.SetInvalidField(prefix+"asset_code", err)
 	}
+
 	return
 }
 
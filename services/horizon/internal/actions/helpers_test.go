@@ -51,6 +51,15 @@ func TestGetAsset(t *testing.T) {
 	// bad path
 	action.GetAsset("cursor")
 	tt.Assert.Error(action.Err)
+
+	// code too long for the asset type
+	action = makeAction("/", map[string]string{
+		"asset_type":   "credit_alphanum4",
+		"asset_code":   "SCOTTBUCKS",
+		"asset_issuer": "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+	})
+	action.GetAsset("")
+	tt.Assert.Error(action.Err)
 }
 
 func TestGetAssetType(t *testing.T) {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/resource"
+	"github.com/stellar/go/services/horizon/internal/test"
 )
 
 func TestTradeActions_Index(t *testing.T) {
@@ -69,6 +70,14 @@ func TestTradeActions_Index(t *testing.T) {
 		ht.Assert.Contains(records[0], "counter_amount")
 	}
 
+	// streaming
+	w = ht.Get("/trades?limit=1", test.RequestHelperStreaming)
+	ht.Assert.Equal(200, w.Code)
+
+	// streaming before history
+	ht.ReapHistory(1)
+	w = ht.Get("/trades?order=desc&cursor=8589938689-1", test.RequestHelperStreaming)
+	ht.Assert.Equal(410, w.Code)
 }
 
 func TestTradeActions_IndexRegressions(t *testing.T) {
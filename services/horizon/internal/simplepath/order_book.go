@@ -2,12 +2,12 @@ package simplepath
 
 import (
 	"errors"
-	"math/big"
 
 	sq "github.com/Masterminds/squirrel"
-	"github.com/stellar/go/xdr"
-	"github.com/stellar/go/services/horizon/internal/assets"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/price"
 	"github.com/stellar/go/services/horizon/internal/db2/core"
+	"github.com/stellar/go/xdr"
 )
 
 // ErrNotEnough represents an error that occurs when pricing a trade on an
@@ -55,7 +55,7 @@ func (ob *orderBook) Cost(source xdr.Asset, sourceAmount xdr.Int64) (result xdr.
 			"COALESCE(buyingassetcode, '')": bc,
 			"COALESCE(buyingissuer, '')":    bi})
 
-	inverted := assets.Equals(source, ob.Buying)
+	inverted := source.Key() == ob.Buying.Key()
 
 	if !inverted {
 		sql = sql.OrderBy("price ASC")
@@ -70,16 +70,18 @@ func (ob *orderBook) Cost(source xdr.Asset, sourceAmount xdr.Int64) (result xdr.
 	defer rows.Close()
 
 	var (
-		needed = int64(sourceAmount)
-		cost   int64
+		needed = sourceAmount
+		cost   xdr.Int64
 	)
 
 	for rows.Next() {
 		// load data from the row
-		var available, pricen, priced, offerid int64
+		var (
+			available               xdr.Int64
+			pricen, priced, offerid int64
+		)
 		if inverted {
 			err = rows.Scan(&available, &priced, &pricen, &offerid)
-			available = mul(available, pricen, priced)
 		} else {
 			err = rows.Scan(&available, &pricen, &priced, &offerid)
 		}
@@ -87,29 +89,36 @@ func (ob *orderBook) Cost(source xdr.Asset, sourceAmount xdr.Int64) (result xdr.
 			return
 		}
 
+		p := xdr.Price{N: xdr.Int32(pricen), D: xdr.Int32(priced)}
+		if inverted {
+			available, err = price.Mul(available, p, amount.RoundDown)
+			if err != nil {
+				return
+			}
+		}
+
 		if available >= needed {
-			cost += mul(needed, pricen, priced)
-			result = xdr.Int64(cost)
+			var c xdr.Int64
+			c, err = price.Mul(needed, p, amount.RoundDown)
+			if err != nil {
+				return
+			}
+			result, err = amount.Add(cost, c)
 			return
 		}
 
-		cost += mul(available, pricen, priced)
+		var c xdr.Int64
+		c, err = price.Mul(available, p, amount.RoundDown)
+		if err != nil {
+			return
+		}
+		cost, err = amount.Add(cost, c)
+		if err != nil {
+			return
+		}
 		needed -= available
 	}
 
 	err = ErrNotEnough
 	return
 }
-
-// mul multiplies the input amount by the input price
-func mul(amount int64, pricen int64, priced int64) int64 {
-	var r, n, d big.Int
-
-	r.SetInt64(amount)
-	n.SetInt64(pricen)
-	d.SetInt64(priced)
-
-	r.Mul(&r, &n)
-	r.Quo(&r, &d)
-	return r.Int64()
-}
@@ -26,12 +26,12 @@ func (p *pathNode) String() string {
 	}
 
 	var out bytes.Buffer
-	fmt.Fprintf(&out, "%v", p.Asset)
+	fmt.Fprint(&out, p.Asset.StringCanonical())
 
 	cur := p.Tail
 
 	for cur != nil {
-		fmt.Fprintf(&out, " -> %v", cur.Asset)
+		fmt.Fprintf(&out, " -> %s", cur.Asset.StringCanonical())
 		cur = cur.Tail
 	}
 
@@ -22,8 +22,8 @@ type search struct {
 	// Fields below are initialized by a call to Init() after
 	// setting the fields above
 	queue   []*pathNode
-	targets map[string]bool
-	visited map[string]bool
+	targets map[xdr.AssetKey]bool
+	visited map[xdr.AssetKey]bool
 
 	//This fields below are initialized after the search is run
 	Err     error
@@ -43,15 +43,14 @@ func (s *search) Init() {
 		},
 	}
 
-	// build a map of asset's string representation to check if a given node
-	// is one of the targets for our search.  Unfortunately, xdr.Asset is not suitable
-	// for use as a map key, and so we use its string representation.
-	s.targets = map[string]bool{}
+	// build a set of asset keys to check if a given node is one of the targets
+	// for our search.
+	s.targets = map[xdr.AssetKey]bool{}
 	for _, a := range s.Query.SourceAssets {
-		s.targets[a.String()] = true
+		s.targets[a.Key()] = true
 	}
 
-	s.visited = map[string]bool{}
+	s.visited = map[xdr.AssetKey]bool{}
 	s.Err = nil
 	s.Results = nil
 }
@@ -90,14 +89,14 @@ func (s *search) hasMore() bool {
 
 // isTarget returns true if the asset id provided is one of the targets
 // for this search (i.e. one of the requesting account's trusted assets)
-func (s *search) isTarget(id string) bool {
+func (s *search) isTarget(id xdr.AssetKey) bool {
 	_, found := s.targets[id]
 	return found
 }
 
 // visit returns true if the asset id provided has not been
 // visited on this search, after marking the id as visited
-func (s *search) visit(id string) bool {
+func (s *search) visit(id xdr.AssetKey) bool {
 	if _, found := s.visited[id]; found {
 		return false
 	}
@@ -110,7 +109,7 @@ func (s *search) visit(id string) bool {
 // and extending the search as necessary.
 func (s *search) runOnce() {
 	cur := s.pop()
-	id := cur.Asset.String()
+	id := cur.Asset.Key()
 
 	if s.isTarget(id) {
 		s.Results = append(s.Results, cur)
@@ -0,0 +1,13 @@
+package shamir
+
+import (
+	"fmt"
+)
+
+func (err *InvalidShareError) Error() string {
+	return fmt.Sprintf("invalid share at position %d: %s", err.Position, err.Cause)
+}
+
+func (err *InconsistentShareError) Error() string {
+	return fmt.Sprintf("share %d is inconsistent with the other shares", err.Index)
+}
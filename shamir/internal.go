@@ -0,0 +1,121 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+)
+
+// Arithmetic in GF(2^8), using the same reducing polynomial as AES
+// (x^8 + x^4 + x^3 + x + 1) and 3 as a generator.
+var (
+	gfExp [510]byte
+	gfLog [256]byte
+)
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		gfExp[i] = x
+		gfExp[i+255] = x
+		gfLog[x] = byte(i)
+		x = gfMulSlow(x, 3)
+	}
+}
+
+func gfMulSlow(a, b byte) byte {
+	var p byte
+	for b > 0 {
+		if b&1 != 0 {
+			p ^= a
+		}
+		hi := a & 0x80
+		a <<= 1
+		if hi != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return p
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if b == 0 {
+		panic("division by zero")
+	}
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+// polynomial is a polynomial over GF(2^8), with the constant term first.
+type polynomial []byte
+
+func (p polynomial) eval(x byte) byte {
+	// horner's method
+	var result byte
+	for i := len(p) - 1; i >= 0; i-- {
+		result = gfMul(result, x) ^ p[i]
+	}
+	return result
+}
+
+// randomPolynomials returns one random polynomial of degree threshold-1 for
+// each byte of `secret`, whose constant term is that byte.
+func randomPolynomials(secret []byte, threshold int) ([]polynomial, error) {
+	coefficients := make([]byte, len(secret)*(threshold-1))
+	_, err := io.ReadFull(rand.Reader, coefficients)
+	if err != nil {
+		return nil, err
+	}
+
+	polys := make([]polynomial, len(secret))
+	for i, b := range secret {
+		poly := make(polynomial, threshold)
+		poly[0] = b
+		copy(poly[1:], coefficients[i*(threshold-1):])
+		polys[i] = poly
+	}
+
+	return polys, nil
+}
+
+// interpolate evaluates, at `x`, the polynomial for byte `i` of the secret
+// passing through the points of `shares` using lagrange interpolation.
+func interpolate(shares []Share, i int, x byte) byte {
+	var result byte
+	for j, sj := range shares {
+		xj := byte(sj.Index)
+
+		basis := byte(1)
+		for k, sk := range shares {
+			if k == j {
+				continue
+			}
+			xk := byte(sk.Index)
+			// subtraction is xor in GF(2^8)
+			basis = gfMul(basis, gfDiv(x^xk, xj^xk))
+		}
+
+		result ^= gfMul(sj.value[i], basis)
+	}
+	return result
+}
+
+// keyIDFor returns the identifier recorded in the shares of `kp`'s seed: the
+// last 8 bytes of its public key.
+func keyIDFor(kp keypair.KP) (id [8]byte) {
+	raw := strkey.MustDecode(strkey.VersionByteAccountID, kp.Address())
+	copy(id[:], raw[len(raw)-8:])
+	return
+}
@@ -0,0 +1,142 @@
+// Package shamir splits the seed of a keypair into shares using Shamir's
+// secret sharing scheme, so that any `threshold` of the shares can recover the
+// seed but fewer reveal nothing about it.
+//
+// Each share is encoded like a strkey, using strkey.VersionByteSeedShare
+// (shares begin with a 'K') and the usual crc16 checksum, so that mistyped
+// shares are rejected.  A share also records the threshold, its own index and
+// an identifier derived from the public key of the split seed.  The identifier
+// keeps shares of different seeds from being mixed and lets Combine confirm
+// that the recovered seed is the one originally split.
+package shamir
+
+import (
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+)
+
+// MaxShares is the largest number of shares a seed may be split into.
+const MaxShares = 255
+
+var (
+	// ErrNotEnoughShares is returned by Combine when fewer shares than the
+	// threshold they were created with are provided.
+	ErrNotEnoughShares = errors.New("not enough shares")
+
+	// ErrMismatchedShares is returned by Combine when the provided shares were
+	// not all created by the same call to Split.
+	ErrMismatchedShares = errors.New("shares belong to different seeds")
+
+	// ErrDuplicateShare is returned by Combine when the same share is
+	// provided more than once.
+	ErrDuplicateShare = errors.New("duplicate share")
+
+	// ErrCorruptShares is returned by Combine when the shares decode correctly
+	// but do not recombine into the seed that was split, which means at least
+	// one of them has been altered.
+	ErrCorruptShares = errors.New("shares do not recombine into the original seed")
+
+	// ErrInconsistentShares is returned by Combine when more shares than the
+	// threshold are provided and they disagree, but the corrupt share cannot
+	// be identified: either a single extra share was provided, or several
+	// shares are corrupt.
+	ErrInconsistentShares = errors.New("shares are inconsistent with each other")
+)
+
+// Share is a single decoded share of a seed.
+type Share struct {
+	// Threshold is the number of shares needed to recover the seed.
+	Threshold int
+
+	// Index identifies the share, from 1 to the number of shares created.
+	Index int
+
+	// KeyID identifies the keypair whose seed was split.
+	KeyID [8]byte
+
+	value [32]byte
+}
+
+// InvalidShareError is returned by Combine when one of the shares cannot be
+// decoded.
+type InvalidShareError struct {
+	// Position is the position of the share within the shares passed to
+	// Combine.
+	Position int
+	Cause    error
+}
+
+// InconsistentShareError is returned by Combine when at least two more shares
+// than needed are provided and a single one of them disagrees with the
+// others, identifying the corrupt share.
+type InconsistentShareError struct {
+	// Index is the index of the share that disagrees.
+	Index int
+}
+
+// Split splits the seed of `kp` into `shares` encoded shares, any `threshold`
+// of which recover the seed.
+func Split(kp *keypair.Full, threshold, shares int) ([]string, error) {
+	if threshold < 2 || threshold > shares || shares > MaxShares {
+		return nil, errors.Errorf(
+			"invalid threshold %d of %d shares: must satisfy 2 <= threshold <= shares <= %d",
+			threshold, shares, MaxShares,
+		)
+	}
+
+	seed, err := strkey.Decode(strkey.VersionByteSeed, kp.Seed())
+	if err != nil {
+		return nil, errors.Wrap(err, "decode seed failed")
+	}
+
+	polys, err := randomPolynomials(seed, threshold)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate polynomials failed")
+	}
+
+	keyID := keyIDFor(kp)
+
+	result := make([]string, shares)
+	for i := range result {
+		share := Share{
+			Threshold: threshold,
+			Index:     i + 1,
+			KeyID:     keyID,
+		}
+
+		for j, poly := range polys {
+			share.value[j] = poly.eval(byte(share.Index))
+		}
+
+		result[i], err = share.String()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Combine recovers the seed split into `shares`.  At least as many shares as
+// the threshold given to Split must be provided.  When more are provided,
+// every share is checked against the others.  Inconsistent shares are
+// reported using ErrInconsistentShares, or using an *InconsistentShareError
+// identifying the corrupt share when there are at least two extra shares and
+// only one of them is corrupt.
+func Combine(shares []string) (*keypair.Full, error) {
+	if len(shares) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+
+	decoded := make([]Share, len(shares))
+	for i, s := range shares {
+		share, err := ParseShare(s)
+		if err != nil {
+			return nil, &InvalidShareError{Position: i, Cause: err}
+		}
+		decoded[i] = *share
+	}
+
+	return CombineShares(decoded)
+}
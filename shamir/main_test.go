@@ -0,0 +1,136 @@
+package shamir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const seed = "SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"
+
+func split(t *testing.T, seed string, threshold, n int) []string {
+	kp := keypair.MustParse(seed).(*keypair.Full)
+	shares, err := Split(kp, threshold, n)
+	require.NoError(t, err)
+	require.Len(t, shares, n)
+	return shares
+}
+
+func TestSplitCombine(t *testing.T) {
+	shares := split(t, seed, 3, 5)
+
+	for _, share := range shares {
+		assert.True(t, strings.HasPrefix(share, "K"), share)
+		assert.NotContains(t, share, "=")
+	}
+
+	// every combination of three shares recovers the seed
+	for i := 0; i < len(shares); i++ {
+		for j := i + 1; j < len(shares); j++ {
+			for k := j + 1; k < len(shares); k++ {
+				kp, err := Combine([]string{shares[k], shares[i], shares[j]})
+				require.NoError(t, err)
+				assert.Equal(t, seed, kp.Seed())
+			}
+		}
+	}
+
+	// all shares may be provided too
+	kp, err := Combine(shares)
+	require.NoError(t, err)
+	assert.Equal(t, seed, kp.Seed())
+
+	_, err = Combine(shares[:2])
+	assert.Equal(t, ErrNotEnoughShares, err)
+}
+
+func TestSplit_InvalidThreshold(t *testing.T) {
+	kp := keypair.MustParse(seed).(*keypair.Full)
+
+	_, err := Split(kp, 1, 3)
+	assert.Error(t, err)
+	_, err = Split(kp, 4, 3)
+	assert.Error(t, err)
+	_, err = Split(kp, 2, MaxShares+1)
+	assert.Error(t, err)
+}
+
+func TestCombine_Errors(t *testing.T) {
+	shares := split(t, seed, 2, 3)
+	other := split(t, "SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY", 2, 3)
+
+	// a typo is caught by the checksum
+	typo := []byte(shares[1])
+	if typo[20] == 'A' {
+		typo[20] = 'B'
+	} else {
+		typo[20] = 'A'
+	}
+	_, err := Combine([]string{shares[0], string(typo)})
+	if assert.IsType(t, &InvalidShareError{}, err) {
+		assert.Equal(t, 1, err.(*InvalidShareError).Position)
+	}
+
+	_, err = Combine([]string{shares[0], other[1]})
+	assert.Equal(t, ErrMismatchedShares, err)
+
+	_, err = Combine([]string{shares[0], shares[0]})
+	assert.Equal(t, ErrDuplicateShare, err)
+
+	// a share altered and re-encoded with a valid checksum
+	corrupt, err := ParseShare(shares[2])
+	require.NoError(t, err)
+	corrupt.value[5] ^= 0x01
+	encoded, err := corrupt.String()
+	require.NoError(t, err)
+
+	_, err = Combine([]string{shares[0], encoded})
+	assert.Equal(t, ErrCorruptShares, err)
+
+	// with a single extra share, the corrupt one cannot be identified
+	_, err = Combine([]string{shares[0], shares[1], encoded})
+	assert.Equal(t, ErrInconsistentShares, err)
+}
+
+func TestCombine_IdentifiesCorruptShare(t *testing.T) {
+	shares := split(t, seed, 3, 6)
+
+	corrupt := func(s string) string {
+		share, err := ParseShare(s)
+		require.NoError(t, err)
+		share.value[7] ^= 0x40
+		encoded, err := share.String()
+		require.NoError(t, err)
+		return encoded
+	}
+
+	// the corrupt share is among the first threshold shares, which are used
+	// to check the others
+	_, err := Combine([]string{corrupt(shares[0]), shares[1], shares[2], shares[3], shares[4]})
+	assert.Equal(t, &InconsistentShareError{Index: 1}, err)
+
+	_, err = Combine([]string{shares[5], shares[1], shares[2], corrupt(shares[3]), shares[4]})
+	assert.Equal(t, &InconsistentShareError{Index: 4}, err)
+
+	// several corrupt shares are reported as inconsistent
+	_, err = Combine([]string{corrupt(shares[0]), corrupt(shares[1]), shares[2], shares[3], shares[4]})
+	assert.Equal(t, ErrInconsistentShares, err)
+
+	kp, err := Combine(shares)
+	require.NoError(t, err)
+	assert.Equal(t, seed, kp.Seed())
+}
+
+func TestGF256(t *testing.T) {
+	for a := 0; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			assert.Equal(t, gfMulSlow(byte(a), byte(b)), gfMul(byte(a), byte(b)))
+			if gfMul(gfDiv(byte(a), byte(b)), byte(b)) != byte(a) {
+				t.Fatalf("%d / %d * %d != %d", a, b, b, a)
+			}
+		}
+	}
+}
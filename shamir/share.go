@@ -0,0 +1,141 @@
+package shamir
+
+import (
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+)
+
+// sharePayloadLength is the length of a decoded share: its threshold, index,
+// key id and value.  Together with the version byte and checksum it makes 45
+// bytes, which base32 encodes without padding.
+const sharePayloadLength = 1 + 1 + 8 + 32
+
+// ParseShare decodes the encoded share `s`.
+func ParseShare(s string) (*Share, error) {
+	raw, err := strkey.Decode(strkey.VersionByteSeedShare, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) != sharePayloadLength {
+		return nil, errors.Errorf("invalid share length %d", len(raw))
+	}
+
+	share := &Share{
+		Threshold: int(raw[0]),
+		Index:     int(raw[1]),
+	}
+	copy(share.KeyID[:], raw[2:10])
+	copy(share.value[:], raw[10:])
+
+	if share.Threshold < 2 || share.Index < 1 {
+		return nil, errors.New("invalid share threshold or index")
+	}
+
+	return share, nil
+}
+
+// String returns the encoded form of the share.
+func (share Share) String() (string, error) {
+	raw := make([]byte, 0, sharePayloadLength)
+	raw = append(raw, byte(share.Threshold), byte(share.Index))
+	raw = append(raw, share.KeyID[:]...)
+	raw = append(raw, share.value[:]...)
+
+	return strkey.Encode(strkey.VersionByteSeedShare, raw)
+}
+
+// CombineShares recovers the seed split into the decoded `shares`, as Combine
+// does.
+func CombineShares(shares []Share) (*keypair.Full, error) {
+	if len(shares) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+
+	first := shares[0]
+	seen := map[int]bool{}
+	for _, share := range shares {
+		if share.Threshold != first.Threshold || share.KeyID != first.KeyID {
+			return nil, ErrMismatchedShares
+		}
+
+		if seen[share.Index] {
+			return nil, ErrDuplicateShare
+		}
+		seen[share.Index] = true
+	}
+
+	if len(shares) < first.Threshold {
+		return nil, ErrNotEnoughShares
+	}
+
+	if !consistent(shares) {
+		return nil, inconsistency(shares)
+	}
+
+	basis := shares[:first.Threshold]
+	var seed [32]byte
+	for i := range seed {
+		seed[i] = interpolate(basis, i, 0)
+	}
+
+	kp, err := keypair.FromRawSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyIDFor(kp) != first.KeyID {
+		return nil, ErrCorruptShares
+	}
+
+	return kp, nil
+}
+
+// consistent returns true if every share of `shares` lies on the polynomials
+// defined by the first threshold shares.
+func consistent(shares []Share) bool {
+	// any threshold shares define the polynomials; check the rest against them
+	basis := shares[:shares[0].Threshold]
+	for _, share := range shares[len(basis):] {
+		for i := range share.value {
+			if interpolate(basis, i, byte(share.Index)) != share.value[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// inconsistency returns the error describing the inconsistent `shares`.  The
+// corrupt share is identified when it is the only share whose removal leaves
+// the others consistent, which takes at least two more shares than the
+// threshold: with a single extra share, removing any of them leaves exactly
+// threshold shares, which are always consistent.
+func inconsistency(shares []Share) error {
+	if len(shares) < shares[0].Threshold+2 {
+		return ErrInconsistentShares
+	}
+
+	corrupt := -1
+	rest := make([]Share, 0, len(shares)-1)
+	for i := range shares {
+		rest = append(rest[:0], shares[:i]...)
+		rest = append(rest, shares[i+1:]...)
+		if !consistent(rest) {
+			continue
+		}
+
+		if corrupt != -1 {
+			return ErrInconsistentShares
+		}
+		corrupt = i
+	}
+
+	// several shares are corrupt
+	if corrupt == -1 {
+		return ErrInconsistentShares
+	}
+
+	return &InconsistentShareError{Index: shares[corrupt].Index}
+}
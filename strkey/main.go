@@ -31,6 +31,10 @@ const (
 	//VersionByteHashX is the version byte used for encoded stellar hashX
 	//signer keys.
 	VersionByteHashX = 23 << 3 // Base32-encodes to 'X...'
+
+	//VersionByteSeedShare is the version byte used for encoded shares of a
+	//stellar seed, as produced by the shamir package.
+	VersionByteSeedShare = 10 << 3 // Base32-encodes to 'K...'
 )
 
 // Decode decodes the provided StrKey into a raw value, checking the checksum
@@ -143,6 +147,10 @@ func checkValidVersionByte(version VersionByte) error {
 		return nil
 	}
 
+	if version == VersionByteSeedShare {
+		return nil
+	}
+
 	return ErrInvalidVersionByte
 }
 
@@ -28,6 +28,28 @@ func Read(path string, dest interface{}) error {
 	return decode(string(bs), dest)
 }
 
+// Decode parses the TOML document `content` into `dest` and validates the
+// result, in the same manner as `Read`.
+func Decode(content string, dest interface{}) error {
+	return decode(content, dest)
+}
+
+// Validate uses github.com/asaskevich/govalidator to validate the struct
+// `dest`, returning an *InvalidConfigError describing each invalid field.
+func Validate(dest interface{}) error {
+	valid, err := govalidator.ValidateStruct(dest)
+
+	if valid {
+		return nil
+	}
+
+	fields := govalidator.ErrorsByField(err)
+
+	return &InvalidConfigError{
+		InvalidFields: fields,
+	}
+}
+
 func decode(content string, dest interface{}) error {
 	metadata, err := toml.Decode(content, dest)
 	if err != nil {
@@ -41,17 +63,7 @@ func decode(content string, dest interface{}) error {
 		return errors.New("Unknown fields: " + fmt.Sprintf("%+v", undecoded))
 	}
 
-	valid, err := govalidator.ValidateStruct(dest)
-
-	if valid {
-		return nil
-	}
-
-	fields := govalidator.ErrorsByField(err)
-
-	return &InvalidConfigError{
-		InvalidFields: fields,
-	}
+	return Validate(dest)
 }
 
 func init() {
@@ -122,3 +122,25 @@ private-key-file="world"`
 	err := decode(toml, &val)
 	require.NoError(t, err)
 }
+
+func TestValidate(t *testing.T) {
+	var val struct {
+		Account string `valid:"stellar_accountid"`
+		Seed    string `valid:"stellar_seed"`
+	}
+
+	val.Account = "GBXS6WTZNRS7LOGHM3SCMAJD6M6JCXB3GATXECCZ3C5NJ3PVSZ23PEWX"
+	val.Seed = "GBXS6WTZNRS7LOGHM3SCMAJD6M6JCXB3GATXECCZ3C5NJ3PVSZ23PEWX"
+
+	err := Validate(&val)
+	require.Error(t, err)
+	require.IsType(t, &InvalidConfigError{}, err)
+
+	fields := err.(*InvalidConfigError).InvalidFields
+	assert.Len(t, fields, 1)
+	_, ok := fields["Seed"]
+	assert.True(t, ok, "Seed is not an invalid field")
+
+	val.Seed = "SA5MATAU4RNJDKCTIC6VVSYSGB7MFFBVU3OKWOA5K67S62EYB5ESKLTV"
+	assert.NoError(t, Validate(&val))
+}
@@ -5,6 +5,7 @@
 package archivist
 
 import (
+	"bytes"
 	"compress/gzip"
 	"encoding/json"
 	"fmt"
@@ -65,11 +66,16 @@ func DumpXdrAsJson(args []string) error {
 				}
 			}
 			n++
-			buf, err := json.MarshalIndent(tmp, "", "    ")
+			raw, err := xdr.MarshalJSON(tmp)
 			if err != nil {
 				return err
 			}
-			os.Stdout.Write(buf)
+			var buf bytes.Buffer
+			err = json.Indent(&buf, raw, "", "    ")
+			if err != nil {
+				return err
+			}
+			buf.WriteTo(os.Stdout)
 		}
 		xr.Close()
 	}
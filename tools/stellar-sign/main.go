@@ -10,17 +10,25 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"strings"
 
 	"github.com/howeyc/gopass"
 	"github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txauth"
+	"github.com/stellar/go/txrep"
 	"github.com/stellar/go/xdr"
 )
 
 var in *bufio.Reader
 
 var infile = flag.String("infile", "", "transaction envelope")
+var horizonURL = flag.String("horizon", "", "horizon server used to check whether the signatures are sufficient")
+var keystore = flag.String("keystore", "", "encrypted keystore to sign with, instead of prompting for a seed")
 
 func main() {
 	flag.Parse()
@@ -53,11 +61,11 @@ func main() {
 	}
 
 	// parse the envelope
-	var txe xdr.TransactionEnvelope
-	err = xdr.SafeUnmarshalBase64(env, &txe)
-	if err != nil {
-		log.Fatal(err)
+	b := build.TransactionEnvelopeFromBase64(env, build.PublicNetwork)
+	if b.Err != nil {
+		log.Fatal(b.Err)
 	}
+	txe := b.E
 
 	fmt.Println("")
 	fmt.Println("Transaction Summary:")
@@ -66,19 +74,24 @@ func main() {
 	fmt.Printf("  sigs: %d\n", len(txe.Signatures))
 	fmt.Println("")
 
-	// TODO: add operation details
+	details, err := txrep.Marshal(*txe)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// read seed
-	seed, err := readLine("Enter seed: ", true)
+	fmt.Println("Transaction Details:")
+	fmt.Print("```\n")
+	fmt.Print(details)
+	fmt.Print("```\n")
+	fmt.Println("")
+
+	kp, err := readKeypair()
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// sign the transaction
-	b := &build.TransactionEnvelopeBuilder{E: &txe}
-	b.Init()
-	b.MutateTX(build.PublicNetwork)
-	b.Mutate(build.Sign{seed})
+	b.Mutate(build.SignWith{kp})
 	if b.Err != nil {
 		log.Fatal(b.Err)
 	}
@@ -93,6 +106,88 @@ func main() {
 	fmt.Println(newEnv)
 	fmt.Print("```\n")
 
+	if *horizonURL != "" {
+		err = printAuthorization(*b.E)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+}
+
+// readKeypair prompts for the seed to sign with or, when the -keystore flag is
+// set, for the password that unlocks the keystore.
+func readKeypair() (keypair.KP, error) {
+	if *keystore != "" {
+		password, err := readLine("Enter keystore password: ", true)
+		if err != nil {
+			return nil, err
+		}
+		return keypair.LoadKeystore(*keystore, []byte(password))
+	}
+
+	seed, err := readLine("Enter seed: ", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return keypair.Parse(seed)
+}
+
+// printAuthorization loads the source accounts of txe from horizon and reports
+// whether the envelope's signatures are sufficient to submit it.
+func printAuthorization(txe xdr.TransactionEnvelope) error {
+	client := &horizon.Client{URL: *horizonURL, HTTP: http.DefaultClient}
+
+	addresses := []string{txe.Tx.SourceAccount.Address()}
+	for _, op := range txe.Tx.Operations {
+		if op.SourceAccount != nil {
+			addresses = append(addresses, op.SourceAccount.Address())
+		}
+	}
+
+	var accounts []txauth.Account
+	for _, address := range addresses {
+		a, err := client.LoadAccount(address)
+		if err != nil {
+			return err
+		}
+		accounts = append(accounts, txauth.AccountFromHorizon(a))
+	}
+
+	result, err := txauth.Check(txe, network.PublicNetworkPassphrase, accounts...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("\n==== Authorization ====\n\n")
+	printRequirement("tx", result.Transaction)
+	for i, op := range result.Operations {
+		printRequirement(fmt.Sprintf("op %d", i), op)
+	}
+
+	if len(result.Extra) > 0 {
+		fmt.Printf("  extra signatures: %v\n", result.Extra)
+	}
+
+	if result.Sufficient() {
+		fmt.Println("  no more signatures are needed")
+	} else {
+		fmt.Println("  more signatures are needed")
+	}
+	return nil
+}
+
+func printRequirement(name string, req txauth.Requirement) {
+	status := "needs more signatures"
+	if req.Met {
+		status = "ok"
+	}
+
+	fmt.Printf(
+		"  %s: %s (weight %d of %d from %s)\n",
+		name, status, req.Weight, req.Needed, req.Account,
+	)
 }
 
 func readLine(prompt string, private bool) (string, error) {
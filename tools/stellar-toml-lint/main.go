@@ -0,0 +1,79 @@
+// stellar-toml-lint checks stellar.toml files against SEP-1, reporting missing
+// required fields, malformed account ids and urls, and oversized files.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/stellar/go/clients/stellartoml"
+)
+
+var useHTTP = flag.Bool("http", false, "load served files using plain http rather than https")
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(1)
+	}
+
+	client := &stellartoml.Client{HTTP: http.DefaultClient, UseHTTP: *useHTTP}
+
+	failed := false
+	for _, arg := range flag.Args() {
+		problems, err := lint(client, arg)
+		if err != nil {
+			fmt.Printf("%s: %s\n", arg, err)
+			failed = true
+			continue
+		}
+
+		if len(problems) == 0 {
+			fmt.Printf("%s: ok\n", arg)
+			continue
+		}
+
+		failed = true
+		for _, p := range problems {
+			fmt.Printf("%s: %s\n", arg, p)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// lint lints the local file at `arg` if it exists, or the file served for the
+// domain `arg` otherwise.
+func lint(client *stellartoml.Client, arg string) ([]stellartoml.Problem, error) {
+	if _, err := os.Stat(arg); err != nil {
+		return client.LintStellarToml(arg)
+	}
+
+	data, err := ioutil.ReadFile(arg)
+	if err != nil {
+		return nil, err
+	}
+	return stellartoml.Lint(data), nil
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+	stellar-toml-lint [flags] FILE|DOMAIN...
+
+Each argument is either the path of a local stellar.toml file or a domain, in
+which case the file served at https://DOMAIN/.well-known/stellar.toml is
+checked.  Every problem found is printed, and the exit status is 1 when any
+file has problems.
+
+Flags:
+`)
+	flag.PrintDefaults()
+}
@@ -1,58 +1,179 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
-	"strings"
+	"runtime"
+	"time"
 
+	"github.com/howeyc/gopass"
 	"github.com/stellar/go/keypair"
 )
 
-var prefix string
-
-const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+var (
+	workers     = flag.Int("workers", runtime.NumCPU(), "number of goroutines generating keypairs")
+	maxAttempts = flag.Uint64("max-attempts", 0, "give up after trying this many keypairs (0 means never give up)")
+	out         = flag.String("out", "", "save the keypair found to this encrypted keystore file, instead of printing its seed")
+	quiet       = flag.Bool("quiet", false, "do not display progress")
+)
 
 func main() {
+	flag.Usage = usage
+	flag.Parse()
 
-	if len(os.Args) != 2 {
+	if flag.NArg() == 0 || *workers < 1 {
 		usage()
 		os.Exit(1)
 	}
 
-	prefix = strings.ToUpper(os.Args[1])
-	checkPlausible()
+	var ps patterns
+	for _, arg := range flag.Args() {
+		p, err := parsePattern(arg)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		ps = append(ps, p)
+	}
 
-	for {
-		kp, err := keypair.Random()
+	// ask for the password up front, so nobody needs to wait for the search
+	var password []byte
+	if *out != "" {
+		if _, err := os.Stat(*out); err == nil {
+			log.Fatalf("%s already exists", *out)
+		}
 
+		var err error
+		password, err = readPassword()
 		if err != nil {
 			log.Fatal(err)
 		}
+	}
 
-		// NOTE: the first letter of an address will always be G, and the second letter will be one of only a few
-		// possibilities in the base32 alphabet, so we are actually searching for the vanity value after this 2
-		// character prefix.
-		if strings.HasPrefix(kp.Address()[2:], prefix) {
-			fmt.Println("Found!")
-			fmt.Printf("Secret seed: %s\n", kp.Seed())
-			fmt.Printf("Public: %s\n", kp.Address())
-			os.Exit(0)
-		}
+	expected := 1 / ps.Probability()
+	fmt.Printf("Searching for %v using %d workers\n", ps, *workers)
+	fmt.Printf("Difficulty: 1 in %.0f addresses\n", expected)
+
+	s := &search{
+		Patterns:    ps,
+		Workers:     *workers,
+		MaxAttempts: *maxAttempts,
+	}
+
+	stop := make(chan struct{})
+	if !*quiet {
+		go showProgress(s, expected, stop)
+	}
+
+	kp, err := s.Run()
+	close(stop)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if kp == nil {
+		fmt.Printf("\nNo match found after %d attempts\n", s.Attempts())
+		os.Exit(2)
+	}
+
+	fmt.Printf("\nFound after %d attempts!\n", s.Attempts())
+	fmt.Printf("Public: %s\n", kp.Address())
+
+	if *out == "" {
+		fmt.Printf("Secret seed: %s\n", kp.Seed())
+		return
+	}
+
+	err = keypair.SaveKeystore(*out, kp, password)
+	if err != nil {
+		log.Fatal(err)
 	}
+	fmt.Printf("Secret seed saved to %s\n", *out)
 }
 
 func usage() {
-	fmt.Printf("Usage:\n\tstellar-vanity-gen PREFIX\n")
+	fmt.Fprintf(os.Stderr, `Usage:
+	stellar-vanity-gen [flags] PATTERN...
+
+Patterns are matched against the address after its first two characters, which
+are always G followed by one of A, B, C or D.  Write PATTERN as:
+
+	ABC     to find an address beginning with ABC
+	*ABC    to find an address ending with ABC
+	*ABC*   to find an address containing ABC
+
+When several patterns are provided, the first address matching any of them is
+returned.
+
+Flags:
+`)
+	flag.PrintDefaults()
 }
 
-// aborts the attempt if a desired character is not a valid base32 digit
-func checkPlausible() {
-	for _, r := range prefix {
-		if !strings.ContainsRune(alphabet, r) {
-			fmt.Printf("Invalid prefix: %s is not in the base32 alphabet\n", strconv.QuoteRune(r))
-			os.Exit(1)
+// showProgress periodically prints the number of attempts made by `s`, the
+// rate at which they are made and an estimate of the time remaining.  As every
+// attempt is independent, `expected` attempts remain however many were made.
+func showProgress(s *search, expected float64, stop chan struct{}) {
+	start := time.Now()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		attempts := float64(s.Attempts())
+		rate := attempts / time.Since(start).Seconds()
+
+		eta := "unknown"
+		if rate > 0 {
+			eta = formatETA(expected / rate)
 		}
+
+		fmt.Fprintf(os.Stderr,
+			"\r%.0f attempts (%.0f/s), ETA %s          ",
+			attempts, rate, eta,
+		)
+	}
+}
+
+func formatETA(seconds float64) string {
+	const year = 365 * 24 * time.Hour
+
+	if seconds > (100 * year).Seconds() {
+		return "more than 100 years"
+	}
+
+	return "~" + (time.Duration(seconds) * time.Second).String()
+}
+
+func readPassword() ([]byte, error) {
+	fmt.Print("Keystore password: ")
+	password, err := gopass.GetPasswdMasked()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Print("Repeat password: ")
+	confirm, err := gopass.GetPasswdMasked()
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(password, confirm) {
+		return nil, errors.New("passwords do not match")
 	}
+
+	if len(password) == 0 {
+		return nil, errors.New("password must not be empty")
+	}
+
+	return password, nil
 }
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+
+// addressLength is the length of an encoded stellar address.
+const addressLength = 56
+
+// searchOffset is the number of leading address characters that are never
+// matched.  The first letter of an address will always be G, and the second
+// letter will be one of only a few possibilities in the base32 alphabet, so we
+// actually search for vanity values after this 2 character prefix.
+const searchOffset = 2
+
+type matchKind int
+
+const (
+	matchPrefix matchKind = iota
+	matchSuffix
+	matchContains
+)
+
+// pattern is a single vanity pattern.  Patterns are written as `ABC` or `ABC*`
+// to match a prefix, `*ABC` to match a suffix and `*ABC*` to match anywhere in
+// the address.
+type pattern struct {
+	Kind  matchKind
+	Value string
+}
+
+func parsePattern(raw string) (pattern, error) {
+	value := strings.ToUpper(raw)
+	kind := matchPrefix
+
+	switch {
+	case strings.HasPrefix(value, "*") && strings.HasSuffix(value, "*") && len(value) > 1:
+		kind = matchContains
+		value = value[1 : len(value)-1]
+	case strings.HasPrefix(value, "*"):
+		kind = matchSuffix
+		value = value[1:]
+	case strings.HasSuffix(value, "*"):
+		value = value[:len(value)-1]
+	}
+
+	if value == "" {
+		return pattern{}, fmt.Errorf("invalid pattern %q: empty", raw)
+	}
+
+	if len(value) > addressLength-searchOffset {
+		return pattern{}, fmt.Errorf("invalid pattern %q: too long", raw)
+	}
+
+	for _, r := range value {
+		if !strings.ContainsRune(alphabet, r) {
+			return pattern{}, fmt.Errorf(
+				"invalid pattern %q: %s is not in the base32 alphabet",
+				raw, strconv.QuoteRune(r),
+			)
+		}
+	}
+
+	return pattern{Kind: kind, Value: value}, nil
+}
+
+func (p pattern) Match(address string) bool {
+	switch p.Kind {
+	case matchSuffix:
+		return strings.HasSuffix(address, p.Value)
+	case matchContains:
+		return strings.Contains(address[searchOffset:], p.Value)
+	default:
+		return strings.HasPrefix(address[searchOffset:], p.Value)
+	}
+}
+
+// Probability returns the (approximate) probability that a random address
+// matches the pattern.
+func (p pattern) Probability() float64 {
+	single := math.Pow(float64(len(alphabet)), -float64(len(p.Value)))
+
+	if p.Kind == matchContains {
+		positions := addressLength - searchOffset - len(p.Value) + 1
+		return math.Min(1, single*float64(positions))
+	}
+
+	return single
+}
+
+func (p pattern) String() string {
+	switch p.Kind {
+	case matchSuffix:
+		return "*" + p.Value
+	case matchContains:
+		return "*" + p.Value + "*"
+	default:
+		return p.Value
+	}
+}
+
+// patterns is a set of patterns, any of which may match.
+type patterns []pattern
+
+func (ps patterns) Match(address string) bool {
+	for _, p := range ps {
+		if p.Match(address) {
+			return true
+		}
+	}
+	return false
+}
+
+// Probability returns the (approximate) probability that a random address
+// matches any of the patterns.
+func (ps patterns) Probability() float64 {
+	miss := 1.0
+	for _, p := range ps {
+		miss *= 1 - p.Probability()
+	}
+	return 1 - miss
+}
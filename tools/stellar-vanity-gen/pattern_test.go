@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const address = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func TestParsePattern(t *testing.T) {
+	cases := []struct {
+		Raw   string
+		Kind  matchKind
+		Value string
+	}{
+		{"rpy", matchPrefix, "RPY"},
+		{"RPY*", matchPrefix, "RPY"},
+		{"*OX2H", matchSuffix, "OX2H"},
+		{"*NUCE*", matchContains, "NUCE"},
+	}
+
+	for _, kase := range cases {
+		p, err := parsePattern(kase.Raw)
+		require.NoError(t, err, kase.Raw)
+		assert.Equal(t, pattern{Kind: kase.Kind, Value: kase.Value}, p, kase.Raw)
+		assert.True(t, p.Match(address), kase.Raw)
+	}
+
+	for _, raw := range []string{"", "*", "**", "RP1", "R-P"} {
+		_, err := parsePattern(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	prefix, _ := parsePattern("ABC")
+	suffix, _ := parsePattern("*OX2H")
+	contains, _ := parsePattern("*AB*")
+
+	assert.InDelta(t, 1.0/32768, prefix.Probability(), 1e-12)
+	assert.InDelta(t, 53.0/1024, contains.Probability(), 1e-12)
+
+	ps := patterns{prefix, suffix}
+	assert.True(t, ps.Match(address))
+	assert.False(t, patterns{prefix}.Match(address))
+	assert.True(t, ps.Probability() > prefix.Probability())
+}
+
+func TestSearch(t *testing.T) {
+	p, _ := parsePattern("*A*")
+
+	s := &search{Patterns: patterns{p}, Workers: 4}
+	kp, err := s.Run()
+	require.NoError(t, err)
+	require.NotNil(t, kp)
+	assert.Contains(t, kp.Address()[searchOffset:], "A")
+
+	// a pattern that practically never matches gives up at the limit
+	p, _ = parsePattern("ZZZZZZZZZZZZ")
+	s = &search{Patterns: patterns{p}, Workers: 2, MaxAttempts: 500}
+	kp, err = s.Run()
+	require.NoError(t, err)
+	assert.Nil(t, kp)
+	assert.True(t, s.Attempts() >= 500)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/stellar/go/keypair"
+)
+
+// counterBatch is the number of attempts a worker reserves at once, and makes
+// between updates of the shared attempt counter.
+const counterBatch = 64
+
+// search generates random keypairs using `workers` goroutines until one
+// matches `ps`, or until `maxAttempts` keypairs have been tried when it is not
+// zero.  It returns nil if no match was found.  The number of attempts made so
+// far is kept in `attempts`, which may be read concurrently.
+type search struct {
+	Patterns    patterns
+	Workers     int
+	MaxAttempts uint64
+
+	attempts uint64
+	reserved uint64
+	done     chan struct{}
+	once     sync.Once
+	result   *keypair.Full
+	err      error
+}
+
+func (s *search) Run() (*keypair.Full, error) {
+	s.done = make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work()
+		}()
+	}
+	wg.Wait()
+
+	return s.result, s.err
+}
+
+// Attempts returns the number of keypairs tried so far.
+func (s *search) Attempts() uint64 {
+	return atomic.LoadUint64(&s.attempts)
+}
+
+func (s *search) work() {
+	for {
+		n, ok := s.reserve()
+		if !ok {
+			return
+		}
+
+		for i := uint64(0); i < n; i++ {
+			select {
+			case <-s.done:
+				atomic.AddUint64(&s.attempts, i)
+				return
+			default:
+			}
+
+			kp, err := keypair.Random()
+			if err != nil {
+				s.finish(nil, err)
+				return
+			}
+
+			if s.Patterns.Match(kp.Address()) {
+				atomic.AddUint64(&s.attempts, i+1)
+				s.finish(kp, nil)
+				return
+			}
+		}
+
+		atomic.AddUint64(&s.attempts, n)
+	}
+}
+
+// reserve reserves the next batch of attempts, returning its size, or false
+// once MaxAttempts attempts have been reserved.  Workers stop without
+// finishing the search once out of attempts, so that the batches of the
+// others are still tried.
+func (s *search) reserve() (uint64, bool) {
+	if s.MaxAttempts == 0 {
+		return counterBatch, true
+	}
+
+	end := atomic.AddUint64(&s.reserved, counterBatch)
+	start := end - counterBatch
+	if start >= s.MaxAttempts {
+		return 0, false
+	}
+	if end > s.MaxAttempts {
+		end = s.MaxAttempts
+	}
+	return end - start, true
+}
+
+// finish records the outcome of the search and stops every worker.  Only the
+// first outcome is kept.
+func (s *search) finish(kp *keypair.Full, err error) {
+	s.once.Do(func() {
+		s.result = kp
+		s.err = err
+		close(s.done)
+	})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch_MaxAttempts(t *testing.T) {
+	// no address contains a character outside of the base32 alphabet
+	ps := patterns{{Kind: matchContains, Value: "1"}}
+
+	for _, max := range []uint64{1, 10, counterBatch + 1} {
+		s := &search{Patterns: ps, Workers: 8, MaxAttempts: max}
+		kp, err := s.Run()
+		require.NoError(t, err)
+		assert.Nil(t, kp)
+		assert.Equal(t, max, s.Attempts())
+	}
+}
+
+func TestSearch_Match(t *testing.T) {
+	ps := patterns{{Kind: matchPrefix, Value: "A"}}
+
+	s := &search{Patterns: ps, Workers: 4}
+	kp, err := s.Run()
+	require.NoError(t, err)
+	require.NotNil(t, kp)
+	assert.True(t, ps.Match(kp.Address()))
+	assert.NotZero(t, s.Attempts())
+}
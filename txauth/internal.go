@@ -0,0 +1,240 @@
+package txauth
+
+import (
+	"bytes"
+
+	"github.com/stellar/go/hash"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// checker tracks the state shared by the individual requirement checks of a
+// single envelope, most importantly which signatures have been used so far.
+type checker struct {
+	txe  xdr.TransactionEnvelope
+	hash [32]byte
+	used []bool
+}
+
+func newChecker(txe xdr.TransactionEnvelope, passphrase string) (*checker, error) {
+	txHash, err := network.HashTransaction(&txe.Tx, passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "hash tx failed")
+	}
+
+	return &checker{
+		txe:  txe,
+		hash: txHash,
+		used: make([]bool, len(txe.Signatures)),
+	}, nil
+}
+
+func (c *checker) check(accounts []Account) (*Result, error) {
+	byAddress := map[string]Account{}
+	for _, a := range accounts {
+		byAddress[a.Address] = a
+	}
+
+	lookup := func(aid xdr.AccountId) (Account, error) {
+		address := aid.Address()
+		a, ok := byAddress[address]
+		if !ok {
+			return Account{}, errors.Wrap(ErrAccountNotFound, address)
+		}
+		return a, nil
+	}
+
+	var result Result
+
+	source, err := lookup(c.txe.Tx.SourceAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Transaction, err = c.require(source, xdr.ThresholdIndexesThresholdLow)
+	if err != nil {
+		return nil, errors.Wrap(err, "check transaction failed")
+	}
+
+	for i, op := range c.txe.Tx.Operations {
+		opSource := source
+		if op.SourceAccount != nil {
+			opSource, err = lookup(*op.SourceAccount)
+			if err != nil {
+				return nil, errors.Wrapf(err, "operation %d", i)
+			}
+		}
+
+		req, err := c.require(opSource, thresholdForOperation(op))
+		if err != nil {
+			return nil, errors.Wrapf(err, "check operation %d failed", i)
+		}
+
+		result.Operations = append(result.Operations, req)
+	}
+
+	for i, used := range c.used {
+		if !used {
+			result.Extra = append(result.Extra, i)
+		}
+	}
+
+	return &result, nil
+}
+
+// require gathers signer weight for account until the provided threshold is
+// met.  Like stellar-core, pre-authorized transaction signers are considered
+// first, then hash(x) signers and finally ed25519 signers, and each signer
+// may be matched by at most one signature.
+func (c *checker) require(
+	account Account,
+	threshold xdr.ThresholdIndexes,
+) (Requirement, error) {
+	req := Requirement{
+		Account:   account.Address,
+		Threshold: threshold,
+		Needed:    account.Thresholds.level(threshold),
+	}
+
+	var preAuth, hashX, ed25519 []Signer
+	for _, s := range account.Signers {
+		vb, err := strkey.Version(s.Key)
+		if err != nil {
+			return req, errors.Wrapf(err, "invalid signer key %q", s.Key)
+		}
+
+		switch vb {
+		case strkey.VersionByteHashTx:
+			preAuth = append(preAuth, s)
+		case strkey.VersionByteHashX:
+			hashX = append(hashX, s)
+		case strkey.VersionByteAccountID:
+			ed25519 = append(ed25519, s)
+		default:
+			return req, errors.Errorf("invalid signer key %q", s.Key)
+		}
+	}
+
+	add := func(sig int, s Signer) bool {
+		weight := s.Weight
+		if weight > MaxSignerWeight {
+			weight = MaxSignerWeight
+		}
+
+		req.Weight += weight
+		req.Matches = append(req.Matches, Match{Signature: sig, Signer: s})
+		req.Met = req.Weight >= int32(req.Needed)
+		return req.Met
+	}
+
+	for _, s := range preAuth {
+		raw := strkey.MustDecode(strkey.VersionByteHashTx, s.Key)
+		if bytes.Equal(raw, c.hash[:]) && add(-1, s) {
+			return req, nil
+		}
+	}
+
+	if c.verifyAll(hashX, c.verifyHashX, add) {
+		return req, nil
+	}
+
+	if c.verifyAll(ed25519, c.verifyEd25519, add) {
+		return req, nil
+	}
+
+	return req, nil
+}
+
+// verifyAll matches every signature of the envelope against signers, marking
+// matched signatures as used and removing matched signers from consideration.
+// It stops as soon as add reports the requirement as met.
+func (c *checker) verifyAll(
+	signers []Signer,
+	verify func(xdr.DecoratedSignature, Signer) bool,
+	add func(int, Signer) bool,
+) bool {
+	signers = append([]Signer(nil), signers...)
+
+	for i, sig := range c.txe.Signatures {
+		for j, s := range signers {
+			if !verify(sig, s) {
+				continue
+			}
+
+			c.used[i] = true
+			if add(i, s) {
+				return true
+			}
+
+			signers = append(signers[:j], signers[j+1:]...)
+			break
+		}
+	}
+
+	return false
+}
+
+func (c *checker) verifyEd25519(sig xdr.DecoratedSignature, s Signer) bool {
+	kp, err := keypair.Parse(s.Key)
+	if err != nil {
+		return false
+	}
+
+	if kp.Hint() != [4]byte(sig.Hint) {
+		return false
+	}
+
+	return kp.Verify(c.hash[:], sig.Signature) == nil
+}
+
+func (c *checker) verifyHashX(sig xdr.DecoratedSignature, s Signer) bool {
+	raw, err := strkey.Decode(strkey.VersionByteHashX, s.Key)
+	if err != nil {
+		return false
+	}
+
+	if !bytes.Equal(raw[len(raw)-4:], sig.Hint[:]) {
+		return false
+	}
+
+	x := hash.Hash(sig.Signature)
+	return bytes.Equal(raw, x[:])
+}
+
+// level returns the weight required by the provided threshold.
+func (t Thresholds) level(threshold xdr.ThresholdIndexes) uint8 {
+	switch threshold {
+	case xdr.ThresholdIndexesThresholdLow:
+		return t.Low
+	case xdr.ThresholdIndexesThresholdMed:
+		return t.Medium
+	case xdr.ThresholdIndexesThresholdHigh:
+		return t.High
+	default:
+		return 0
+	}
+}
+
+// thresholdForOperation returns the threshold stellar-core requires the
+// source account of op to meet.
+func thresholdForOperation(op xdr.Operation) xdr.ThresholdIndexes {
+	switch op.Body.Type {
+	case xdr.OperationTypeAccountMerge:
+		return xdr.ThresholdIndexesThresholdHigh
+	case xdr.OperationTypeSetOptions:
+		so := op.Body.MustSetOptionsOp()
+		if so.MasterWeight != nil || so.LowThreshold != nil ||
+			so.MedThreshold != nil || so.HighThreshold != nil ||
+			so.Signer != nil {
+			return xdr.ThresholdIndexesThresholdHigh
+		}
+		return xdr.ThresholdIndexesThresholdMed
+	case xdr.OperationTypeAllowTrust, xdr.OperationTypeInflation:
+		return xdr.ThresholdIndexesThresholdLow
+	default:
+		return xdr.ThresholdIndexesThresholdMed
+	}
+}
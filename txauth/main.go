@@ -0,0 +1,164 @@
+// Package txauth predicts whether the signatures attached to a transaction
+// envelope are sufficient to authorize it, given the signers and thresholds of
+// the source accounts involved.
+//
+// The checks mirror the algorithm stellar-core uses when applying a
+// transaction: every operation (and the transaction itself) must gather enough
+// signer weight from its source account to meet the threshold required by the
+// operation, and every signature on the envelope must have been used by at
+// least one of those checks.  A transaction that fails the former is rejected
+// with `tx_bad_auth`, one that fails the latter with `tx_bad_auth_extra`.
+package txauth
+
+import (
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// MaxSignerWeight is the largest weight a single signer contributes towards a
+// threshold.  Heavier signers are capped to this value.
+const MaxSignerWeight = 255
+
+// ErrAccountNotFound is returned by Check when the envelope uses a source
+// account whose signers were not provided.
+var ErrAccountNotFound = errors.New("source account not provided")
+
+// Account represents the signing configuration of a source account: its
+// address, every signer allowed to sign on its behalf (including the master
+// key) and its thresholds.
+type Account struct {
+	Address    string
+	Signers    []Signer
+	Thresholds Thresholds
+}
+
+// Match records that the signature at index Signature of the envelope was
+// matched to Signer.  Pre-authorized transaction signers are matched by the
+// transaction itself rather than by a signature, and use a Signature of -1.
+type Match struct {
+	Signature int
+	Signer    Signer
+}
+
+// Requirement describes the authorization requirement of either the
+// transaction itself or one of its operations, along with whether the
+// envelope's signatures satisfy it.
+type Requirement struct {
+	// Account is the address of the source account whose signers must
+	// authorize.
+	Account string
+
+	// Threshold identifies which of the account's thresholds applies.
+	Threshold xdr.ThresholdIndexes
+
+	// Needed is the weight required by the applicable threshold.
+	Needed uint8
+
+	// Weight is the signer weight gathered from the envelope's signatures.
+	Weight int32
+
+	// Met is true when the gathered weight satisfies the threshold.
+	Met bool
+
+	// Matches lists the signatures that contributed to Weight.
+	Matches []Match
+}
+
+// Result is the outcome of a call to Check.
+type Result struct {
+	// Transaction is the requirement applied to the transaction as a whole,
+	// which must be authorized by the low threshold of its source account.
+	Transaction Requirement
+
+	// Operations contains one requirement for each of the transaction's
+	// operations, in order.
+	Operations []Requirement
+
+	// Extra contains the indexes of the envelope's signatures that were not
+	// used by any requirement.
+	Extra []int
+}
+
+// Signer represents a single signer of an account.  Key is a strkey encoded
+// ed25519 public key (G...), pre-authorized transaction hash (T...) or hash(x)
+// (X...).
+type Signer struct {
+	Key    string
+	Weight int32
+}
+
+// Thresholds represents the thresholds of an account.
+type Thresholds struct {
+	Low    uint8
+	Medium uint8
+	High   uint8
+}
+
+// Check evaluates the signatures of txe, hashed for the network identified by
+// passphrase, against the provided accounts.  Every source account used by the
+// transaction or its operations must be present in accounts.
+func Check(
+	txe xdr.TransactionEnvelope,
+	passphrase string,
+	accounts ...Account,
+) (*Result, error) {
+	c, err := newChecker(txe, passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "prepare checker failed")
+	}
+
+	return c.check(accounts)
+}
+
+// NewAccount builds an Account from raw account data, such as the account and
+// signer rows of a stellar-core database: thresholds is the account's
+// thresholds in their xdr form, whose first byte is the weight of the master
+// key, and signers are the account's additional signers.
+func NewAccount(address string, thresholds xdr.Thresholds, signers ...Signer) Account {
+	result := Account{
+		Address: address,
+		Thresholds: Thresholds{
+			Low:    thresholds[xdr.ThresholdIndexesThresholdLow],
+			Medium: thresholds[xdr.ThresholdIndexesThresholdMed],
+			High:   thresholds[xdr.ThresholdIndexesThresholdHigh],
+		},
+	}
+
+	result.Signers = append(result.Signers, signers...)
+
+	master := thresholds[xdr.ThresholdIndexesThresholdMasterWeight]
+	if master > 0 {
+		result.Signers = append(result.Signers, Signer{address, int32(master)})
+	}
+
+	return result
+}
+
+// AccountFromHorizon builds an Account from an account loaded through the
+// horizon client.  Horizon includes the master key in the account's signers.
+func AccountFromHorizon(a horizon.Account) Account {
+	result := Account{
+		Address: a.AccountID,
+		Thresholds: Thresholds{
+			Low:    a.Thresholds.LowThreshold,
+			Medium: a.Thresholds.MedThreshold,
+			High:   a.Thresholds.HighThreshold,
+		},
+	}
+
+	for _, s := range a.Signers {
+		key := s.Key
+		if key == "" {
+			key = s.PublicKey
+		}
+
+		if s.Weight <= 0 {
+			continue
+		}
+
+		result.Signers = append(result.Signers, Signer{key, s.Weight})
+	}
+
+	return result
+}
@@ -0,0 +1,271 @@
+package txauth
+
+import (
+	"testing"
+
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sourceSeed = "SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"
+	otherSeed  = "SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY"
+	thirdSeed  = "SBQHO2IMYKXAYJFCWGXC7YKLJD2EGDPSK3IUDHVJ6OOTTKLSCK6Z6POM"
+	dest       = "GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA"
+)
+
+func address(t *testing.T, seed string) string {
+	kp, err := keypair.Parse(seed)
+	require.NoError(t, err)
+	return kp.Address()
+}
+
+func envelope(
+	t *testing.T,
+	muts []build.TransactionMutator,
+	sigs ...build.TransactionEnvelopeMutator,
+) (*build.TransactionBuilder, xdr.TransactionEnvelope) {
+	muts = append([]build.TransactionMutator{
+		build.SourceAccount{sourceSeed},
+		build.Sequence{1},
+		build.TestNetwork,
+	}, muts...)
+
+	tx := build.Transaction(muts...)
+	require.NoError(t, tx.Err)
+
+	txe := tx.Sign()
+	txe.Mutate(sigs...)
+	require.NoError(t, txe.Err)
+	return tx, *txe.E
+}
+
+func payment() build.TransactionMutator {
+	return build.Payment(
+		build.Destination{dest},
+		build.NativeAmount{"10"},
+	)
+}
+
+func TestCheck_SingleSigner(t *testing.T) {
+	source := NewAccount(address(t, sourceSeed), xdr.Thresholds{1, 0, 0, 0})
+
+	_, txe := envelope(t, []build.TransactionMutator{payment()}, build.Sign{sourceSeed})
+	result, err := Check(txe, network.TestNetworkPassphrase, source)
+	require.NoError(t, err)
+
+	assert.True(t, result.Sufficient())
+	assert.Empty(t, result.Extra)
+	require.Len(t, result.Operations, 1)
+	op := result.Operations[0]
+	assert.Equal(t, xdr.ThresholdIndexesThresholdMed, op.Threshold)
+	assert.EqualValues(t, 1, op.Weight)
+	assert.Equal(t, []Match{{0, Signer{source.Address, 1}}}, op.Matches)
+
+	// unsigned
+	_, txe = envelope(t, []build.TransactionMutator{payment()})
+	result, err = Check(txe, network.TestNetworkPassphrase, source)
+	require.NoError(t, err)
+	assert.False(t, result.Authorized())
+	assert.False(t, result.Transaction.Met)
+	assert.False(t, result.Operations[0].Met)
+
+	// signed for the wrong network
+	_, txe = envelope(t, []build.TransactionMutator{payment()}, build.Sign{sourceSeed})
+	result, err = Check(txe, network.PublicNetworkPassphrase, source)
+	require.NoError(t, err)
+	assert.False(t, result.Authorized())
+	assert.Equal(t, []int{0}, result.Extra)
+}
+
+func TestCheck_Multisig(t *testing.T) {
+	source := NewAccount(
+		address(t, sourceSeed),
+		xdr.Thresholds{1, 1, 2, 3},
+		Signer{address(t, otherSeed), 1},
+		Signer{address(t, thirdSeed), 2},
+	)
+
+	cases := []struct {
+		Name       string
+		Mutator    build.TransactionMutator
+		Signers    []string
+		Threshold  xdr.ThresholdIndexes
+		Authorized bool
+		Extra      []int
+	}{
+		{
+			Name:       "medium met by two signers",
+			Mutator:    payment(),
+			Signers:    []string{sourceSeed, otherSeed},
+			Threshold:  xdr.ThresholdIndexesThresholdMed,
+			Authorized: true,
+		},
+		{
+			Name:      "medium not met",
+			Mutator:   payment(),
+			Signers:   []string{otherSeed},
+			Threshold: xdr.ThresholdIndexesThresholdMed,
+		},
+		{
+			Name:       "medium met by a single heavy signer",
+			Mutator:    payment(),
+			Signers:    []string{thirdSeed},
+			Threshold:  xdr.ThresholdIndexesThresholdMed,
+			Authorized: true,
+		},
+		{
+			// like stellar-core, signatures are no longer considered once the
+			// threshold is met, leaving the later signature unused.
+			Name:       "extra signature after threshold is met",
+			Mutator:    payment(),
+			Signers:    []string{thirdSeed, sourceSeed},
+			Threshold:  xdr.ThresholdIndexesThresholdMed,
+			Authorized: true,
+			Extra:      []int{1},
+		},
+		{
+			Name:       "low threshold for allow trust",
+			Mutator:    build.AllowTrust(build.Trustor{dest}, build.AllowTrustAsset{"USD"}, build.Authorize{true}),
+			Signers:    []string{otherSeed},
+			Threshold:  xdr.ThresholdIndexesThresholdLow,
+			Authorized: true,
+		},
+		{
+			Name:      "high threshold for account merge",
+			Mutator:   build.AccountMerge(build.Destination{dest}),
+			Signers:   []string{thirdSeed},
+			Threshold: xdr.ThresholdIndexesThresholdHigh,
+		},
+		{
+			Name:       "high threshold met for signer changes",
+			Mutator:    build.AddSigner(dest, 1),
+			Signers:    []string{thirdSeed, sourceSeed},
+			Threshold:  xdr.ThresholdIndexesThresholdHigh,
+			Authorized: true,
+		},
+		{
+			Name:       "medium threshold for other set options",
+			Mutator:    build.HomeDomain("example.com"),
+			Signers:    []string{thirdSeed},
+			Threshold:  xdr.ThresholdIndexesThresholdMed,
+			Authorized: true,
+		},
+	}
+
+	for _, kase := range cases {
+		var sigs []build.TransactionEnvelopeMutator
+		for _, s := range kase.Signers {
+			sigs = append(sigs, build.Sign{s})
+		}
+
+		_, txe := envelope(t, []build.TransactionMutator{kase.Mutator}, sigs...)
+		result, err := Check(txe, network.TestNetworkPassphrase, source)
+		if !assert.NoError(t, err, "case %s", kase.Name) {
+			continue
+		}
+
+		assert.Equal(t, kase.Threshold, result.Operations[0].Threshold, "case %s", kase.Name)
+		assert.Equal(t, kase.Authorized, result.Authorized(), "case %s", kase.Name)
+		assert.Equal(t, kase.Extra, result.Extra, "case %s", kase.Name)
+	}
+}
+
+func TestCheck_OperationSource(t *testing.T) {
+	source := NewAccount(address(t, sourceSeed), xdr.Thresholds{1, 0, 0, 0})
+	other := NewAccount(address(t, otherSeed), xdr.Thresholds{1, 0, 0, 0})
+
+	op := build.Payment(
+		build.SourceAccount{otherSeed},
+		build.Destination{dest},
+		build.NativeAmount{"10"},
+	)
+	_, txe := envelope(t, []build.TransactionMutator{op}, build.Sign{sourceSeed})
+
+	_, err := Check(txe, network.TestNetworkPassphrase, source)
+	assert.Equal(t, ErrAccountNotFound, errors.Cause(err))
+
+	result, err := Check(txe, network.TestNetworkPassphrase, source, other)
+	require.NoError(t, err)
+	assert.True(t, result.Transaction.Met)
+	assert.False(t, result.Operations[0].Met)
+	assert.Equal(t, other.Address, result.Operations[0].Account)
+	assert.False(t, result.Authorized())
+
+	_, txe = envelope(t, []build.TransactionMutator{op}, build.Sign{sourceSeed}, build.Sign{otherSeed})
+	result, err = Check(txe, network.TestNetworkPassphrase, source, other)
+	require.NoError(t, err)
+	assert.True(t, result.Sufficient())
+}
+
+func TestCheck_HashX(t *testing.T) {
+	preimage := []byte("open sesame")
+	var so xdr.SetOptionsOp
+	require.NoError(t, build.AddHashXSigner(preimage, 1).MutateSetOptions(&so))
+
+	source := NewAccount(
+		address(t, sourceSeed),
+		xdr.Thresholds{0, 0, 0, 0},
+		Signer{so.Signer.Key.Address(), 1},
+	)
+
+	_, txe := envelope(t, []build.TransactionMutator{payment()}, build.SignHashX{preimage})
+	result, err := Check(txe, network.TestNetworkPassphrase, source)
+	require.NoError(t, err)
+	assert.True(t, result.Sufficient())
+
+	_, txe = envelope(t, []build.TransactionMutator{payment()}, build.SignHashX{[]byte("wrong")})
+	result, err = Check(txe, network.TestNetworkPassphrase, source)
+	require.NoError(t, err)
+	assert.False(t, result.Authorized())
+	assert.Equal(t, []int{0}, result.Extra)
+}
+
+func TestCheck_PreAuthTx(t *testing.T) {
+	tx, txe := envelope(t, []build.TransactionMutator{payment()})
+
+	var so xdr.SetOptionsOp
+	require.NoError(t, build.AddPreAuthTxSigner(tx, 1).MutateSetOptions(&so))
+
+	source := NewAccount(
+		address(t, sourceSeed),
+		xdr.Thresholds{1, 0, 0, 0},
+		Signer{so.Signer.Key.Address(), 1},
+	)
+
+	result, err := Check(txe, network.TestNetworkPassphrase, source)
+	require.NoError(t, err)
+	assert.True(t, result.Sufficient())
+	assert.Equal(t, -1, result.Operations[0].Matches[0].Signature)
+}
+
+func TestAccountFromHorizon(t *testing.T) {
+	var a horizon.Account
+	a.AccountID = address(t, sourceSeed)
+	a.Thresholds = horizon.AccountThresholds{LowThreshold: 1, MedThreshold: 2, HighThreshold: 3}
+	a.Signers = []horizon.Signer{
+		{Key: dest, Weight: 1},
+		{PublicKey: a.AccountID, Weight: 2},
+		{Key: address(t, otherSeed), Weight: 0},
+	}
+
+	account := AccountFromHorizon(a)
+	assert.Equal(t, a.AccountID, account.Address)
+	assert.Equal(t, Thresholds{1, 2, 3}, account.Thresholds)
+	assert.Equal(t, []Signer{{dest, 1}, {a.AccountID, 2}}, account.Signers)
+}
+
+func TestNewAccount(t *testing.T) {
+	account := NewAccount(dest, xdr.Thresholds{0, 1, 2, 3})
+	assert.Equal(t, Thresholds{1, 2, 3}, account.Thresholds)
+	assert.Empty(t, account.Signers, "master key with weight 0 should be omitted")
+
+	account = NewAccount(dest, xdr.Thresholds{4, 1, 2, 3}, Signer{"X", 1})
+	assert.Equal(t, []Signer{{"X", 1}, {dest, 4}}, account.Signers)
+}
@@ -0,0 +1,23 @@
+package txauth
+
+// Authorized returns true if every requirement of the transaction is met.
+func (r *Result) Authorized() bool {
+	if !r.Transaction.Met {
+		return false
+	}
+
+	for _, op := range r.Operations {
+		if !op.Met {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Sufficient returns true if the transaction is authorized and carries no
+// extra signatures, i.e. if stellar-core would neither reject it with
+// `tx_bad_auth` nor with `tx_bad_auth_extra`.
+func (r *Result) Sufficient() bool {
+	return r.Authorized() && len(r.Extra) == 0
+}
@@ -0,0 +1,45 @@
+package txcheck
+
+import (
+	"fmt"
+
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+)
+
+// Builder checks the transaction being built by `b`.  A set_options operation
+// updating more than one signer, which the builder refuses to add, is reported
+// as an `op_malformed` problem of the operation that would have followed those
+// already added.  Any other error previously recorded on the builder is
+// returned as is.
+func (c Checker) Builder(b *build.TransactionBuilder) error {
+	if b.Err == build.ErrMultipleSigners {
+		i := len(b.TX.Operations)
+		return &Error{
+			Problems:   []Problem{{Operation: i, Code: opMalformed, Reason: "more than one signer updated"}},
+			Operations: i + 1,
+		}
+	}
+	if b.Err != nil {
+		return b.Err
+	}
+
+	return c.Transaction(*b.TX)
+}
+
+// Envelope checks the transaction envelope `txe`, including the number of
+// signatures attached to it.
+func (c Checker) Envelope(txe xdr.TransactionEnvelope) error {
+	chk := c.newCheck(txe.Tx)
+
+	if len(txe.Signatures) > MaxSignatures {
+		chk.tx(TransactionMalformed, fmt.Sprintf("more than %d signatures", MaxSignatures))
+	}
+
+	return chk.run()
+}
+
+// Transaction checks the transaction `tx`.
+func (c Checker) Transaction(tx xdr.Transaction) error {
+	return c.newCheck(tx).run()
+}
@@ -0,0 +1,57 @@
+package txcheck
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/clients/horizon"
+)
+
+func (err *Error) Error() string {
+	problems := make([]string, 0, len(err.Problems))
+	for _, p := range err.Problems {
+		problems = append(problems, p.String())
+	}
+
+	return "transaction failed pre-flight checks: " + strings.Join(problems, "; ")
+}
+
+// ResultCodes returns the summary of result codes horizon would report for
+// the checked transaction.  When any problem concerns the transaction as a
+// whole, the first such problem's code is used as the transaction code.
+// Otherwise the transaction code is `tx_failed`, and each operation is
+// reported using the code of its first problem or `op_success` if it has
+// none.
+func (err *Error) ResultCodes() *horizon.TransactionResultCodes {
+	for _, p := range err.Problems {
+		if p.Operation == -1 {
+			return &horizon.TransactionResultCodes{TransactionCode: p.Code}
+		}
+	}
+
+	ops := make([]string, err.Operations)
+	for i := range ops {
+		ops[i] = opSuccess
+	}
+
+	// problems are ordered by operation, so only record the first one
+	for i := len(err.Problems) - 1; i >= 0; i-- {
+		p := err.Problems[i]
+		if p.Operation >= 0 && p.Operation < len(ops) {
+			ops[p.Operation] = p.Code
+		}
+	}
+
+	return &horizon.TransactionResultCodes{
+		TransactionCode: txFailed,
+		OperationCodes:  ops,
+	}
+}
+
+func (p Problem) String() string {
+	if p.Operation == -1 {
+		return fmt.Sprintf("%s (%s)", p.Code, p.Reason)
+	}
+
+	return fmt.Sprintf("operation %d: %s (%s)", p.Operation, p.Code, p.Reason)
+}
@@ -0,0 +1,323 @@
+package txcheck
+
+import (
+	"fmt"
+
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+)
+
+// result codes, as reported by horizon
+const (
+	txFailed              = "tx_failed"
+	txInsufficientFee     = "tx_insufficient_fee"
+	txMissingOperation    = "tx_missing_operation"
+	opSuccess             = "op_success"
+	opMalformed           = "op_malformed"
+	opBadFlags            = "op_bad_flags"
+	opUnknownFlag         = "op_unknown_flag"
+	opThresholdOutOfRange = "op_threshold_out_of_range"
+	opBadSigner           = "op_bad_signer"
+	opOfferNotFound       = "op_offer_not_found"
+	opInvalidHomeDomain   = "op_invalid_home_domain"
+)
+
+const (
+	maxWeight      = 255
+	maxHomeDomain  = 32
+	maxDataName    = 64
+	maxDataValue   = 64
+	allAccountFlag = xdr.AccountFlagsAuthRequiredFlag |
+		xdr.AccountFlagsAuthRevocableFlag |
+		xdr.AccountFlagsAuthImmutableFlag
+)
+
+// check accumulates the problems found in a single transaction.
+type check struct {
+	Checker
+	TX       xdr.Transaction
+	Problems []Problem
+}
+
+func (c Checker) newCheck(tx xdr.Transaction) *check {
+	return &check{
+		Checker: c,
+		TX:      tx,
+	}
+}
+
+func (chk *check) tx(code, reason string) {
+	chk.Problems = append(chk.Problems, Problem{
+		Operation: -1,
+		Code:      code,
+		Reason:    reason,
+	})
+}
+
+func (chk *check) op(i int, code, reason string) {
+	chk.Problems = append(chk.Problems, Problem{
+		Operation: i,
+		Code:      code,
+		Reason:    reason,
+	})
+}
+
+// run checks the transaction and returns an *Error if any problems were found.
+// Transaction level problems are reported before operation level ones.
+func (chk *check) run() error {
+	tx := chk.TX
+	ops := len(tx.Operations)
+
+	switch {
+	case ops == 0:
+		chk.tx(txMissingOperation, "no operations")
+	case ops > MaxOperations:
+		chk.tx(TransactionMalformed, fmt.Sprintf("more than %d operations", MaxOperations))
+	}
+
+	minFee := uint64(chk.BaseFee) * uint64(ops)
+	if uint64(tx.Fee) < minFee {
+		chk.tx(txInsufficientFee, fmt.Sprintf("fee %d is below the minimum of %d", tx.Fee, minFee))
+	}
+
+	if text, ok := tx.Memo.GetText(); ok && len(text) > build.MemoTextMaxLength {
+		chk.tx(TransactionMalformed, fmt.Sprintf("memo text longer than %d bytes", build.MemoTextMaxLength))
+	}
+
+	if tb := tx.TimeBounds; tb != nil && tb.MaxTime != 0 && tb.MinTime > tb.MaxTime {
+		chk.tx(TransactionMalformed, "min time is after max time")
+	}
+
+	for i, op := range tx.Operations {
+		chk.operation(i, op)
+	}
+
+	if len(chk.Problems) == 0 {
+		return nil
+	}
+
+	return &Error{Problems: chk.Problems, Operations: ops}
+}
+
+func (chk *check) operation(i int, op xdr.Operation) {
+	source := chk.TX.SourceAccount
+	if op.SourceAccount != nil {
+		source = *op.SourceAccount
+	}
+
+	switch op.Body.Type {
+	case xdr.OperationTypeCreateAccount:
+		body := op.Body.MustCreateAccountOp()
+		chk.positive(i, body.StartingBalance, "starting balance")
+		if body.Destination.Equals(source) {
+			chk.op(i, opMalformed, "account creates itself")
+		}
+	case xdr.OperationTypePayment:
+		body := op.Body.MustPaymentOp()
+		chk.positive(i, body.Amount, "amount")
+		chk.asset(i, body.Asset, "asset")
+		if body.Destination.Equals(source) {
+			chk.op(i, opMalformed, "payment to self")
+		}
+	case xdr.OperationTypePathPayment:
+		body := op.Body.MustPathPaymentOp()
+		chk.positive(i, body.SendMax, "send max")
+		chk.positive(i, body.DestAmount, "destination amount")
+		chk.asset(i, body.SendAsset, "send asset")
+		chk.asset(i, body.DestAsset, "destination asset")
+		for j, asset := range body.Path {
+			chk.asset(i, asset, fmt.Sprintf("path asset %d", j))
+		}
+	case xdr.OperationTypeManageOffer:
+		body := op.Body.MustManageOfferOp()
+		chk.offer(i, body.Selling, body.Buying, body.Amount, body.Price)
+		if body.OfferId == 0 && body.Amount == 0 {
+			// since protocol 3, stellar-core reports deleting an offer that
+			// does not exist yet as not found rather than malformed
+			chk.op(i, opOfferNotFound, "new offer has a zero amount")
+		}
+	case xdr.OperationTypeCreatePassiveOffer:
+		body := op.Body.MustCreatePassiveOfferOp()
+		chk.offer(i, body.Selling, body.Buying, body.Amount, body.Price)
+		if body.Amount == 0 {
+			chk.op(i, opOfferNotFound, "new offer has a zero amount")
+		}
+	case xdr.OperationTypeSetOptions:
+		chk.setOptions(i, source, op.Body.MustSetOptionsOp())
+	case xdr.OperationTypeChangeTrust:
+		body := op.Body.MustChangeTrustOp()
+		if body.Limit < 0 {
+			chk.op(i, opMalformed, "limit is negative")
+		}
+		if body.Line.Type == xdr.AssetTypeAssetTypeNative {
+			chk.op(i, opMalformed, "cannot trust the native asset")
+		} else {
+			chk.asset(i, body.Line, "line")
+			if lineIssuer := issuer(body.Line); lineIssuer.Equals(source) {
+				chk.op(i, opMalformed, "account trusts itself")
+			}
+		}
+	case xdr.OperationTypeAllowTrust:
+		body := op.Body.MustAllowTrustOp()
+		var code []byte
+		switch body.Asset.Type {
+		case xdr.AssetTypeAssetTypeCreditAlphanum4:
+			raw := body.Asset.MustAssetCode4()
+			code = raw[:]
+		case xdr.AssetTypeAssetTypeCreditAlphanum12:
+			raw := body.Asset.MustAssetCode12()
+			code = raw[:]
+		}
+		if code == nil || !validAssetCode(body.Asset.Type, code) {
+			chk.op(i, opMalformed, "invalid asset code")
+		}
+		if body.Trustor.Equals(source) {
+			chk.op(i, opMalformed, "account authorizes itself")
+		}
+	case xdr.OperationTypeAccountMerge:
+		if dest := op.Body.MustDestination(); dest.Equals(source) {
+			chk.op(i, opMalformed, "account merges into itself")
+		}
+	case xdr.OperationTypeManageData:
+		body := op.Body.MustManageDataOp()
+		if len(body.DataName) == 0 || len(body.DataName) > maxDataName || !printable(string(body.DataName)) {
+			chk.op(i, opMalformed, "invalid data name")
+		}
+		if body.DataValue != nil && len(*body.DataValue) > maxDataValue {
+			chk.op(i, opMalformed, fmt.Sprintf("data value longer than %d bytes", maxDataValue))
+		}
+	}
+}
+
+func (chk *check) setOptions(i int, source xdr.AccountId, body xdr.SetOptionsOp) {
+	var set, clear xdr.Uint32
+	if body.SetFlags != nil {
+		set = *body.SetFlags
+	}
+	if body.ClearFlags != nil {
+		clear = *body.ClearFlags
+	}
+
+	if (set|clear)&^xdr.Uint32(allAccountFlag) != 0 {
+		chk.op(i, opUnknownFlag, "unknown account flag")
+	}
+	if set&clear != 0 {
+		chk.op(i, opBadFlags, "flag both set and cleared")
+	}
+
+	thresholds := []struct {
+		value *xdr.Uint32
+		name  string
+	}{
+		{body.MasterWeight, "master weight"},
+		{body.LowThreshold, "low threshold"},
+		{body.MedThreshold, "medium threshold"},
+		{body.HighThreshold, "high threshold"},
+	}
+	for _, t := range thresholds {
+		if t.value != nil && *t.value > maxWeight {
+			chk.op(i, opThresholdOutOfRange, t.name+" is above 255")
+		}
+	}
+
+	if body.HomeDomain != nil {
+		domain := string(*body.HomeDomain)
+		if len(domain) > maxHomeDomain || !printable(domain) {
+			chk.op(i, opInvalidHomeDomain, "invalid home domain")
+		}
+	}
+
+	if body.Signer == nil {
+		return
+	}
+
+	if body.Signer.Weight > maxWeight {
+		chk.op(i, opThresholdOutOfRange, "signer weight is above 255")
+	}
+
+	if ed, ok := body.Signer.Key.GetEd25519(); ok && ed == source.MustEd25519() {
+		chk.op(i, opBadSigner, "signer is the master key")
+	}
+}
+
+func (chk *check) offer(i int, selling, buying xdr.Asset, amount xdr.Int64, price xdr.Price) {
+	chk.asset(i, selling, "selling asset")
+	chk.asset(i, buying, "buying asset")
+
+	if amount < 0 {
+		chk.op(i, opMalformed, "amount is negative")
+	}
+	if price.N <= 0 || price.D <= 0 {
+		chk.op(i, opMalformed, "price must be positive")
+	}
+	if selling.Equals(buying) {
+		chk.op(i, opMalformed, "selling and buying the same asset")
+	}
+}
+
+func (chk *check) positive(i int, amount xdr.Int64, name string) {
+	if amount <= 0 {
+		chk.op(i, opMalformed, name+" must be positive")
+	}
+}
+
+func (chk *check) asset(i int, asset xdr.Asset, name string) {
+	var code []byte
+	switch asset.Type {
+	case xdr.AssetTypeAssetTypeNative:
+		return
+	case xdr.AssetTypeAssetTypeCreditAlphanum4:
+		raw := asset.MustAlphaNum4().AssetCode
+		code = raw[:]
+	case xdr.AssetTypeAssetTypeCreditAlphanum12:
+		raw := asset.MustAlphaNum12().AssetCode
+		code = raw[:]
+	}
+
+	if code == nil || !validAssetCode(asset.Type, code) {
+		chk.op(i, opMalformed, "invalid "+name+" code")
+	}
+}
+
+// validAssetCode mirrors stellar-core's isAssetValid: a code is made of ascii
+// letters and digits, padded with trailing zeros, and twelve character codes
+// must have more than four characters.
+func validAssetCode(typ xdr.AssetType, code []byte) bool {
+	chars := 0
+	padding := false
+
+	for _, b := range code {
+		switch {
+		case b == 0:
+			padding = true
+		case padding:
+			return false
+		case (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9'):
+			chars++
+		default:
+			return false
+		}
+	}
+
+	if typ == xdr.AssetTypeAssetTypeCreditAlphanum12 {
+		return chars > 4
+	}
+	return chars > 0
+}
+
+// printable returns true if s only contains printable ascii characters.
+func printable(s string) bool {
+	for _, r := range []byte(s) {
+		if r < 0x20 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func issuer(asset xdr.Asset) xdr.AccountId {
+	if a, ok := asset.GetAlphaNum4(); ok {
+		return a.Issuer
+	}
+	return asset.MustAlphaNum12().Issuer
+}
@@ -0,0 +1,80 @@
+// Package txcheck performs static pre-flight checks of transactions, catching
+// mistakes that the build package lets through before the transaction is
+// signed and submitted.
+//
+// Each problem found is reported using the result code horizon would report
+// had the transaction been submitted, such as `tx_insufficient_fee` or
+// `op_malformed`.  Transactions that could not even be decoded by stellar-core,
+// such as those with more than 100 operations, are reported using the
+// `transaction_malformed` problem type horizon responds with instead.  A few
+// checks, such as payments to self, catch operations stellar-core accepts but
+// that are almost always a mistake; these are reported using the code
+// stellar-core uses for malformed operations of the same type.
+package txcheck
+
+import (
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	// DefaultBaseFee is the base fee, in stroops, charged for each operation of
+	// a transaction on the public and test networks.
+	DefaultBaseFee = 100
+
+	// MaxOperations is the largest number of operations a transaction may
+	// contain.
+	MaxOperations = 100
+
+	// MaxSignatures is the largest number of signatures an envelope may
+	// contain.
+	MaxSignatures = 20
+
+	// TransactionMalformed is the horizon problem type used when a submitted
+	// transaction cannot be decoded.
+	TransactionMalformed = "transaction_malformed"
+)
+
+// Checker checks transactions for a network whose base fee is BaseFee.
+type Checker struct {
+	BaseFee uint32
+}
+
+// Error is returned when a transaction fails one or more checks.
+type Error struct {
+	// Problems lists every problem found, in order.
+	Problems []Problem
+
+	// Operations is the number of operations in the checked transaction.
+	Operations int
+}
+
+// Problem describes a single problem found in a transaction.
+type Problem struct {
+	// Operation is the index of the operation at fault, or -1 when the problem
+	// concerns the transaction as a whole.
+	Operation int
+
+	// Code is the result code horizon would report for the problem, for
+	// example `op_malformed`.
+	Code string
+
+	// Reason is a human readable description of the problem.
+	Reason string
+}
+
+// Builder checks the transaction being built by `b` using DefaultBaseFee, as
+// described on Checker.Builder.
+func Builder(b *build.TransactionBuilder) error {
+	return Checker{BaseFee: DefaultBaseFee}.Builder(b)
+}
+
+// Envelope checks the transaction envelope `txe` using DefaultBaseFee.
+func Envelope(txe xdr.TransactionEnvelope) error {
+	return Checker{BaseFee: DefaultBaseFee}.Envelope(txe)
+}
+
+// Transaction checks the transaction `tx` using DefaultBaseFee.
+func Transaction(tx xdr.Transaction) error {
+	return Checker{BaseFee: DefaultBaseFee}.Transaction(tx)
+}
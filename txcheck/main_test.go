@@ -0,0 +1,299 @@
+package txcheck
+
+import (
+	"testing"
+
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	seed   = "SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"
+	source = "GA3FR7TVTDJAY6TN4MUX7BF4KK6SUHWIYDY7NRNUDTA4OVY3IMY7B6H5"
+	dest   = "GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA"
+)
+
+func transaction(muts ...build.TransactionMutator) *build.TransactionBuilder {
+	muts = append([]build.TransactionMutator{
+		build.SourceAccount{seed},
+		build.Sequence{1},
+		build.TestNetwork,
+	}, muts...)
+
+	return build.Transaction(muts...)
+}
+
+func payment(to, amount string) build.TransactionMutator {
+	return build.Payment(
+		build.Destination{to},
+		build.NativeAmount{amount},
+	)
+}
+
+func problems(t *testing.T, err error) []Problem {
+	require.Error(t, err)
+	require.IsType(t, &Error{}, err)
+	return err.(*Error).Problems
+}
+
+func TestBuilder_Valid(t *testing.T) {
+	tx := transaction(
+		payment(dest, "10"),
+		build.Payment(
+			build.Destination{dest},
+			build.CreditAmount{"USD", dest, "0.0000001"},
+		),
+		build.SetOptions(build.AddSigner(dest, 1), build.SetAuthRequired()),
+		build.CreateOffer(build.Rate{
+			Selling: build.NativeAsset(),
+			Buying:  build.CreditAsset("EUR", dest),
+			Price:   "0.5",
+		}, "100"),
+		build.Trust("ABCDEFGH", dest),
+	)
+	require.NoError(t, tx.Err)
+
+	assert.NoError(t, Builder(tx))
+}
+
+func TestBuilder_Err(t *testing.T) {
+	tx := transaction(build.SourceAccount{"foo"})
+	require.Error(t, tx.Err)
+
+	assert.Equal(t, tx.Err, Builder(tx))
+}
+
+func TestBuilder_MultipleSigners(t *testing.T) {
+	tx := transaction(
+		payment(dest, "1"),
+		build.SetOptions(build.AddSigner(dest, 1), build.RemoveSigner(source)),
+	)
+	require.Equal(t, build.ErrMultipleSigners, tx.Err)
+
+	err := Builder(tx)
+	ps := problems(t, err)
+	require.Len(t, ps, 1)
+	assert.Equal(t, 1, ps[0].Operation)
+	assert.Equal(t, "op_malformed", ps[0].Code)
+	assert.Equal(t, &horizon.TransactionResultCodes{
+		TransactionCode: "tx_failed",
+		OperationCodes:  []string{"op_success", "op_malformed"},
+	}, err.(*Error).ResultCodes())
+}
+
+func TestTransaction_Operations(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Op     build.TransactionMutator
+		Code   string
+		Reason string
+	}{
+		{
+			Name:   "zero amount",
+			Op:     payment(dest, "0"),
+			Code:   "op_malformed",
+			Reason: "amount must be positive",
+		},
+		{
+			Name:   "negative starting balance",
+			Op:     build.CreateAccount(build.Destination{dest}, build.NativeAmount{"-1"}),
+			Code:   "op_malformed",
+			Reason: "starting balance must be positive",
+		},
+		{
+			Name:   "payment to self",
+			Op:     payment(source, "10"),
+			Code:   "op_malformed",
+			Reason: "payment to self",
+		},
+		{
+			Name: "invalid asset code characters",
+			Op: build.Payment(
+				build.Destination{dest},
+				build.CreditAmount{"US$", dest, "10"},
+			),
+			Code:   "op_malformed",
+			Reason: "invalid asset code",
+		},
+		{
+			Name:   "five character asset code",
+			Op:     build.Trust("ABCDE", dest),
+			Code:   "",
+			Reason: "",
+		},
+		{
+			Name:   "signer weight out of range",
+			Op:     build.SetOptions(build.AddSigner(dest, 256)),
+			Code:   "op_threshold_out_of_range",
+			Reason: "signer weight is above 255",
+		},
+		{
+			Name:   "master key as signer",
+			Op:     build.SetOptions(build.AddSigner(source, 1)),
+			Code:   "op_bad_signer",
+			Reason: "signer is the master key",
+		},
+		{
+			Name:   "flag set and cleared",
+			Op:     build.SetOptions(build.SetAuthRequired(), build.ClearAuthRequired()),
+			Code:   "op_bad_flags",
+			Reason: "flag both set and cleared",
+		},
+		{
+			Name:   "account merge into self",
+			Op:     build.AccountMerge(build.Destination{source}),
+			Code:   "op_malformed",
+			Reason: "account merges into itself",
+		},
+	}
+
+	for _, kase := range cases {
+		tx := transaction(kase.Op)
+		require.NoError(t, tx.Err, kase.Name)
+
+		err := Builder(tx)
+		if kase.Code == "" {
+			assert.NoError(t, err, kase.Name)
+			continue
+		}
+
+		ps := problems(t, err)
+		if assert.Len(t, ps, 1, kase.Name) {
+			assert.Equal(t, 0, ps[0].Operation, kase.Name)
+			assert.Equal(t, kase.Code, ps[0].Code, kase.Name)
+			assert.Contains(t, ps[0].Reason, kase.Reason, kase.Name)
+		}
+	}
+}
+
+func TestTransaction_ZeroPrice(t *testing.T) {
+	tx := transaction(build.CreateOffer(build.Rate{
+		Selling: build.NativeAsset(),
+		Buying:  build.CreditAsset("EUR", dest),
+		Price:   "1",
+	}, "100"))
+	require.NoError(t, tx.Err)
+
+	// price.Parse refuses zero, but nothing stops a raw transaction using one
+	tx.TX.Operations[0].Body.ManageOfferOp.Price.N = 0
+
+	ps := problems(t, Builder(tx))
+	require.Len(t, ps, 1)
+	assert.Equal(t, "op_malformed", ps[0].Code)
+	assert.Equal(t, "price must be positive", ps[0].Reason)
+}
+
+func TestTransaction_Fee(t *testing.T) {
+	tx := transaction(payment(dest, "1"), payment(dest, "1"), build.Fee(150))
+	require.NoError(t, tx.Err)
+
+	ps := problems(t, Builder(tx))
+	require.Len(t, ps, 1)
+	assert.Equal(t, -1, ps[0].Operation)
+	assert.Equal(t, "tx_insufficient_fee", ps[0].Code)
+
+	assert.NoError(t, Checker{BaseFee: 50}.Builder(tx))
+}
+
+func TestTransaction_MalformedEnvelope(t *testing.T) {
+	var muts []build.TransactionMutator
+	for i := 0; i < MaxOperations+1; i++ {
+		muts = append(muts, build.Inflation())
+	}
+
+	tx := transaction(muts...)
+	require.NoError(t, tx.Err)
+	tx.TX.Memo, _ = xdr.NewMemo(xdr.MemoTypeMemoText, "12345678901234567890123456789")
+
+	ps := problems(t, Builder(tx))
+	require.Len(t, ps, 2)
+	assert.Equal(t, TransactionMalformed, ps[0].Code)
+	assert.Contains(t, ps[0].Reason, "operations")
+	assert.Equal(t, TransactionMalformed, ps[1].Code)
+	assert.Contains(t, ps[1].Reason, "memo")
+
+	tx = transaction()
+	ps = problems(t, Builder(tx))
+	require.Len(t, ps, 1)
+	assert.Equal(t, "tx_missing_operation", ps[0].Code)
+}
+
+func TestTransaction_SignerUpdatedTwice(t *testing.T) {
+	// stellar-core applies the operations in order, so a signer may be added
+	// and then re-weighted or removed by the same transaction
+	tx := transaction(
+		build.SetOptions(build.AddSigner(dest, 1)),
+		build.SetOptions(build.SourceAccount{dest}, build.AddSigner(source, 1)),
+		build.SetOptions(build.AddSigner(dest, 2)),
+		build.SetOptions(build.RemoveSigner(dest)),
+	)
+	require.NoError(t, tx.Err)
+	assert.NoError(t, Builder(tx))
+}
+
+func TestTransaction_ZeroAmountNewOffer(t *testing.T) {
+	rate := build.Rate{
+		Selling: build.NativeAsset(),
+		Buying:  build.CreditAsset("EUR", dest),
+		Price:   "1",
+	}
+
+	for _, op := range []build.ManageOfferBuilder{
+		build.CreateOffer(rate, "0"),
+		build.CreatePassiveOffer(rate, "0"),
+	} {
+		tx := transaction(op)
+		require.NoError(t, tx.Err)
+
+		ps := problems(t, Builder(tx))
+		require.Len(t, ps, 1)
+		assert.Equal(t, "op_offer_not_found", ps[0].Code)
+	}
+
+	// deleting an existing offer is fine
+	tx := transaction(build.DeleteOffer(rate, 42))
+	require.NoError(t, tx.Err)
+	assert.NoError(t, Builder(tx))
+}
+
+func TestEnvelope_Signatures(t *testing.T) {
+	tx := transaction(payment(dest, "1"))
+	txe := tx.Sign(seed)
+	require.NoError(t, txe.Err)
+	assert.NoError(t, Envelope(*txe.E))
+
+	for len(txe.E.Signatures) <= MaxSignatures {
+		txe.E.Signatures = append(txe.E.Signatures, txe.E.Signatures[0])
+	}
+
+	ps := problems(t, Envelope(*txe.E))
+	require.Len(t, ps, 1)
+	assert.Equal(t, TransactionMalformed, ps[0].Code)
+}
+
+func TestError_ResultCodes(t *testing.T) {
+	tx := transaction(
+		payment(dest, "1"),
+		payment(dest, "0"),
+		build.AccountMerge(build.Destination{source}),
+	)
+	require.NoError(t, tx.Err)
+
+	err := Builder(tx)
+	require.Error(t, err)
+	assert.Equal(t, &horizon.TransactionResultCodes{
+		TransactionCode: "tx_failed",
+		OperationCodes:  []string{"op_success", "op_malformed", "op_malformed"},
+	}, err.(*Error).ResultCodes())
+	assert.Contains(t, err.Error(), "operation 1: op_malformed (amount must be positive)")
+
+	tx.Mutate(build.Fee(1))
+	err = Builder(tx)
+	require.Error(t, err)
+	assert.Equal(t, &horizon.TransactionResultCodes{
+		TransactionCode: "tx_insufficient_fee",
+	}, err.(*Error).ResultCodes())
+}
@@ -0,0 +1,27 @@
+package txrep
+
+import (
+	"fmt"
+)
+
+// LineError is returned by Unmarshal when a line of the document cannot be
+// parsed.
+type LineError struct {
+	Line int
+	Msg  string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// FieldError is returned by Unmarshal when the value of a field is missing,
+// invalid or unexpected.
+type FieldError struct {
+	Field string
+	Msg   string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Msg)
+}
@@ -0,0 +1,506 @@
+package txrep
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+var (
+	accountIDType         = reflect.TypeOf(xdr.AccountId{})
+	assetType             = reflect.TypeOf(xdr.Asset{})
+	allowTrustOpAssetType = reflect.TypeOf(xdr.AllowTrustOpAsset{})
+	int64Type             = reflect.TypeOf(xdr.Int64(0))
+	signerKeyType         = reflect.TypeOf(xdr.SignerKey{})
+)
+
+type encoder struct {
+	buf bytes.Buffer
+}
+
+func (e *encoder) line(key, value string) {
+	fmt.Fprintf(&e.buf, "%s: %s\n", key, value)
+}
+
+func (e *encoder) encode(key string, v reflect.Value) error {
+	switch v.Type() {
+	case accountIDType:
+		aid := v.Interface().(xdr.AccountId)
+		e.line(key, aid.Address())
+		return nil
+	case assetType:
+		e.line(key, v.Interface().(xdr.Asset).StringCanonical())
+		return nil
+	case allowTrustOpAssetType:
+		code, err := allowTrustCode(v.Interface().(xdr.AllowTrustOpAsset))
+		if err != nil {
+			return &FieldError{Field: key, Msg: err.Error()}
+		}
+		e.line(key, code)
+		return nil
+	case int64Type:
+		e.line(key, amount.String(v.Interface().(xdr.Int64)))
+		return nil
+	case signerKeyType:
+		skey := v.Interface().(xdr.SignerKey)
+		e.line(key, skey.Address())
+		return nil
+	}
+
+	if u, ok := xdr.ReflectUnion(v); ok {
+		return e.encodeUnion(key, v, u)
+	}
+
+	if en, ok := xdr.ReflectEnum(v); ok {
+		e.line(key, en.String())
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			name := fieldKey(key, v.Type().Field(i).Name)
+			err := e.encode(name, v.Field(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr:
+		e.line(key+"._present", strconv.FormatBool(!v.IsNil()))
+		if !v.IsNil() {
+			return e.encode(key, v.Elem())
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.line(key, hex.EncodeToString(v.Bytes()))
+			return nil
+		}
+
+		e.line(key+".len", strconv.Itoa(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			err := e.encode(indexKey(key, i), v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			raw := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(raw), v)
+			e.line(key, hex.EncodeToString(raw))
+			return nil
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			err := e.encode(indexKey(key, i), v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		e.line(key, strconv.Quote(v.String()))
+	case reflect.Bool:
+		e.line(key, strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.line(key, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		e.line(key, strconv.FormatUint(v.Uint(), 10))
+	default:
+		return &FieldError{Field: key, Msg: "unsupported type " + v.Type().String()}
+	}
+
+	return nil
+}
+
+func (e *encoder) encodeUnion(key string, v reflect.Value, u xdr.Union) error {
+	sw := v.FieldByName(u.SwitchFieldName())
+	err := e.encode(fieldKey(key, u.SwitchFieldName()), sw)
+	if err != nil {
+		return err
+	}
+
+	arm, ok := xdr.UnionArm(v)
+	if !ok {
+		return &FieldError{Field: key, Msg: "invalid union switch"}
+	}
+
+	// void arm
+	if arm == "" {
+		return nil
+	}
+
+	value := v.FieldByName(arm)
+	if value.IsNil() {
+		return &FieldError{Field: fieldKey(key, arm), Msg: "union arm is not set"}
+	}
+
+	return e.encode(fieldKey(key, arm), value.Elem())
+}
+
+type decoder struct {
+	values map[string]string
+	lines  map[string]int
+	used   map[string]bool
+}
+
+func newDecoder(text string) (*decoder, error) {
+	d := &decoder{
+		values: map[string]string{},
+		lines:  map[string]int{},
+		used:   map[string]bool{},
+	}
+
+	for i, line := range strings.Split(text, "\n") {
+		lineno := i + 1
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sep := strings.Index(line, ":")
+		if sep == -1 {
+			return nil, &LineError{Line: lineno, Msg: "missing ':' separator"}
+		}
+
+		key := strings.TrimSpace(line[:sep])
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return nil, &LineError{Line: lineno, Msg: "invalid key"}
+		}
+
+		if _, dup := d.values[key]; dup {
+			return nil, &LineError{
+				Line: lineno,
+				Msg:  fmt.Sprintf("duplicate key %s, first set on line %d", key, d.lines[key]),
+			}
+		}
+
+		value, err := lineValue(strings.TrimSpace(line[sep+1:]))
+		if err != nil {
+			return nil, &LineError{Line: lineno, Msg: err.Error()}
+		}
+
+		d.values[key] = value
+		d.lines[key] = lineno
+	}
+
+	return d, nil
+}
+
+func (d *decoder) value(key string) (string, error) {
+	value, ok := d.values[key]
+	if !ok {
+		return "", &FieldError{Field: key, Msg: "missing value"}
+	}
+
+	d.used[key] = true
+	return value, nil
+}
+
+func (d *decoder) checkUnused() error {
+	// report the earliest unused line, so errors are stable across runs
+	first := ""
+	for key := range d.values {
+		if d.used[key] {
+			continue
+		}
+		if first == "" || d.lines[key] < d.lines[first] {
+			first = key
+		}
+	}
+
+	if first == "" {
+		return nil
+	}
+
+	return &LineError{Line: d.lines[first], Msg: "unexpected field " + first}
+}
+
+func (d *decoder) decode(key string, v reflect.Value) error {
+	switch v.Type() {
+	case accountIDType:
+		return d.decodeWith(key, func(value string) error {
+			return v.Addr().Interface().(*xdr.AccountId).SetAddress(value)
+		})
+	case assetType:
+		return d.decodeWith(key, func(value string) error {
+			asset, err := xdr.ParseAsset(value)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(asset))
+			return nil
+		})
+	case allowTrustOpAssetType:
+		return d.decodeWith(key, func(value string) error {
+			asset, err := parseAllowTrustCode(value)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(asset))
+			return nil
+		})
+	case int64Type:
+		return d.decodeWith(key, func(value string) error {
+			parsed, err := amount.Parse(value)
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(parsed))
+			return nil
+		})
+	case signerKeyType:
+		return d.decodeWith(key, func(value string) error {
+			return v.Addr().Interface().(*xdr.SignerKey).SetAddress(value)
+		})
+	}
+
+	if u, ok := xdr.ReflectUnion(v); ok {
+		return d.decodeUnion(key, v, u)
+	}
+
+	if _, ok := xdr.ReflectEnum(v); ok {
+		return d.decodeWith(key, func(value string) error {
+			sw, ok := xdr.EnumValue(v.Type(), value)
+			if !ok {
+				return errors.New("unknown value for " + v.Type().Name())
+			}
+			v.SetInt(int64(sw))
+			return nil
+		})
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			name := fieldKey(key, v.Type().Field(i).Name)
+			err := d.decode(name, v.Field(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr:
+		var present bool
+		err := d.decodeWith(key+"._present", func(value string) (err error) {
+			present, err = strconv.ParseBool(value)
+			return
+		})
+		if err != nil || !present {
+			return err
+		}
+
+		v.Set(reflect.New(v.Type().Elem()))
+		return d.decode(key, v.Elem())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return d.decodeWith(key, func(value string) error {
+				raw, err := hex.DecodeString(value)
+				if err != nil {
+					return err
+				}
+				v.SetBytes(raw)
+				return nil
+			})
+		}
+
+		var n int
+		err := d.decodeWith(key+".len", func(value string) (err error) {
+			n, err = strconv.Atoi(value)
+			if err == nil && (n < 0 || n > len(d.values)) {
+				err = errors.New("invalid length")
+			}
+			return
+		})
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			err := d.decode(indexKey(key, i), v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return d.decodeWith(key, func(value string) error {
+				raw, err := hex.DecodeString(value)
+				if err != nil {
+					return err
+				}
+				if len(raw) != v.Len() {
+					return fmt.Errorf("expected %d bytes, got %d", v.Len(), len(raw))
+				}
+				reflect.Copy(v, reflect.ValueOf(raw))
+				return nil
+			})
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			err := d.decode(indexKey(key, i), v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		return d.decodeWith(key, func(value string) error {
+			s, err := strconv.Unquote(value)
+			if err != nil {
+				return errors.New("invalid quoted string")
+			}
+			v.SetString(s)
+			return nil
+		})
+	case reflect.Bool:
+		return d.decodeWith(key, func(value string) error {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+			v.SetBool(b)
+			return nil
+		})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return d.decodeWith(key, func(value string) error {
+			i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+			if err != nil {
+				return err
+			}
+			v.SetInt(i)
+			return nil
+		})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return d.decodeWith(key, func(value string) error {
+			i, err := strconv.ParseUint(value, 10, v.Type().Bits())
+			if err != nil {
+				return err
+			}
+			v.SetUint(i)
+			return nil
+		})
+	default:
+		return &FieldError{Field: key, Msg: "unsupported type " + v.Type().String()}
+	}
+
+	return nil
+}
+
+func (d *decoder) decodeUnion(key string, v reflect.Value, u xdr.Union) error {
+	sw := v.FieldByName(u.SwitchFieldName())
+	err := d.decode(fieldKey(key, u.SwitchFieldName()), sw)
+	if err != nil {
+		return err
+	}
+
+	// re-read the switch from the now populated union
+	arm, ok := xdr.UnionArm(v)
+	if !ok {
+		return &FieldError{Field: key, Msg: "invalid union switch"}
+	}
+
+	// void arm
+	if arm == "" {
+		return nil
+	}
+
+	value := v.FieldByName(arm)
+	value.Set(reflect.New(value.Type().Elem()))
+	return d.decode(fieldKey(key, arm), value.Elem())
+}
+
+// decodeWith looks up the value for key and passes it to fn, wrapping any
+// error returned into a FieldError.
+func (d *decoder) decodeWith(key string, fn func(string) error) error {
+	value, err := d.value(key)
+	if err != nil {
+		return err
+	}
+
+	err = fn(value)
+	if err != nil {
+		return &FieldError{Field: key, Msg: err.Error()}
+	}
+
+	return nil
+}
+
+// allowTrustCode returns the asset code of an AllowTrustOpAsset, without the
+// trailing zero padding.
+func allowTrustCode(a xdr.AllowTrustOpAsset) (string, error) {
+	var raw []byte
+	switch a.Type {
+	case xdr.AssetTypeAssetTypeCreditAlphanum4:
+		code := a.MustAssetCode4()
+		raw = code[:]
+	case xdr.AssetTypeAssetTypeCreditAlphanum12:
+		code := a.MustAssetCode12()
+		raw = code[:]
+	default:
+		return "", errors.New("invalid asset type " + a.Type.String())
+	}
+
+	return strings.TrimRight(string(raw), "\x00"), nil
+}
+
+// parseAllowTrustCode is the inverse of allowTrustCode, choosing the asset
+// type based upon the length of the code.
+func parseAllowTrustCode(code string) (xdr.AllowTrustOpAsset, error) {
+	switch {
+	case len(code) >= 1 && len(code) <= 4:
+		var raw [4]byte
+		copy(raw[:], code)
+		return xdr.NewAllowTrustOpAsset(xdr.AssetTypeAssetTypeCreditAlphanum4, raw)
+	case len(code) >= 5 && len(code) <= 12:
+		var raw [12]byte
+		copy(raw[:], code)
+		return xdr.NewAllowTrustOpAsset(xdr.AssetTypeAssetTypeCreditAlphanum12, raw)
+	default:
+		return xdr.AllowTrustOpAsset{}, errors.New("invalid asset code length")
+	}
+}
+
+// lineValue extracts the value from the remainder of a line, dropping any
+// trailing annotation.
+func lineValue(rest string) (string, error) {
+	if !strings.HasPrefix(rest, `"`) {
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return "", nil
+		}
+		return fields[0], nil
+	}
+
+	for i := 1; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			i++
+		case '"':
+			return rest[:i+1], nil
+		}
+	}
+
+	return "", errors.New("unterminated string")
+}
+
+func fieldKey(prefix, name string) string {
+	name = strings.ToLower(name[:1]) + name[1:]
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func indexKey(prefix string, i int) string {
+	return fmt.Sprintf("%s[%d]", prefix, i)
+}
+
+func reflectValue(ptr interface{}) reflect.Value {
+	return reflect.ValueOf(ptr).Elem()
+}
@@ -0,0 +1,62 @@
+// Package txrep renders transaction envelopes into txrep, a line oriented
+// key/value text format meant to be read by humans, and parses such documents
+// back into the identical envelope.
+//
+// Each line of a txrep document holds a single value, keyed by its path within
+// the envelope, for example:
+//
+//	tx.sourceAccount: GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA
+//	tx.memo.type: MemoTypeMemoText
+//	tx.memo.text: "hello"
+//	tx.operations.len: 1
+//	tx.operations[0].body.type: OperationTypePayment
+//	tx.operations[0].body.paymentOp.amount: 100.0000000
+//
+// Accounts and signer keys are written as strkeys, assets in the canonical
+// form parsed by xdr.ParseAsset (such as "native" or "USD:GBRP...OX2H"),
+// amounts as decimal strings, enums by name, strings quoted and opaque data in
+// hex.  Optional values are preceded
+// by a `._present` line and lists by a `.len` line.
+//
+// When parsing, blank lines and lines starting with `#` are ignored, as is
+// anything following an unquoted value, allowing documents to be annotated.
+package txrep
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// Marshal renders the provided envelope as a txrep document.
+func Marshal(txe xdr.TransactionEnvelope) (string, error) {
+	var e encoder
+	err := e.encode("", reflectValue(&txe))
+	if err != nil {
+		return "", err
+	}
+
+	return e.buf.String(), nil
+}
+
+// Unmarshal parses the provided txrep document into dest.  Every value of the
+// envelope must be present in the document, and every line of the document
+// must correspond to a value of the envelope.
+func Unmarshal(text string, dest *xdr.TransactionEnvelope) error {
+	d, err := newDecoder(text)
+	if err != nil {
+		return err
+	}
+
+	var result xdr.TransactionEnvelope
+	err = d.decode("", reflectValue(&result))
+	if err != nil {
+		return err
+	}
+
+	err = d.checkUnused()
+	if err != nil {
+		return err
+	}
+
+	*dest = result
+	return nil
+}
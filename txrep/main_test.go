@@ -0,0 +1,159 @@
+package txrep
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	seed    = "SDOTALIMPAM2IV65IOZA7KZL7XWZI5BODFXTRVLIHLQZQCKK57PH5F3H"
+	address = "GA3FR7TVTDJAY6TN4MUX7BF4KK6SUHWIYDY7NRNUDTA4OVY3IMY7B6H5"
+	other   = "GAWSI2JO2CF36Z43UGMUJCDQ2IMR5B3P5TMS7XM7NUTU3JHG3YJUDQXA"
+)
+
+func testEnvelope(t *testing.T) xdr.TransactionEnvelope {
+	txe := build.Transaction(
+		build.SourceAccount{AddressOrSeed: seed},
+		build.Sequence{Sequence: 2},
+		build.TestNetwork,
+		build.MemoText{Value: "hello \"world\""},
+		build.Timebounds{MinTime: 10, MaxTime: 20},
+		build.Payment(
+			build.Destination{AddressOrSeed: other},
+			build.NativeAmount{Amount: "100"},
+		),
+		build.Payment(
+			build.SourceAccount{AddressOrSeed: other},
+			build.Destination{AddressOrSeed: address},
+			build.CreditAmount{Code: "USD", Issuer: other, Amount: "0.0000001"},
+		),
+		build.SetOptions(
+			build.HomeDomain("example.com"),
+			build.AddSigner(other, 1),
+		),
+		build.AllowTrust(
+			build.Trustor{Address: other},
+			build.AllowTrustAsset{Code: "EURT"},
+			build.Authorize{Value: true},
+		),
+		build.Inflation(),
+	).Sign(seed)
+	require.NoError(t, txe.Err)
+
+	return *txe.E
+}
+
+func TestRoundTrip(t *testing.T) {
+	txe := testEnvelope(t)
+
+	text, err := Marshal(txe)
+	require.NoError(t, err)
+
+	assert.Contains(t, text, "tx.sourceAccount: "+address+"\n")
+	assert.Contains(t, text, "tx.memo.type: MemoTypeMemoText\n")
+	assert.Contains(t, text, `tx.memo.text: "hello \"world\""`+"\n")
+	assert.Contains(t, text, "tx.timeBounds._present: true\n")
+	assert.Contains(t, text, "tx.operations.len: 5\n")
+	assert.Contains(t, text, "tx.operations[0].body.type: OperationTypePayment\n")
+	assert.Contains(t, text, "tx.operations[0].body.paymentOp.amount: 100.0000000\n")
+	assert.Contains(t, text, "tx.operations[0].body.paymentOp.asset: native\n")
+	assert.Contains(t, text, "tx.operations[1].sourceAccount._present: true\n")
+	assert.Contains(t, text, "tx.operations[1].body.paymentOp.asset: USD:"+other+"\n")
+	assert.Contains(t, text, "tx.operations[2].body.setOptionsOp.signer.key: "+other+"\n")
+	assert.Contains(t, text, "tx.operations[3].body.allowTrustOp.asset: EURT\n")
+	assert.Contains(t, text, "signatures.len: 1\n")
+
+	var parsed xdr.TransactionEnvelope
+	err = Unmarshal(text, &parsed)
+	require.NoError(t, err)
+
+	expected, err := xdr.MarshalBase64(txe)
+	require.NoError(t, err)
+	actual, err := xdr.MarshalBase64(parsed)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestUnmarshal_Annotations(t *testing.T) {
+	text, err := Marshal(testEnvelope(t))
+	require.NoError(t, err)
+
+	annotated := "# reviewed\n\n" + strings.Replace(
+		text,
+		"tx.fee: 500\n",
+		"tx.fee: 500 (5 operations)\n",
+		1,
+	)
+	require.NotEqual(t, text, annotated)
+
+	var parsed xdr.TransactionEnvelope
+	err = Unmarshal(annotated, &parsed)
+	require.NoError(t, err)
+	assert.EqualValues(t, 500, parsed.Tx.Fee)
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	text, err := Marshal(testEnvelope(t))
+	require.NoError(t, err)
+
+	cases := []struct {
+		Name     string
+		Text     string
+		Expected string
+	}{
+		{
+			Name:     "missing field",
+			Text:     strings.Replace(text, "tx.fee: 500\n", "", 1),
+			Expected: "tx.fee: missing value",
+		},
+		{
+			Name:     "unexpected field",
+			Text:     text + "tx.foo: 1\n",
+			Expected: "unexpected field tx.foo",
+		},
+		{
+			Name:     "duplicate field",
+			Text:     text + "tx.fee: 1\n",
+			Expected: "duplicate key tx.fee",
+		},
+		{
+			Name:     "missing separator",
+			Text:     "tx.fee 500\n",
+			Expected: "line 1: missing ':' separator",
+		},
+		{
+			Name: "invalid enum",
+			Text: strings.Replace(text,
+				"tx.memo.type: MemoTypeMemoText",
+				"tx.memo.type: MemoTypeMemoFoo", 1),
+			Expected: "tx.memo.type: unknown value for MemoType",
+		},
+		{
+			Name: "invalid amount",
+			Text: strings.Replace(text,
+				"paymentOp.amount: 100.0000000",
+				"paymentOp.amount: lots", 1),
+			Expected: "tx.operations[0].body.paymentOp.amount",
+		},
+		{
+			Name: "invalid account",
+			Text: strings.Replace(text,
+				"tx.sourceAccount: "+address,
+				"tx.sourceAccount: GFOO", 1),
+			Expected: "tx.sourceAccount",
+		},
+	}
+
+	for _, kase := range cases {
+		var parsed xdr.TransactionEnvelope
+		err := Unmarshal(kase.Text, &parsed)
+		if assert.Error(t, err, kase.Name) {
+			assert.Contains(t, err.Error(), kase.Expected, kase.Name)
+		}
+	}
+}
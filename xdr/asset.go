@@ -10,6 +10,105 @@ import (
 
 // This file contains helpers for working with xdr.Asset structs
 
+// AssetTypeToString maps an asset type to the name used for it by horizon and
+// the rest of the stellar ecosystem.
+var AssetTypeToString = map[AssetType]string{
+	AssetTypeAssetTypeNative:           "native",
+	AssetTypeAssetTypeCreditAlphanum4:  "credit_alphanum4",
+	AssetTypeAssetTypeCreditAlphanum12: "credit_alphanum12",
+}
+
+// StringToAssetType is the inverse of AssetTypeToString.
+var StringToAssetType = map[string]AssetType{
+	"native":            AssetTypeAssetTypeNative,
+	"credit_alphanum4":  AssetTypeAssetTypeCreditAlphanum4,
+	"credit_alphanum12": AssetTypeAssetTypeCreditAlphanum12,
+}
+
+// ParseAsset parses the canonical string form of an asset, as returned by
+// StringCanonical: either "native", or the asset code and the strkey of its
+// issuer separated by a colon, such as "USD:GBRP...OX2H".  The asset type is
+// chosen based upon the length of the code.
+func ParseAsset(s string) (Asset, error) {
+	if s == "native" {
+		return NewAsset(AssetTypeAssetTypeNative, nil)
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return Asset{}, fmt.Errorf("invalid asset %q: expected native or CODE:ISSUER", s)
+	}
+
+	code, issuer := parts[0], parts[1]
+	typ := AssetTypeAssetTypeCreditAlphanum4
+	if len(code) > 4 {
+		typ = AssetTypeAssetTypeCreditAlphanum12
+	}
+
+	return BuildAsset(AssetTypeToString[typ], code, issuer)
+}
+
+// BuildAsset creates an asset of the type named `assetType` (see
+// AssetTypeToString), whose code and issuer are `code` and the strkey
+// `issuer`.  Both `code` and `issuer` must be empty for the native asset.
+func BuildAsset(assetType, code, issuer string) (Asset, error) {
+	typ, ok := StringToAssetType[assetType]
+	if !ok {
+		return Asset{}, fmt.Errorf("invalid asset type %q", assetType)
+	}
+
+	if typ == AssetTypeAssetTypeNative {
+		if code != "" || issuer != "" {
+			return Asset{}, errors.New("native asset cannot have a code or issuer")
+		}
+		return NewAsset(typ, nil)
+	}
+
+	err := validateAssetCode(typ, code)
+	if err != nil {
+		return Asset{}, err
+	}
+
+	var aid AccountId
+	err = aid.SetAddress(issuer)
+	if err != nil {
+		return Asset{}, fmt.Errorf("invalid asset issuer %q: %s", issuer, err)
+	}
+
+	switch typ {
+	case AssetTypeAssetTypeCreditAlphanum4:
+		body := AssetAlphaNum4{Issuer: aid}
+		copy(body.AssetCode[:], code)
+		return NewAsset(typ, body)
+	default:
+		body := AssetAlphaNum12{Issuer: aid}
+		copy(body.AssetCode[:], code)
+		return NewAsset(typ, body)
+	}
+}
+
+// validateAssetCode returns an error if `code` is not a valid code for credit
+// assets of type `typ`.
+func validateAssetCode(typ AssetType, code string) error {
+	max := 4
+	if typ == AssetTypeAssetTypeCreditAlphanum12 {
+		max = 12
+	}
+
+	if len(code) == 0 || len(code) > max {
+		return fmt.Errorf("invalid asset code %q: must be between 1 and %d characters", code, max)
+	}
+
+	for _, c := range code {
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlnum {
+			return fmt.Errorf("invalid asset code %q: must be alphanumeric", code)
+		}
+	}
+
+	return nil
+}
+
 // SetCredit overwrites `a` with a credit asset using `code` and `issuer`.  The
 // asset type (CreditAlphanum4 or CreditAlphanum12) is chosen automatically
 // based upon the length of `code`.
@@ -27,7 +126,7 @@ func (a *Asset) SetCredit(code string, issuer AccountId) error {
 	case length >= 5 && length <= 12:
 		newbody := AssetAlphaNum12{Issuer: issuer}
 		copy(newbody.AssetCode[:], []byte(code)[:length])
-		typ = AssetTypeAssetTypeCreditAlphanum4
+		typ = AssetTypeAssetTypeCreditAlphanum12
 		body = newbody
 	default:
 		return errors.New("Asset code length is invalid")
@@ -64,6 +163,18 @@ func (a Asset) String() string {
 	return fmt.Sprintf("%s/%s/%s", t, c, i)
 }
 
+// StringCanonical returns the canonical string form of the asset: "native" for
+// the native asset and CODE:ISSUER for credit assets.  ParseAsset reverses it.
+func (a Asset) StringCanonical() string {
+	if a.Type == AssetTypeAssetTypeNative {
+		return "native"
+	}
+
+	var c, i string
+	a.MustExtract(new(string), &c, &i)
+	return c + ":" + i
+}
+
 // Equals returns true if `other` is equivalent to `a`
 func (a Asset) Equals(other Asset) bool {
 	if a.Type != other.Type {
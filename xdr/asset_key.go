@@ -0,0 +1,108 @@
+package xdr
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/strkey"
+)
+
+// AssetKey is a comparable representation of an Asset.  Unlike Asset, which
+// holds its credit details behind pointers, two keys for equivalent assets are
+// equal using ==, making AssetKey suitable for use as a map key.
+type AssetKey struct {
+	Type AssetType
+
+	// Code is the asset code, padded with zero bytes.  It is empty for the
+	// native asset.
+	Code [12]byte
+
+	// Issuer is the ed25519 public key of the asset's issuer.  It is empty
+	// for the native asset.
+	Issuer Uint256
+}
+
+// Key returns the comparable key for `a`.
+func (a Asset) Key() AssetKey {
+	key := AssetKey{Type: a.Type}
+
+	switch a.Type {
+	case AssetTypeAssetTypeNative:
+	case AssetTypeAssetTypeCreditAlphanum4:
+		an := a.MustAlphaNum4()
+		copy(key.Code[:], an.AssetCode[:])
+		key.Issuer = an.Issuer.MustEd25519()
+	case AssetTypeAssetTypeCreditAlphanum12:
+		an := a.MustAlphaNum12()
+		copy(key.Code[:], an.AssetCode[:])
+		key.Issuer = an.Issuer.MustEd25519()
+	default:
+		panic(fmt.Errorf("Unknown asset type: %v", a.Type))
+	}
+
+	return key
+}
+
+// Less reports whether `a` sorts before `other`.  See AssetKey.Less.
+func (a Asset) Less(other Asset) bool {
+	return a.Key().Less(other.Key())
+}
+
+// Asset returns the asset identified by `k`.
+func (k AssetKey) Asset() Asset {
+	var (
+		body interface{}
+		err  error
+	)
+
+	switch k.Type {
+	case AssetTypeAssetTypeNative:
+	case AssetTypeAssetTypeCreditAlphanum4:
+		an := AssetAlphaNum4{}
+		copy(an.AssetCode[:], k.Code[:4])
+		an.Issuer, err = NewAccountId(PublicKeyTypePublicKeyTypeEd25519, k.Issuer)
+		body = an
+	case AssetTypeAssetTypeCreditAlphanum12:
+		an := AssetAlphaNum12{}
+		copy(an.AssetCode[:], k.Code[:])
+		an.Issuer, err = NewAccountId(PublicKeyTypePublicKeyTypeEd25519, k.Issuer)
+		body = an
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	result, err := NewAsset(k.Type, body)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// String returns the canonical string form of the asset identified by `k`, as
+// Asset.StringCanonical does.
+func (k AssetKey) String() string {
+	if k.Type == AssetTypeAssetTypeNative {
+		return "native"
+	}
+
+	code := strings.TrimRight(string(k.Code[:]), "\x00")
+	issuer := strkey.MustEncode(strkey.VersionByteAccountID, k.Issuer[:])
+	return code + ":" + issuer
+}
+
+// Less reports whether `k` sorts before `other`.  Assets are ordered by type,
+// so that the native asset sorts first, then by code and finally by issuer.
+func (k AssetKey) Less(other AssetKey) bool {
+	if k.Type != other.Type {
+		return k.Type < other.Type
+	}
+
+	if c := bytes.Compare(k.Code[:], other.Code[:]); c != 0 {
+		return c < 0
+	}
+
+	return bytes.Compare(k.Issuer[:], other.Issuer[:]) < 0
+}
@@ -0,0 +1,66 @@
+package xdr_test
+
+import (
+	"sort"
+
+	. "github.com/stellar/go/xdr"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type byKey []Asset
+
+func (s byKey) Len() int           { return len(s) }
+func (s byKey) Less(i, j int) bool { return s[i].Less(s[j]) }
+func (s byKey) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+var _ = Describe("xdr.AssetKey", func() {
+	var (
+		native, usd1, usd2, eur1, long1 Asset
+	)
+
+	mustParse := func(s string) Asset {
+		asset, err := ParseAsset(s)
+		Expect(err).To(BeNil())
+		return asset
+	}
+
+	BeforeEach(func() {
+		native = mustParse("native")
+		usd1 = mustParse("USD:GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+		usd2 = mustParse("USD:GCCB23N7VU2U3JHZYSX7HKK6WBBUMHFP4GOXYOZDETXTICY6BR26EGJY")
+		eur1 = mustParse("EUR:GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+		long1 = mustParse("SCOTTBUCKS:GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	})
+
+	It("can be used as a map key", func() {
+		seen := map[AssetKey]bool{}
+		seen[usd1.Key()] = true
+
+		again := mustParse(usd1.StringCanonical())
+		Expect(seen[again.Key()]).To(BeTrue())
+		Expect(seen[usd2.Key()]).To(BeFalse())
+		Expect(seen[native.Key()]).To(BeFalse())
+	})
+
+	It("round trips through Asset", func() {
+		for _, a := range []Asset{native, usd1, long1} {
+			Expect(a.Key().Asset().Equals(a)).To(BeTrue())
+			Expect(a.Key().String()).To(Equal(a.StringCanonical()))
+		}
+	})
+
+	It("orders by type, code and then issuer", func() {
+		assets := []Asset{long1, usd2, usd1, eur1, native}
+		sort.Sort(byKey(assets))
+
+		Expect(assets[0].Equals(native)).To(BeTrue())
+		Expect(assets[1].Equals(eur1)).To(BeTrue())
+		Expect(assets[2].Equals(usd1)).To(BeTrue())
+		Expect(assets[3].Equals(usd2)).To(BeTrue())
+		Expect(assets[4].Equals(long1)).To(BeTrue())
+
+		Expect(usd1.Less(usd1)).To(BeFalse())
+	})
+})
@@ -4,6 +4,7 @@ import (
 	. "github.com/stellar/go/xdr"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -175,3 +176,59 @@ var _ = Describe("xdr.Asset#Equals()", func() {
 	})
 
 })
+
+var _ = Describe("xdr.ParseAsset()", func() {
+	const issuer = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+	DescribeTable("parses canonical assets",
+		func(in string, typ AssetType) {
+			asset, err := ParseAsset(in)
+			Expect(err).To(BeNil())
+			Expect(asset.Type).To(Equal(typ))
+			Expect(asset.StringCanonical()).To(Equal(in))
+		},
+		Entry("native", "native", AssetTypeAssetTypeNative),
+		Entry("credit_alphanum4", "USD:"+issuer, AssetTypeAssetTypeCreditAlphanum4),
+		Entry("credit_alphanum12", "SCOTTBUCKS:"+issuer, AssetTypeAssetTypeCreditAlphanum12),
+	)
+
+	DescribeTable("rejects invalid assets",
+		func(in string) {
+			_, err := ParseAsset(in)
+			Expect(err).ToNot(BeNil())
+		},
+		Entry("empty", ""),
+		Entry("type/code/issuer form", "credit_alphanum4/USD/"+issuer),
+		Entry("missing code", ":"+issuer),
+		Entry("long code", "ABCDEFGHIJKLM:"+issuer),
+		Entry("non-alphanumeric code", "US$:"+issuer),
+		Entry("bad issuer", "USD:GBAD"),
+		Entry("extra separator", "USD:"+issuer+":"),
+	)
+})
+
+var _ = Describe("xdr.BuildAsset()", func() {
+	const issuer = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+	It("honours the requested type", func() {
+		asset, err := BuildAsset("credit_alphanum12", "USD", issuer)
+		Expect(err).To(BeNil())
+		Expect(asset.Type).To(Equal(AssetTypeAssetTypeCreditAlphanum12))
+		Expect(asset.StringCanonical()).To(Equal("USD:" + issuer))
+	})
+
+	It("rejects codes too long for the type", func() {
+		_, err := BuildAsset("credit_alphanum4", "SCOTTBUCKS", issuer)
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("rejects native assets with a code", func() {
+		_, err := BuildAsset("native", "XLM", "")
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("rejects unknown types", func() {
+		_, err := BuildAsset("credit_alphanum8", "USD", issuer)
+		Expect(err).ToNot(BeNil())
+	})
+})
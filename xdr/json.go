@@ -0,0 +1,689 @@
+package xdr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/stellar/go/strkey"
+)
+
+// This file contains a reflection based JSON codec that works for every type
+// in this package.  The representation is designed to be read by humans while
+// still decoding back into identical xdr:
+//
+//   - structs are objects keyed by their field names, in lower camel case
+//   - unions are objects holding the union's discriminant and, unless the arm
+//     is void, the value of the selected arm
+//   - enums are encoded using their names, for example "OperationTypePayment"
+//   - public keys, account ids, node ids and signer keys are strkeys
+//   - assets are objects with a type and, for credit assets, a code and issuer
+//   - 64-bit integers are decimal strings, so they survive javascript
+//   - fixed length opaque data (such as hashes) is hex, variable length opaque
+//     data is base64
+//   - optional values are null when absent
+//   - strings are JSON strings, unless they are not valid UTF-8, in which
+//     case they are objects holding their base64 encoded bytes, for example
+//     {"base64": "/w=="}, so that no byte is lost
+
+// MarshalJSON returns the JSON representation of the xdr value `v`.
+func MarshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := encodeJSON(&buf, "", reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the JSON representation of an xdr value, as produced
+// by MarshalJSON, into `dest`, which must be a pointer.
+func UnmarshalJSON(data []byte, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("xdr: UnmarshalJSON requires a non-nil pointer, got %T", dest)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var raw interface{}
+	err := dec.Decode(&raw)
+	if err != nil {
+		return err
+	}
+
+	// decode into a fresh value, so dest is untouched on failure
+	result := reflect.New(v.Elem().Type())
+	err = decodeJSON("", raw, result.Elem())
+	if err != nil {
+		return err
+	}
+
+	v.Elem().Set(result.Elem())
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (e TransactionEnvelope) MarshalJSON() ([]byte, error) { return MarshalJSON(e) }
+
+// UnmarshalJSON implements json.Unmarshaler
+func (e *TransactionEnvelope) UnmarshalJSON(data []byte) error { return UnmarshalJSON(data, e) }
+
+// MarshalJSON implements json.Marshaler
+func (r TransactionResult) MarshalJSON() ([]byte, error) { return MarshalJSON(r) }
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *TransactionResult) UnmarshalJSON(data []byte) error { return UnmarshalJSON(data, r) }
+
+// MarshalJSON implements json.Marshaler
+func (m TransactionMeta) MarshalJSON() ([]byte, error) { return MarshalJSON(m) }
+
+// UnmarshalJSON implements json.Unmarshaler
+func (m *TransactionMeta) UnmarshalJSON(data []byte) error { return UnmarshalJSON(data, m) }
+
+// MarshalJSON implements json.Marshaler
+func (e LedgerEntry) MarshalJSON() ([]byte, error) { return MarshalJSON(e) }
+
+// UnmarshalJSON implements json.Unmarshaler
+func (e *LedgerEntry) UnmarshalJSON(data []byte) error { return UnmarshalJSON(data, e) }
+
+// MarshalJSON implements json.Marshaler
+func (h LedgerHeader) MarshalJSON() ([]byte, error) { return MarshalJSON(h) }
+
+// UnmarshalJSON implements json.Unmarshaler
+func (h *LedgerHeader) UnmarshalJSON(data []byte) error { return UnmarshalJSON(data, h) }
+
+var (
+	publicKeyType         = reflect.TypeOf(PublicKey{})
+	accountIDType         = reflect.TypeOf(AccountId{})
+	nodeIDType            = reflect.TypeOf(NodeId{})
+	signerKeyType         = reflect.TypeOf(SignerKey{})
+	assetType             = reflect.TypeOf(Asset{})
+	allowTrustOpAssetType = reflect.TypeOf(AllowTrustOpAsset{})
+)
+
+func encodeJSON(buf *bytes.Buffer, path string, v reflect.Value) error {
+	switch v.Type() {
+	case publicKeyType, accountIDType, nodeIDType:
+		pk := v.Convert(publicKeyType).Interface().(PublicKey)
+		key, ok := pk.GetEd25519()
+		if !ok {
+			return fmt.Errorf("xdr: %s: invalid public key type", path)
+		}
+		return encodeJSONString(buf, strkey.MustEncode(strkey.VersionByteAccountID, key[:]))
+	case signerKeyType:
+		skey := v.Interface().(SignerKey)
+		return encodeJSONString(buf, skey.Address())
+	case assetType:
+		var typ, code, issuer string
+		err := v.Interface().(Asset).Extract(&typ, &code, &issuer)
+		if err != nil {
+			return fmt.Errorf("xdr: %s: %s", path, err)
+		}
+		if typ == "" {
+			return fmt.Errorf("xdr: %s: invalid asset type", path)
+		}
+		obj := map[string]string{"type": typ}
+		if typ != "native" {
+			obj["code"] = code
+			obj["issuer"] = issuer
+		}
+		return encodeJSONObject(buf, obj)
+	case allowTrustOpAssetType:
+		a := v.Interface().(AllowTrustOpAsset)
+		obj := map[string]string{}
+		switch a.Type {
+		case AssetTypeAssetTypeCreditAlphanum4:
+			code := a.MustAssetCode4()
+			obj["type"] = "credit_alphanum4"
+			obj["code"] = strings.TrimRight(string(code[:]), "\x00")
+		case AssetTypeAssetTypeCreditAlphanum12:
+			code := a.MustAssetCode12()
+			obj["type"] = "credit_alphanum12"
+			obj["code"] = strings.TrimRight(string(code[:]), "\x00")
+		default:
+			return fmt.Errorf("xdr: %s: invalid asset type", path)
+		}
+		return encodeJSONObject(buf, obj)
+	}
+
+	if u, ok := ReflectUnion(v); ok {
+		return encodeJSONUnion(buf, path, v, u)
+	}
+
+	if e, ok := ReflectEnum(v); ok {
+		if !e.ValidEnum(int32(v.Int())) {
+			return fmt.Errorf("xdr: %s: invalid %s value %d", path, v.Type().Name(), v.Int())
+		}
+		return encodeJSONString(buf, e.String())
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		buf.WriteByte('{')
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			name := jsonFieldName(v.Type().Field(i).Name)
+			encodeJSONString(buf, name)
+			buf.WriteByte(':')
+			err := encodeJSON(buf, jsonPath(path, name), v.Field(i))
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case reflect.Ptr:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return encodeJSON(buf, path, v.Elem())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return encodeJSONString(buf, base64.StdEncoding.EncodeToString(v.Bytes()))
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			err := encodeJSON(buf, fmt.Sprintf("%s[%d]", path, i), v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			raw := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(raw), v)
+			return encodeJSONString(buf, hex.EncodeToString(raw))
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			err := encodeJSON(buf, fmt.Sprintf("%s[%d]", path, i), v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case reflect.String:
+		if !utf8.ValidString(v.String()) {
+			return encodeJSONObject(buf, map[string]string{
+				"base64": base64.StdEncoding.EncodeToString([]byte(v.String())),
+			})
+		}
+		return encodeJSONString(buf, v.String())
+	case reflect.Bool:
+		buf.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int64:
+		return encodeJSONString(buf, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint64:
+		return encodeJSONString(buf, strconv.FormatUint(v.Uint(), 10))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	default:
+		return fmt.Errorf("xdr: %s: unsupported type %s", path, v.Type())
+	}
+
+	return nil
+}
+
+func encodeJSONUnion(buf *bytes.Buffer, path string, v reflect.Value, u Union) error {
+	swName := jsonFieldName(u.SwitchFieldName())
+	sw := v.FieldByName(u.SwitchFieldName())
+
+	buf.WriteByte('{')
+	encodeJSONString(buf, swName)
+	buf.WriteByte(':')
+	err := encodeJSON(buf, jsonPath(path, swName), sw)
+	if err != nil {
+		return err
+	}
+
+	arm, ok := UnionArm(v)
+	if !ok {
+		return fmt.Errorf("xdr: %s: invalid union switch", path)
+	}
+
+	if arm != "" {
+		name := jsonFieldName(arm)
+		value := v.FieldByName(arm)
+		if value.IsNil() {
+			return fmt.Errorf("xdr: %s: union arm is not set", jsonPath(path, name))
+		}
+
+		buf.WriteByte(',')
+		encodeJSONString(buf, name)
+		buf.WriteByte(':')
+		err = encodeJSON(buf, jsonPath(path, name), value.Elem())
+		if err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte('}')
+	return nil
+}
+
+func encodeJSONString(buf *bytes.Buffer, s string) error {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	buf.Write(raw)
+	return nil
+}
+
+// encodeJSONObject writes a flat string object, with keys sorted so that the
+// output is stable.
+func encodeJSONObject(buf *bytes.Buffer, obj map[string]string) error {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	buf.Write(raw)
+	return nil
+}
+
+func decodeJSON(path string, raw interface{}, v reflect.Value) error {
+	switch v.Type() {
+	case publicKeyType, accountIDType, nodeIDType:
+		s, err := jsonString(path, raw)
+		if err != nil {
+			return err
+		}
+		var aid AccountId
+		err = aid.SetAddress(s)
+		if err != nil {
+			return fmt.Errorf("xdr: %s: %s", path, err)
+		}
+		v.Set(reflect.ValueOf(aid).Convert(v.Type()))
+		return nil
+	case signerKeyType:
+		s, err := jsonString(path, raw)
+		if err != nil {
+			return err
+		}
+		var skey SignerKey
+		err = skey.SetAddress(s)
+		if err != nil {
+			return fmt.Errorf("xdr: %s: %s", path, err)
+		}
+		v.Set(reflect.ValueOf(skey))
+		return nil
+	case assetType:
+		obj, err := jsonStringObject(path, raw)
+		if err != nil {
+			return err
+		}
+		asset, err := decodeJSONAsset(obj)
+		if err != nil {
+			return fmt.Errorf("xdr: %s: %s", path, err)
+		}
+		v.Set(reflect.ValueOf(asset))
+		return nil
+	case allowTrustOpAssetType:
+		obj, err := jsonStringObject(path, raw)
+		if err != nil {
+			return err
+		}
+		asset, err := decodeJSONAllowTrustAsset(obj)
+		if err != nil {
+			return fmt.Errorf("xdr: %s: %s", path, err)
+		}
+		v.Set(reflect.ValueOf(asset))
+		return nil
+	}
+
+	if u, ok := ReflectUnion(v); ok {
+		return decodeJSONUnion(path, raw, v, u)
+	}
+
+	if _, ok := ReflectEnum(v); ok {
+		name, err := jsonString(path, raw)
+		if err != nil {
+			return err
+		}
+		value, ok := EnumValue(v.Type(), name)
+		if !ok {
+			return fmt.Errorf("xdr: %s: unknown %s %q", path, v.Type().Name(), name)
+		}
+		v.SetInt(int64(value))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("xdr: %s: expected an object", path)
+		}
+
+		fields := map[string]bool{}
+		for i := 0; i < v.NumField(); i++ {
+			name := jsonFieldName(v.Type().Field(i).Name)
+			fields[name] = true
+
+			value, present := obj[name]
+			if !present && v.Field(i).Kind() != reflect.Ptr {
+				return fmt.Errorf("xdr: %s: missing field", jsonPath(path, name))
+			}
+
+			err := decodeJSON(jsonPath(path, name), value, v.Field(i))
+			if err != nil {
+				return err
+			}
+		}
+
+		return checkJSONFields(path, obj, fields)
+	case reflect.Ptr:
+		if raw == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		return decodeJSON(path, raw, v.Elem())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			s, err := jsonString(path, raw)
+			if err != nil {
+				return err
+			}
+			data, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return fmt.Errorf("xdr: %s: %s", path, err)
+			}
+			v.SetBytes(data)
+			return nil
+		}
+
+		arr, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("xdr: %s: expected an array", path)
+		}
+
+		if len(arr) == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+
+		v.Set(reflect.MakeSlice(v.Type(), len(arr), len(arr)))
+		for i, elem := range arr {
+			err := decodeJSON(fmt.Sprintf("%s[%d]", path, i), elem, v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			s, err := jsonString(path, raw)
+			if err != nil {
+				return err
+			}
+			data, err := hex.DecodeString(s)
+			if err != nil {
+				return fmt.Errorf("xdr: %s: %s", path, err)
+			}
+			if len(data) != v.Len() {
+				return fmt.Errorf("xdr: %s: expected %d bytes, got %d", path, v.Len(), len(data))
+			}
+			reflect.Copy(v, reflect.ValueOf(data))
+			return nil
+		}
+
+		arr, ok := raw.([]interface{})
+		if !ok || len(arr) != v.Len() {
+			return fmt.Errorf("xdr: %s: expected an array of %d elements", path, v.Len())
+		}
+
+		for i, elem := range arr {
+			err := decodeJSON(fmt.Sprintf("%s[%d]", path, i), elem, v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if obj, ok := raw.(map[string]interface{}); ok {
+			fields, err := jsonStringObject(path, obj)
+			if err != nil {
+				return err
+			}
+			encoded, ok := fields["base64"]
+			if !ok || len(fields) != 1 {
+				return fmt.Errorf("xdr: %s: expected a string or an object holding base64 data", path)
+			}
+			data, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return fmt.Errorf("xdr: %s: invalid base64: %s", path, err)
+			}
+			v.SetString(string(data))
+			return nil
+		}
+
+		s, err := jsonString(path, raw)
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("xdr: %s: expected a boolean", path)
+		}
+		v.SetBool(b)
+	case reflect.Int64:
+		s, err := jsonString(path, raw)
+		if err != nil {
+			return err
+		}
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("xdr: %s: %s", path, err)
+		}
+		v.SetInt(i)
+	case reflect.Uint64:
+		s, err := jsonString(path, raw)
+		if err != nil {
+			return err
+		}
+		i, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("xdr: %s: %s", path, err)
+		}
+		v.SetUint(i)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		n, ok := raw.(json.Number)
+		if !ok {
+			return fmt.Errorf("xdr: %s: expected a number", path)
+		}
+		i, err := strconv.ParseInt(string(n), 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("xdr: %s: %s", path, err)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		n, ok := raw.(json.Number)
+		if !ok {
+			return fmt.Errorf("xdr: %s: expected a number", path)
+		}
+		i, err := strconv.ParseUint(string(n), 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("xdr: %s: %s", path, err)
+		}
+		v.SetUint(i)
+	default:
+		return fmt.Errorf("xdr: %s: unsupported type %s", path, v.Type())
+	}
+
+	return nil
+}
+
+func decodeJSONUnion(path string, raw interface{}, v reflect.Value, u Union) error {
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("xdr: %s: expected an object", path)
+	}
+
+	swName := jsonFieldName(u.SwitchFieldName())
+	swRaw, ok := obj[swName]
+	if !ok {
+		return fmt.Errorf("xdr: %s: missing field", jsonPath(path, swName))
+	}
+
+	sw := v.FieldByName(u.SwitchFieldName())
+	err := decodeJSON(jsonPath(path, swName), swRaw, sw)
+	if err != nil {
+		return err
+	}
+
+	// re-read the union, now that its switch is populated
+	arm, ok := UnionArm(v)
+	if !ok {
+		return fmt.Errorf("xdr: %s: invalid union switch", path)
+	}
+
+	fields := map[string]bool{swName: true}
+	if arm != "" {
+		name := jsonFieldName(arm)
+		fields[name] = true
+
+		armRaw, ok := obj[name]
+		if !ok {
+			return fmt.Errorf("xdr: %s: missing field", jsonPath(path, name))
+		}
+
+		value := v.FieldByName(arm)
+		value.Set(reflect.New(value.Type().Elem()))
+		err = decodeJSON(jsonPath(path, name), armRaw, value.Elem())
+		if err != nil {
+			return err
+		}
+	}
+
+	return checkJSONFields(path, obj, fields)
+}
+
+func decodeJSONAsset(obj map[string]string) (Asset, error) {
+	switch obj["type"] {
+	case "native":
+		if len(obj) != 1 {
+			return Asset{}, fmt.Errorf("unexpected fields for native asset")
+		}
+		return NewAsset(AssetTypeAssetTypeNative, nil)
+	case "credit_alphanum4", "credit_alphanum12":
+		if len(obj) != 3 {
+			return Asset{}, fmt.Errorf("credit assets require exactly a type, code and issuer")
+		}
+
+		var issuer AccountId
+		err := issuer.SetAddress(obj["issuer"])
+		if err != nil {
+			return Asset{}, err
+		}
+
+		code := obj["code"]
+		if obj["type"] == "credit_alphanum4" {
+			if len(code) > 4 {
+				return Asset{}, fmt.Errorf("asset code too long")
+			}
+			body := AssetAlphaNum4{Issuer: issuer}
+			copy(body.AssetCode[:], code)
+			return NewAsset(AssetTypeAssetTypeCreditAlphanum4, body)
+		}
+
+		if len(code) > 12 {
+			return Asset{}, fmt.Errorf("asset code too long")
+		}
+		body := AssetAlphaNum12{Issuer: issuer}
+		copy(body.AssetCode[:], code)
+		return NewAsset(AssetTypeAssetTypeCreditAlphanum12, body)
+	default:
+		return Asset{}, fmt.Errorf("unknown asset type %q", obj["type"])
+	}
+}
+
+func decodeJSONAllowTrustAsset(obj map[string]string) (AllowTrustOpAsset, error) {
+	if len(obj) != 2 {
+		return AllowTrustOpAsset{}, fmt.Errorf("assets require exactly a type and code")
+	}
+
+	code := obj["code"]
+	switch obj["type"] {
+	case "credit_alphanum4":
+		if len(code) > 4 {
+			return AllowTrustOpAsset{}, fmt.Errorf("asset code too long")
+		}
+		var raw [4]byte
+		copy(raw[:], code)
+		return NewAllowTrustOpAsset(AssetTypeAssetTypeCreditAlphanum4, raw)
+	case "credit_alphanum12":
+		if len(code) > 12 {
+			return AllowTrustOpAsset{}, fmt.Errorf("asset code too long")
+		}
+		var raw [12]byte
+		copy(raw[:], code)
+		return NewAllowTrustOpAsset(AssetTypeAssetTypeCreditAlphanum12, raw)
+	default:
+		return AllowTrustOpAsset{}, fmt.Errorf("unknown asset type %q", obj["type"])
+	}
+}
+
+func checkJSONFields(path string, obj map[string]interface{}, fields map[string]bool) error {
+	for key := range obj {
+		if !fields[key] {
+			return fmt.Errorf("xdr: %s: unknown field", jsonPath(path, key))
+		}
+	}
+	return nil
+}
+
+func jsonString(path string, raw interface{}) (string, error) {
+	s, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("xdr: %s: expected a string", path)
+	}
+	return s, nil
+}
+
+func jsonStringObject(path string, raw interface{}) (map[string]string, error) {
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("xdr: %s: expected an object", path)
+	}
+
+	result := map[string]string{}
+	for key, value := range obj {
+		s, err := jsonString(jsonPath(path, key), value)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = s
+	}
+	return result, nil
+}
+
+func jsonFieldName(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func jsonPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
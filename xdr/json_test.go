@@ -0,0 +1,171 @@
+package xdr_test
+
+import (
+	"encoding/json"
+
+	. "github.com/stellar/go/xdr"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("xdr.MarshalJSON", func() {
+	const (
+		envelope = "AAAAAGL8HQvQkbK2HA3WVjRrKmjX00fG8sLI7m0ERwJW/AX3AAAAZAAAAAAAAAABAAAAAAAAAAAAAAABAAAAAAAAAAAAAAAA5BFB2+Hs81DQk/cAlJes5R0+3PUQaZ62NZJoKPsBWnsAAAACVAvkAAAAAAAAAAABVvwF9wAAAEC96/+BcbMflvMQfFAQTbAKGu+6BR1M6SG/KVzTJSlIY8ovSVywuthk9dOW9jm23siTiIZE0IAl84wK83gnAcEK"
+		issuer   = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	)
+
+	DescribeTable("round trips",
+		func(dest interface{}, in string) {
+			err := SafeUnmarshalBase64(in, dest)
+			Expect(err).To(BeNil())
+
+			js, err := MarshalJSON(dest)
+			Expect(err).To(BeNil())
+
+			err = UnmarshalJSON(js, dest)
+			Expect(err).To(BeNil())
+
+			out, err := MarshalBase64(dest)
+			Expect(err).To(BeNil())
+			Expect(out).To(Equal(in))
+		},
+		Entry("LedgerEntry", &LedgerEntry{},
+			"AAAAAQAAAAAAAAAAYvwdC9CRsrYcDdZWNGsqaNfTR8bywsjubQRHAlb8BfcN4Lazp2QAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAEAAAAAAAAAAAAAAAAAAAA="),
+		Entry("LedgerEntryChanges", &LedgerEntryChanges{},
+			"AAAAAgAAAAMAAAABAAAAAAAAAABi/B0L0JGythwN1lY0aypo19NHxvLCyO5tBEcCVvwF9w3gtrOnZAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAAAAAAEAAAACAAAAAAAAAABi/B0L0JGythwN1lY0aypo19NHxvLCyO5tBEcCVvwF9w3gtrOnY/+cAAAAAAAAAAEAAAAAAAAAAAAAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAA=="),
+		Entry("LedgerHeader", &LedgerHeader{},
+			"AAAAAWPZj1Nu5o0bJ7W4nyOvUxG3Vpok+vFAOtC1K2M7B76ZlmEdOpVCM5HLr9FNj55qa6w2HKMtqTPFLvG8yPU/aAoAAAAAVmX5PQAAAAIAAAAIAAAAAQAAAAEAAAAIAAAAAwAAADIAAAAARUAVxJm1lDMwwqujKcyQzs97F/AETiCgQPrw63wqaPGOtj0VqejCRGn8A4KwJni7nqeau/0Ehh/Gk8yEDm7nHgAAAAIN4Lazp2QAAAAAAAAAAAEsAAAAAAAAAAAAAAAAAAAAZAX14QAAAAAyAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"),
+		Entry("ScpEnvelope", &ScpEnvelope{},
+			"AAAAAHrhN3GHEnrzTWJhFpClc59zx+BDb5xg65XqG+z5jnC7AAAAAAAAAAMAAAACAAAAAQAAADC/LifyiYybRvcg7+v7J44d4hsQc5Zc16IYRoo1xslnCAAAAABWZfk+AAAAAAAAAAAAAAABebIw2H2gD0qa+oOpLf5MdO3oYdixAJ2WXGefyG5JefMAAABAvTupFluE8rWgS3FR8nUi34+ya58L+Lv4KwYBeCxaibmjuqjlYL7EnIYORmAWVQPYHoviKOIidnB6JHfWXkZ+BQ=="),
+		Entry("TransactionEnvelope", &TransactionEnvelope{}, envelope),
+		Entry("TransactionMeta", &TransactionMeta{},
+			"AAAAAAAAAAEAAAACAAAAAAAAAAIAAAAAAAAAAOQRQdvh7PNQ0JP3AJSXrOUdPtz1EGmetjWSaCj7AVp7AAAAAlQL5AAAAAACAAAAAAAAAAAAAAAAAAAAAAAAAAABAAAAAAAAAAAAAAAAAAAAAAAAAQAAAAIAAAAAAAAAAGL8HQvQkbK2HA3WVjRrKmjX00fG8sLI7m0ERwJW/AX3DeC2sVNYGtQAAAAAAAAAAwAAAAAAAAAAAAAAAAAAAAABAAAAAAAAAAAAAAAAAAAA"),
+		Entry("TransactionResult", &TransactionResult{},
+			"AAAAAAAAAGQAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAAA="),
+	)
+
+	It("uses readable names, strkeys and strings for 64-bit values", func() {
+		var txe TransactionEnvelope
+		err := SafeUnmarshalBase64(envelope, &txe)
+		Expect(err).To(BeNil())
+
+		js, err := MarshalJSON(txe)
+		Expect(err).To(BeNil())
+
+		var parsed struct {
+			Tx struct {
+				SourceAccount string
+				SeqNum        string
+				Memo          map[string]interface{}
+				Operations    []struct {
+					Body struct {
+						Type            string
+						CreateAccountOp map[string]interface{}
+					}
+				}
+			}
+		}
+		err = json.Unmarshal(js, &parsed)
+		Expect(err).To(BeNil())
+
+		Expect(parsed.Tx.SourceAccount).To(Equal(txe.Tx.SourceAccount.Address()))
+		Expect(parsed.Tx.SeqNum).To(Equal("1"))
+		Expect(parsed.Tx.Memo).To(Equal(map[string]interface{}{"type": "MemoTypeMemoNone"}))
+		Expect(parsed.Tx.Operations).To(HaveLen(1))
+		Expect(parsed.Tx.Operations[0].Body.Type).To(Equal("OperationTypeCreateAccount"))
+		Expect(parsed.Tx.Operations[0].Body.CreateAccountOp).To(HaveKeyWithValue("startingBalance", "10000000000"))
+	})
+
+	It("encodes assets as code and issuer", func() {
+		an := AssetAlphaNum4{}
+		err := an.Issuer.SetAddress(issuer)
+		Expect(err).To(BeNil())
+		copy(an.AssetCode[:], "USD")
+
+		asset, err := NewAsset(AssetTypeAssetTypeCreditAlphanum4, an)
+		Expect(err).To(BeNil())
+
+		js, err := MarshalJSON(asset)
+		Expect(err).To(BeNil())
+		Expect(string(js)).To(MatchJSON(`{"type":"credit_alphanum4","code":"USD","issuer":"` + issuer + `"}`))
+
+		var decoded Asset
+		err = UnmarshalJSON(js, &decoded)
+		Expect(err).To(BeNil())
+		Expect(decoded.Equals(asset)).To(BeTrue())
+
+		native, err := NewAsset(AssetTypeAssetTypeNative, nil)
+		Expect(err).To(BeNil())
+
+		js, err = MarshalJSON(native)
+		Expect(err).To(BeNil())
+		Expect(string(js)).To(MatchJSON(`{"type":"native"}`))
+	})
+
+	It("round trips strings that are not valid UTF-8", func() {
+		memo, err := NewMemo(MemoTypeMemoText, "caf\xe9 \xff")
+		Expect(err).To(BeNil())
+
+		js, err := MarshalJSON(memo)
+		Expect(err).To(BeNil())
+		Expect(string(js)).To(MatchJSON(`{"type":"MemoTypeMemoText","text":{"base64":"Y2Fm6SD/"}}`))
+
+		var decoded Memo
+		err = UnmarshalJSON(js, &decoded)
+		Expect(err).To(BeNil())
+		Expect(decoded.MustText()).To(Equal("caf\xe9 \xff"))
+
+		// valid strings are still plain JSON strings
+		memo, err = NewMemo(MemoTypeMemoText, "café")
+		Expect(err).To(BeNil())
+		js, err = MarshalJSON(memo)
+		Expect(err).To(BeNil())
+		Expect(string(js)).To(MatchJSON(`{"type":"MemoTypeMemoText","text":"café"}`))
+	})
+
+	It("is used by encoding/json", func() {
+		var txe TransactionEnvelope
+		err := SafeUnmarshalBase64(envelope, &txe)
+		Expect(err).To(BeNil())
+
+		expected, err := MarshalJSON(txe)
+		Expect(err).To(BeNil())
+
+		js, err := json.Marshal(map[string]interface{}{"envelope": txe})
+		Expect(err).To(BeNil())
+		Expect(string(js)).To(MatchJSON(`{"envelope":` + string(expected) + `}`))
+
+		var decoded struct{ Envelope TransactionEnvelope }
+		err = json.Unmarshal(js, &decoded)
+		Expect(err).To(BeNil())
+		Expect(decoded.Envelope).To(Equal(txe))
+	})
+})
+
+var _ = Describe("xdr.UnmarshalJSON", func() {
+	DescribeTable("rejects invalid input",
+		func(in string, msg string) {
+			var result TransactionResult
+			err := UnmarshalJSON([]byte(in), &result)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(msg))
+		},
+		Entry("unknown enum name",
+			`{"feeCharged":"100","result":{"code":"TransactionResultCodeTxAwesome"},"ext":{"v":0}}`,
+			"result.code"),
+		Entry("unknown field",
+			`{"feeCharged":"100","result":{"code":"TransactionResultCodeTxBadSeq"},"ext":{"v":0},"colour":"blue"}`,
+			"colour: unknown field"),
+		Entry("missing field",
+			`{"result":{"code":"TransactionResultCodeTxBadSeq"},"ext":{"v":0}}`,
+			"feeCharged: missing field"),
+		Entry("missing union arm",
+			`{"feeCharged":"100","result":{"code":"TransactionResultCodeTxSuccess"},"ext":{"v":0}}`,
+			"result.results: missing field"),
+		Entry("number for 64-bit value",
+			`{"feeCharged":100,"result":{"code":"TransactionResultCodeTxBadSeq"},"ext":{"v":0}}`,
+			"feeCharged: expected a string"),
+	)
+})
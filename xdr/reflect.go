@@ -0,0 +1,90 @@
+package xdr
+
+import (
+	"reflect"
+	"sync"
+)
+
+// This file contains helpers for codecs that walk xdr values using
+// reflection, such as the JSON codec of this package and txrep.
+
+// Union is implemented by every generated xdr union.
+type Union interface {
+	SwitchFieldName() string
+	ArmForSwitch(int32) (string, bool)
+}
+
+// Enum is implemented by every generated xdr enum.
+type Enum interface {
+	ValidEnum(int32) bool
+	String() string
+}
+
+// enumSearchRange bounds the values considered when looking up an enum value
+// by name.  Every enum in this package falls within it.
+const enumSearchRange = 1024
+
+var (
+	enumNames   = map[reflect.Type]map[string]int32{}
+	enumNamesMu sync.Mutex
+)
+
+// ReflectUnion returns the union held by `v`, if `v` holds one.
+func ReflectUnion(v reflect.Value) (Union, bool) {
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	u, ok := v.Interface().(Union)
+	return u, ok
+}
+
+// ReflectEnum returns the enum held by `v`, if `v` holds one.
+func ReflectEnum(v reflect.Value) (Enum, bool) {
+	if v.Kind() != reflect.Int32 {
+		return nil, false
+	}
+
+	e, ok := v.Interface().(Enum)
+	return e, ok
+}
+
+// UnionArm returns the name of the arm of the union `v` selected by the
+// current value of its switch, which is empty for void arms.  ok is false
+// when the switch does not select any arm.  Decoders call it once they have
+// set the switch, so that the arm can be populated.
+func UnionArm(v reflect.Value) (arm string, ok bool) {
+	u := v.Interface().(Union)
+	sw := v.FieldByName(u.SwitchFieldName())
+
+	switch sw.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return u.ArmForSwitch(int32(sw.Uint()))
+	default:
+		return u.ArmForSwitch(int32(sw.Int()))
+	}
+}
+
+// EnumValue finds the value of the enum type `t` whose name, as returned by
+// its String method, is `name`.
+func EnumValue(t reflect.Type, name string) (int32, bool) {
+	enumNamesMu.Lock()
+	defer enumNamesMu.Unlock()
+
+	names, ok := enumNames[t]
+	if !ok {
+		names = map[string]int32{}
+		v := reflect.New(t).Elem()
+		for i := int32(-enumSearchRange); i <= enumSearchRange; i++ {
+			v.SetInt(int64(i))
+			e := v.Interface().(Enum)
+			if e.ValidEnum(i) {
+				names[e.String()] = i
+			}
+		}
+		enumNames[t] = names
+	}
+
+	value, ok := names[name]
+	return value, ok
+}